@@ -1,4 +1,5 @@
-// Package cobra provides a CommandAdapter implementation for spf13/cobra.
+// Package cobra provides a CommandAdapter implementation for spf13/cobra,
+// and ready-made commands such as NewIDCommand.
 package cobra
 
 import (
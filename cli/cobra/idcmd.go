@@ -0,0 +1,337 @@
+package cobra
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/chinayin/gox/idgen/snowflake"
+	"github.com/spf13/cobra"
+)
+
+// idEncoding Snowflake ID 的一种文本编码
+type idEncoding struct {
+	name   string
+	encode func(snowflake.ID) string
+	parse  func(string) (snowflake.ID, error)
+}
+
+// idEncodings 支持的编码，按自动识别的优先级排列
+var idEncodings = []idEncoding{
+	{name: "decimal", encode: snowflake.ID.String, parse: snowflake.ParseString},
+	{name: "base58", encode: snowflake.ID.Base58, parse: func(s string) (snowflake.ID, error) { return snowflake.ParseBase58([]byte(s)) }},
+	{name: "base32", encode: snowflake.ID.Base32, parse: func(s string) (snowflake.ID, error) { return snowflake.ParseBase32([]byte(s)) }},
+	{name: "base36", encode: snowflake.ID.Base36, parse: snowflake.ParseBase36},
+	{name: "base64", encode: snowflake.ID.Base64, parse: snowflake.ParseBase64},
+	{name: "base2", encode: snowflake.ID.Base2, parse: snowflake.ParseBase2},
+	{name: "base64url", encode: snowflake.ID.Base64URL, parse: snowflake.ParseBase64URL},
+	{name: "crockford32", encode: snowflake.ID.Crockford32, parse: snowflake.ParseCrockford32},
+	{name: "sortable58", encode: snowflake.ID.SortableBase58, parse: snowflake.ParseSortableBase58},
+	{name: "sortable62", encode: snowflake.ID.SortableBase62, parse: snowflake.ParseSortableBase62},
+}
+
+// autoEncodings 自动识别时尝试的编码
+var autoEncodings = []string{"decimal", "base58", "base32", "base36", "base64"}
+
+// encodingNames 返回全部编码名称，用于帮助信息
+func encodingNames() string {
+	names := make([]string, len(idEncodings))
+	for i, e := range idEncodings {
+		names[i] = e.name
+	}
+	return strings.Join(names, ", ")
+}
+
+// lookupEncoding 按名称查找编码
+func lookupEncoding(name string) (idEncoding, error) {
+	for _, e := range idEncodings {
+		if e.name == name {
+			return e, nil
+		}
+	}
+	return idEncoding{}, fmt.Errorf("unknown encoding %q (supported: auto, %s)", name, encodingNames())
+}
+
+// idCandidate ID 的一种解读
+type idCandidate struct {
+	id       snowflake.ID
+	encoding string
+}
+
+// parseIDCandidates 按指定编码解析 ID
+//
+// auto 时尝试 autoEncodings 中的全部编码，返回解析成功且重新编码后与输入一致的所有解读，
+// 都不一致时（如大写的 base36）返回所有解析成功的解读；
+// 十进制是 ID 的规范形式，能按十进制解析的输入只作十进制解读
+func parseIDCandidates(s, encoding string) ([]idCandidate, error) {
+	if encoding != "auto" {
+		e, err := lookupEncoding(encoding)
+		if err != nil {
+			return nil, err
+		}
+		id, err := e.parse(s)
+		if err != nil {
+			return nil, fmt.Errorf("parse %q as %s: %w", s, e.name, err)
+		}
+		return []idCandidate{{id: id, encoding: e.name}}, nil
+	}
+
+	var exact, loose []idCandidate
+	for _, name := range autoEncodings {
+		e, _ := lookupEncoding(name)
+		id, err := e.parse(s)
+		if err != nil {
+			continue
+		}
+		c := idCandidate{id: id, encoding: e.name}
+		if e.encode(id) != s {
+			loose = append(loose, c)
+			continue
+		}
+		if e.name == "decimal" {
+			return []idCandidate{c}, nil
+		}
+		exact = append(exact, c)
+	}
+	switch {
+	case len(exact) > 0:
+		return exact, nil
+	case len(loose) > 0:
+		return loose, nil
+	default:
+		return nil, fmt.Errorf("cannot detect the encoding of %q; use --encoding", s)
+	}
+}
+
+// parseID 按指定编码解析 ID 并返回所用的编码；auto 时存在多种解读则返回错误并列出全部解读
+func parseID(s, encoding string) (snowflake.ID, string, error) {
+	candidates, err := parseIDCandidates(s, encoding)
+	if err != nil {
+		return 0, "", err
+	}
+	if len(candidates) > 1 {
+		return 0, "", fmt.Errorf("ambiguous ID %q: %s; use --encoding", s, describeCandidates(candidates))
+	}
+	return candidates[0].id, candidates[0].encoding, nil
+}
+
+// describeCandidates 列出各解读的编码与十进制值
+func describeCandidates(candidates []idCandidate) string {
+	parts := make([]string, len(candidates))
+	for i, c := range candidates {
+		parts[i] = c.encoding + " = " + c.id.String()
+	}
+	return "matches " + strings.Join(parts, ", ")
+}
+
+// layoutFlags ID 布局相关的参数
+type layoutFlags struct {
+	epoch    string
+	nodeBits uint8
+	stepBits uint8
+	signBit  bool
+}
+
+// register 将布局参数注册为持久参数
+func (f *layoutFlags) register(cmd *cobra.Command) {
+	defaults := snowflake.DefaultLayout()
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&f.epoch, "epoch", strconv.FormatInt(defaults.Epoch, 10), "epoch in unix milliseconds or RFC 3339")
+	flags.Uint8Var(&f.nodeBits, "node-bits", defaults.NodeBits, "number of node bits")
+	flags.Uint8Var(&f.stepBits, "step-bits", defaults.StepBits, "number of step bits")
+	flags.BoolVar(&f.signBit, "sign-bit", defaults.UseSignBit, "let the timestamp use the sign bit")
+}
+
+// layout 根据参数构造并校验布局
+func (f *layoutFlags) layout() (snowflake.Layout, error) {
+	epoch, err := strconv.ParseInt(f.epoch, 10, 64)
+	if err != nil {
+		t, terr := time.Parse(time.RFC3339, f.epoch)
+		if terr != nil {
+			return snowflake.Layout{}, fmt.Errorf("invalid --epoch %q: want unix milliseconds or RFC 3339", f.epoch)
+		}
+		epoch = t.UnixMilli()
+	}
+	l := snowflake.Layout{Epoch: epoch, NodeBits: f.nodeBits, StepBits: f.stepBits, UseSignBit: f.signBit}
+	if err := l.Validate(); err != nil {
+		return snowflake.Layout{}, err
+	}
+	return l, nil
+}
+
+// NewIDCommand 创建 Snowflake ID 调试命令，可挂载到任意 cobra 应用：
+//
+//	rootCmd.AddCommand(clicobra.NewIDCommand())
+//
+// 子命令：
+//   - inspect：解析 ID，输出编码、UTC 与本地时间、节点和序列号；自动识别有多种解读时全部输出
+//   - generate：按布局批量生成 ID，用于测试数据
+//   - convert：在各编码之间转换；自动识别有多种解读时报错，需通过 --from 指定编码
+//
+// 布局通过 --epoch、--node-bits、--step-bits、--sign-bit 指定，默认与
+// snowflake 包的全局配置一致。
+func NewIDCommand() *cobra.Command {
+	var lf layoutFlags
+	cmd := &cobra.Command{
+		Use:   "id",
+		Short: "Inspect, generate and convert snowflake IDs",
+	}
+	lf.register(cmd)
+	cmd.AddCommand(newIDInspectCommand(&lf), newIDGenerateCommand(&lf), newIDConvertCommand())
+	return cmd
+}
+
+// newIDInspectCommand 创建 inspect 子命令
+func newIDInspectCommand(lf *layoutFlags) *cobra.Command {
+	var encoding string
+	cmd := &cobra.Command{
+		Use:   "inspect <id>...",
+		Short: "Decode IDs into time, node and step",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			layout, err := lf.layout()
+			if err != nil {
+				return err
+			}
+			w := cmd.OutOrStdout()
+			first := true
+			for _, arg := range args {
+				candidates, err := parseIDCandidates(arg, encoding)
+				if err != nil {
+					return err
+				}
+				// 存在多种解读时全部输出，由使用者结合时间与节点判断
+				if len(candidates) > 1 {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "warning: ambiguous ID %q %s; use --encoding to pick one\n",
+						arg, describeCandidates(candidates))
+				}
+				for _, c := range candidates {
+					if !first {
+						_, _ = fmt.Fprintln(w)
+					}
+					first = false
+					writeInspect(w, c.id, c.encoding, layout)
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVarP(&encoding, "encoding", "e", "auto", "input encoding: auto, "+encodingNames())
+	return cmd
+}
+
+// writeInspect 输出单个 ID 的解析结果
+func writeInspect(w io.Writer, id snowflake.ID, encoding string, layout snowflake.Layout) {
+	parts := layout.Decode(id)
+	t := time.UnixMilli(parts.Time)
+	rows := [][2]string{
+		{"ID", id.String()},
+		{"Encoding", encoding},
+		{"Time (UTC)", t.UTC().Format(time.RFC3339Nano)},
+		{"Time (Local)", t.Local().Format(time.RFC3339Nano)},
+		{"Unix ms", strconv.FormatInt(parts.Time, 10)},
+		{"Node", strconv.FormatInt(parts.Node, 10)},
+		{"Step", strconv.FormatInt(parts.Step, 10)},
+	}
+	for _, r := range rows {
+		_, _ = fmt.Fprintf(w, "%-14s%s\n", r[0]+":", r[1])
+	}
+}
+
+// newIDGenerateCommand 创建 generate 子命令
+func newIDGenerateCommand(lf *layoutFlags) *cobra.Command {
+	var (
+		count    int
+		node     int64
+		encoding string
+	)
+	cmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Generate IDs in bulk, one per line",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			layout, err := lf.layout()
+			if err != nil {
+				return err
+			}
+			e, err := lookupEncoding(encoding)
+			if err != nil {
+				return err
+			}
+			if count < 1 {
+				return fmt.Errorf("invalid --count %d: must be positive", count)
+			}
+			n, err := snowflake.NewNodeWithLayout(node, layout)
+			if err != nil {
+				return err
+			}
+
+			var b strings.Builder
+			for _, id := range n.GenerateN(count) {
+				b.WriteString(e.encode(id))
+				b.WriteByte('\n')
+			}
+			_, err = io.WriteString(cmd.OutOrStdout(), b.String())
+			return err
+		},
+	}
+	cmd.Flags().IntVarP(&count, "count", "n", 1, "number of IDs to generate")
+	cmd.Flags().Int64Var(&node, "node", 1, "node number")
+	cmd.Flags().StringVarP(&encoding, "encoding", "e", "decimal", "output encoding: "+encodingNames())
+	return cmd
+}
+
+// newIDConvertCommand 创建 convert 子命令
+func newIDConvertCommand() *cobra.Command {
+	var from, to string
+	cmd := &cobra.Command{
+		Use:   "convert <id>...",
+		Short: "Convert IDs between encodings",
+		Args:  cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			w := cmd.OutOrStdout()
+			if to == "all" {
+				for i, arg := range args {
+					id, _, err := parseID(arg, from)
+					if err != nil {
+						return err
+					}
+					if i > 0 {
+						_, _ = fmt.Fprintln(w)
+					}
+					writeAllEncodings(w, id)
+				}
+				return nil
+			}
+
+			e, err := lookupEncoding(to)
+			if err != nil {
+				return err
+			}
+			for _, arg := range args {
+				id, _, err := parseID(arg, from)
+				if err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintln(w, e.encode(id))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().StringVar(&from, "from", "auto", "input encoding: auto, "+encodingNames())
+	cmd.Flags().StringVar(&to, "to", "all", "output encoding: all, "+encodingNames())
+	return cmd
+}
+
+// writeAllEncodings 按名称顺序输出 ID 的全部编码
+func writeAllEncodings(w io.Writer, id snowflake.ID) {
+	encs := make([]idEncoding, len(idEncodings))
+	copy(encs, idEncodings)
+	sort.Slice(encs, func(i, j int) bool { return encs[i].name < encs[j].name })
+	for _, e := range encs {
+		_, _ = fmt.Fprintf(w, "%-14s%s\n", e.name+":", e.encode(id))
+	}
+}
@@ -0,0 +1,186 @@
+package cobra
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/chinayin/gox/idgen/snowflake"
+	"github.com/spf13/cobra"
+)
+
+// runIDCommand 将 ID 命令挂载到根命令下执行并返回输出
+func runIDCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	root := &cobra.Command{Use: "app", SilenceUsage: true, SilenceErrors: true}
+	root.AddCommand(NewIDCommand())
+
+	var out bytes.Buffer
+	root.SetOut(&out)
+	root.SetErr(&out)
+	root.SetArgs(append([]string{"id"}, args...))
+	err := root.Execute()
+	return out.String(), err
+}
+
+func TestIDCommand_Inspect(t *testing.T) {
+	// 默认布局：时间 1700000000000，节点 5，序列号 7
+	id := snowflake.ID((1700000000000-snowflake.Epoch)<<22 | 5<<12 | 7)
+
+	for _, arg := range []string{id.String(), id.Base58(), id.Base32()} {
+		out, err := runIDCommand(t, "inspect", arg)
+		if err != nil {
+			t.Fatalf("inspect %s error = %v", arg, err)
+		}
+		for _, want := range []string{
+			"ID:           " + id.String(),
+			"Time (UTC):   2023-11-14T22:13:20Z",
+			"Time (Local):",
+			"Unix ms:      1700000000000",
+			"Node:         5",
+			"Step:         7",
+		} {
+			if !strings.Contains(out, want) {
+				t.Errorf("inspect %s output missing %q:\n%s", arg, want, out)
+			}
+		}
+	}
+
+	out, err := runIDCommand(t, "inspect", "--encoding", "crockford32", id.Crockford32())
+	if err != nil || !strings.Contains(out, "Encoding:     crockford32") {
+		t.Errorf("inspect --encoding crockford32 = %q, %v", out, err)
+	}
+}
+
+func TestIDCommand_InspectLayout(t *testing.T) {
+	layout := snowflake.Layout{Epoch: 1704067200000, NodeBits: 16, StepBits: 6}
+	node, err := snowflake.NewNodeWithLayout(42, layout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := node.Generate()
+
+	out, err := runIDCommand(t, "--epoch", "2024-01-01T00:00:00Z", "--node-bits", "16", "--step-bits", "6", "inspect", id.String())
+	if err != nil {
+		t.Fatalf("inspect error = %v", err)
+	}
+	if !strings.Contains(out, "Node:         42") {
+		t.Errorf("inspect with layout output missing node 42:\n%s", out)
+	}
+
+	if _, err := runIDCommand(t, "--node-bits", "20", "inspect", id.String()); err == nil {
+		t.Error("inspect should reject an invalid layout")
+	}
+	if _, err := runIDCommand(t, "--epoch", "yesterday", "inspect", id.String()); err == nil {
+		t.Error("inspect should reject an invalid epoch")
+	}
+}
+
+func TestIDCommand_AutoEncoding(t *testing.T) {
+	// 节点 7 的 ID：base36 形式同时是合法的 base32，base58 形式含大写字母只有一种解读
+	id := snowflake.ID(2112718607354982403)
+
+	out, err := runIDCommand(t, "inspect", id.Base36())
+	if err != nil {
+		t.Fatalf("inspect %s error = %v", id.Base36(), err)
+	}
+	for _, want := range []string{"warning: ambiguous ID", "Encoding:     base36\nTime", "ID:           " + id.String(), "Node:         7", "Encoding:     base32"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("inspect %s output missing %q:\n%s", id.Base36(), want, out)
+		}
+	}
+
+	_, err = runIDCommand(t, "convert", "--to", "decimal", id.Base36())
+	if err == nil || !strings.Contains(err.Error(), "base36 = "+id.String()) || !strings.Contains(err.Error(), "base32 = ") {
+		t.Errorf("convert %s error = %v, want ambiguity listing base36 and base32", id.Base36(), err)
+	}
+	out, err = runIDCommand(t, "convert", "--from", "base36", "--to", "decimal", id.Base36())
+	if err != nil || strings.TrimSpace(out) != id.String() {
+		t.Errorf("convert --from base36 = %q, %v, want %s", out, err, id)
+	}
+
+	out, err = runIDCommand(t, "inspect", id.Base58())
+	if err != nil {
+		t.Fatalf("inspect %s error = %v", id.Base58(), err)
+	}
+	if strings.Contains(out, "ambiguous") || !strings.Contains(out, "Encoding:     base58") || !strings.Contains(out, "ID:           "+id.String()) {
+		t.Errorf("inspect %s should decode as base58 only:\n%s", id.Base58(), out)
+	}
+	out, err = runIDCommand(t, "convert", "--to", "decimal", id.Base58())
+	if err != nil || strings.TrimSpace(out) != id.String() {
+		t.Errorf("convert %s = %q, %v, want %s", id.Base58(), out, err, id)
+	}
+}
+
+func TestIDCommand_InspectInvalid(t *testing.T) {
+	if _, err := runIDCommand(t, "inspect", "!!!"); err == nil {
+		t.Error("inspect should fail for undecodable input")
+	}
+	if _, err := runIDCommand(t, "inspect", "--encoding", "base99", "1"); err == nil {
+		t.Error("inspect should reject unknown encodings")
+	}
+}
+
+func TestIDCommand_Generate(t *testing.T) {
+	out, err := runIDCommand(t, "generate", "-n", "100", "--node", "9", "-e", "sortable62")
+	if err != nil {
+		t.Fatalf("generate error = %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 100 {
+		t.Fatalf("generate printed %d lines, want 100", len(lines))
+	}
+	for i, line := range lines {
+		id, err := snowflake.ParseSortableBase62(line)
+		if err != nil {
+			t.Fatalf("line %q error = %v", line, err)
+		}
+		if id.Node() != 9 {
+			t.Errorf("ID node = %d, want 9", id.Node())
+		}
+		if i > 0 && line <= lines[i-1] {
+			t.Errorf("IDs not increasing: %s after %s", line, lines[i-1])
+		}
+	}
+
+	if _, err := runIDCommand(t, "generate", "-n", "0"); err == nil {
+		t.Error("generate should reject a non-positive count")
+	}
+	if _, err := runIDCommand(t, "generate", "--node", "1024"); err == nil {
+		t.Error("generate should reject an out-of-range node")
+	}
+}
+
+func TestIDCommand_Convert(t *testing.T) {
+	id := snowflake.ID(1116823421972381696)
+
+	out, err := runIDCommand(t, "convert", "--to", "base58", id.String())
+	if err != nil || strings.TrimSpace(out) != id.Base58() {
+		t.Errorf("convert --to base58 = %q, %v, want %s", out, err, id.Base58())
+	}
+
+	out, err = runIDCommand(t, "convert", "--from", "base64url", "--to", "decimal", id.Base64URL())
+	if err != nil || strings.TrimSpace(out) != id.String() {
+		t.Errorf("convert --from base64url = %q, %v, want %s", out, err, id)
+	}
+
+	out, err = runIDCommand(t, "convert", id.String())
+	if err != nil {
+		t.Fatalf("convert error = %v", err)
+	}
+	for _, want := range []string{"decimal:      " + id.String(), "crockford32:  " + id.Crockford32(), "sortable58:   " + id.SortableBase58()} {
+		if !strings.Contains(out, want) {
+			t.Errorf("convert output missing %q:\n%s", want, out)
+		}
+	}
+
+	// 使用符号位的布局产生的负数 ID 以 '-' 前缀编码
+	out, err = runIDCommand(t, "convert", "--", "-5")
+	if err != nil || !strings.Contains(out, "base58:       -6\n") || !strings.Contains(out, "base32:       -f\n") {
+		t.Errorf("convert negative ID = %q, %v", out, err)
+	}
+	out, err = runIDCommand(t, "convert", "--from", "base58", "--to", "decimal", "--", "-6")
+	if err != nil || strings.TrimSpace(out) != "-5" {
+		t.Errorf("convert --from base58 negative ID = %q, %v, want -5", out, err)
+	}
+}
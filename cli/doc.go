@@ -66,6 +66,21 @@
 //
 // The Parameters section only shows flags that were changed from their default values.
 //
+// # ID Command
+//
+// clicobra.NewIDCommand returns an "id" command for debugging snowflake IDs,
+// to be mounted on any Cobra application:
+//
+//	rootCmd.AddCommand(clicobra.NewIDCommand())
+//
+//	$ myapp id inspect 1116823421972381696     # time (UTC and local), node, step
+//	$ myapp id generate -n 1000 -e base58      # bulk IDs for fixtures
+//	$ myapp id convert --to crockford32 4jgmnx8Js8A
+//
+// The input encoding is detected among decimal, base58, base32, base36 and
+// base64, or set with --encoding/--from. --epoch, --node-bits, --step-bits
+// and --sign-bit select the layout.
+//
 // # Custom Adapters
 //
 // Implement the CommandAdapter interface for other CLI frameworks:
@@ -1,6 +1,10 @@
 package config
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/chinayin/gox/internal/sentinel"
+)
 
 // errors for config package.
 var (
@@ -19,3 +23,12 @@ var (
 	// ErrNotFound is returned when no config files are found or a specific file is missing.
 	ErrNotFound = errors.New("gox/config: config file not found")
 )
+
+// 登记哨兵错误，log 展开错误链时输出其名称
+func init() {
+	sentinel.Register("config.ErrReadFailed", ErrReadFailed)
+	sentinel.Register("config.ErrUnmarshalFailed", ErrUnmarshalFailed)
+	sentinel.Register("config.ErrValidationFailed", ErrValidationFailed)
+	sentinel.Register("config.ErrMergeFailed", ErrMergeFailed)
+	sentinel.Register("config.ErrNotFound", ErrNotFound)
+}
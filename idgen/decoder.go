@@ -0,0 +1,148 @@
+package idgen
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/chinayin/gox/idgen/snowflake"
+	"github.com/chinayin/gox/idgen/ulid"
+	"github.com/chinayin/gox/idgen/uuidv7"
+)
+
+// A Decoder reconstructs IDs of one implementation from their stored forms,
+// so that Unwrap returns the native type again.
+type Decoder interface {
+	// DecodeInt64 rebuilds an ID from its Int64 form.
+	DecodeInt64(v int64) (ID, error)
+	// DecodeString rebuilds an ID from its String form.
+	DecodeString(s string) (ID, error)
+	// DecodeBinary rebuilds an ID from its MarshalBinary form.
+	DecodeBinary(b []byte) (ID, error)
+}
+
+// Decoders for the built-in generators.
+var (
+	// SnowflakeDecoder decodes IDs generated by Snowflake.
+	SnowflakeDecoder Decoder = snowflakeDecoder{}
+	// UUIDv7Decoder decodes IDs generated by UUIDv7.
+	UUIDv7Decoder Decoder = uuidv7Decoder{}
+	// ULIDDecoder decodes IDs generated by ULID.
+	ULIDDecoder Decoder = ulidDecoder{}
+)
+
+// Decode reconstructs an ID from a stored value with d. v may be an int64,
+// a string, a []byte holding text (as returned by database drivers) or an ID
+// read without a decoder, e.g. by ID.Scan or ID.UnmarshalJSON. A nil value or
+// zero ID decodes to the zero ID.
+func Decode(d Decoder, v any) (ID, error) {
+	switch v := v.(type) {
+	case nil:
+		return ID{}, nil
+	case ID:
+		if v.IsZero() {
+			return ID{}, nil
+		}
+		if v.intVal != 0 {
+			return d.DecodeInt64(v.intVal)
+		}
+		return d.DecodeString(v.strVal)
+	case int64:
+		return d.DecodeInt64(v)
+	case string:
+		return d.DecodeString(v)
+	case []byte:
+		return d.DecodeString(string(v))
+	default:
+		return ID{}, fmt.Errorf("%w: cannot decode %T", ErrInvalidID, v)
+	}
+}
+
+// ScanWith returns an sql.Scanner that decodes a column into id with d:
+//
+//	var id idgen.ID
+//	err := row.Scan(idgen.ScanWith(idgen.SnowflakeDecoder, &id))
+func ScanWith(d Decoder, id *ID) sql.Scanner {
+	return decodeScanner{d: d, id: id}
+}
+
+type decodeScanner struct {
+	d  Decoder
+	id *ID
+}
+
+func (s decodeScanner) Scan(src any) error {
+	id, err := Decode(s.d, src)
+	if err != nil {
+		return err
+	}
+	*s.id = id
+	return nil
+}
+
+type snowflakeDecoder struct{}
+
+func (snowflakeDecoder) DecodeInt64(v int64) (ID, error) {
+	return snowflakeID(snowflake.ParseInt64(v)), nil
+}
+
+func (snowflakeDecoder) DecodeString(s string) (ID, error) {
+	sf, err := snowflake.ParseString(s)
+	if err != nil {
+		return ID{}, fmt.Errorf("%w: %q is not a snowflake ID", ErrInvalidID, s)
+	}
+	return snowflakeID(sf), nil
+}
+
+func (snowflakeDecoder) DecodeBinary(b []byte) (ID, error) {
+	var sf snowflake.ID
+	if err := sf.UnmarshalBinary(b); err != nil {
+		return ID{}, fmt.Errorf("%w: %w", ErrInvalidID, err)
+	}
+	return snowflakeID(sf), nil
+}
+
+type uuidv7Decoder struct{}
+
+func (uuidv7Decoder) DecodeInt64(v int64) (ID, error) {
+	return ID{}, fmt.Errorf("%w: UUIDv7 has no int64 form", ErrInvalidID)
+}
+
+func (uuidv7Decoder) DecodeString(s string) (ID, error) {
+	return ParseUUIDv7(s)
+}
+
+func (uuidv7Decoder) DecodeBinary(b []byte) (ID, error) {
+	u, err := uuidv7.FromBytes(b)
+	if err != nil {
+		return ID{}, err
+	}
+	return uuidv7ID(u), nil
+}
+
+type ulidDecoder struct{}
+
+func (ulidDecoder) DecodeInt64(v int64) (ID, error) {
+	return ID{}, fmt.Errorf("%w: ULID has no int64 form", ErrInvalidID)
+}
+
+func (ulidDecoder) DecodeString(s string) (ID, error) {
+	return ParseULID(s)
+}
+
+func (ulidDecoder) DecodeBinary(b []byte) (ID, error) {
+	u, err := ulid.FromBytes(b)
+	if err != nil {
+		return ID{}, err
+	}
+	return ulidID(u), nil
+}
+
+// plainID returns an ID without an underlying value for s. Decimal strings
+// also set the int64 form.
+func plainID(s string) ID {
+	if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return NewID(v, s, nil)
+	}
+	return NewID(0, s, nil)
+}
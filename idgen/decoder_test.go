@@ -0,0 +1,98 @@
+package idgen
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chinayin/gox/idgen/snowflake"
+	"github.com/chinayin/gox/idgen/ulid"
+	"github.com/chinayin/gox/idgen/uuidv7"
+)
+
+func TestDecode(t *testing.T) {
+	sf, _ := NewSnowflake(1)
+	sid := sf.Generate()
+	uid := NewUUIDv7().Generate()
+	lid := NewULID().Generate()
+
+	tests := []struct {
+		name    string
+		d       Decoder
+		v       any
+		want    ID
+		wantErr bool
+	}{
+		{name: "snowflake int64", d: SnowflakeDecoder, v: sid.Int64(), want: sid},
+		{name: "snowflake string", d: SnowflakeDecoder, v: sid.String(), want: sid},
+		{name: "snowflake bytes", d: SnowflakeDecoder, v: []byte(sid.String()), want: sid},
+		{name: "snowflake plain ID", d: SnowflakeDecoder, v: NewID(sid.Int64(), sid.String(), nil), want: sid},
+		{name: "snowflake invalid", d: SnowflakeDecoder, v: "abc", wantErr: true},
+		{name: "uuidv7 string", d: UUIDv7Decoder, v: uid.String(), want: uid},
+		{name: "uuidv7 plain ID", d: UUIDv7Decoder, v: NewID(0, uid.String(), nil), want: uid},
+		{name: "uuidv7 int64", d: UUIDv7Decoder, v: int64(1), wantErr: true},
+		{name: "ulid string", d: ULIDDecoder, v: lid.String(), want: lid},
+		{name: "ulid int64", d: ULIDDecoder, v: int64(1), wantErr: true},
+		{name: "nil", d: SnowflakeDecoder, v: nil, want: ID{}},
+		{name: "zero ID", d: ULIDDecoder, v: ID{}, want: ID{}},
+		{name: "unsupported", d: SnowflakeDecoder, v: 1.5, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Decode(tt.d, tt.v)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Decode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Decode() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecoder_DecodeBinary(t *testing.T) {
+	sf, _ := NewSnowflake(1)
+	for _, tt := range []struct {
+		d  Decoder
+		id ID
+	}{
+		{d: SnowflakeDecoder, id: sf.Generate()},
+		{d: UUIDv7Decoder, id: NewUUIDv7().Generate()},
+		{d: ULIDDecoder, id: NewULID().Generate()},
+	} {
+		b, err := tt.id.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary() error = %v", err)
+		}
+		got, err := tt.d.DecodeBinary(b)
+		if err != nil || got != tt.id {
+			t.Errorf("DecodeBinary() = %v, %v, want %v", got, err, tt.id)
+		}
+	}
+
+	if _, err := SnowflakeDecoder.DecodeBinary([]byte{1, 2}); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("DecodeBinary() error = %v, want %v", err, ErrInvalidID)
+	}
+	if _, err := UUIDv7Decoder.DecodeBinary(make([]byte, 16)); !errors.Is(err, uuidv7.ErrInvalidUUID) {
+		t.Errorf("DecodeBinary() error = %v, want %v", err, uuidv7.ErrInvalidUUID)
+	}
+	if _, err := ULIDDecoder.DecodeBinary(make([]byte, 8)); !errors.Is(err, ulid.ErrInvalidULID) {
+		t.Errorf("DecodeBinary() error = %v, want %v", err, ulid.ErrInvalidULID)
+	}
+}
+
+func TestScanWith(t *testing.T) {
+	sf, _ := NewSnowflake(1)
+	want := sf.Generate()
+
+	var id ID
+	if err := ScanWith(SnowflakeDecoder, &id).Scan(want.Int64()); err != nil {
+		t.Fatalf("Scan() error = %v", err)
+	}
+	if _, ok := id.Unwrap().(snowflake.ID); !ok || id != want {
+		t.Errorf("Scan() = %#v, want %#v", id, want)
+	}
+
+	if err := ScanWith(UUIDv7Decoder, &id).Scan("not-a-uuid"); err == nil {
+		t.Error("Scan() should fail for invalid input")
+	}
+}
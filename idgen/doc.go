@@ -3,6 +3,8 @@
 // This package offers a consistent interface for generating unique IDs
 // using various algorithms. Currently supported:
 //   - Snowflake: Distributed 64-bit ID, trend-increasing
+//   - UUIDv7: 128-bit time-ordered UUID (RFC 9562), no node ID needed
+//   - ULID: 128-bit time-ordered ID as 26 base32 characters, no node ID needed
 //
 // # Basic Usage (Recommended)
 //
@@ -35,6 +37,86 @@
 //	    fmt.Println(sf.Base64()) // base64 encoding
 //	}
 //
+// UUIDv7 and ULID IDs have no int64 form; Int64 returns 0. They unwrap to
+// uuidv7.UUID and ulid.ULID, and ParseUUIDv7 and ParseULID turn their string
+// form back into an ID:
+//
+//	gen := idgen.NewUUIDv7()
+//	id := gen.Generate()
+//	u := id.Unwrap().(uuidv7.UUID)
+//	fmt.Println(u.Time()) // creation time
+//
+//	id, err := idgen.ParseUUIDv7("01890a5d-ac96-774b-bcce-b302099a8057")
+//
+// # Prefixed IDs
+//
+// Prefixed wraps any Generator to produce self-describing IDs in the TypeID
+// style. The suffix is fixed-width lower case base32, so IDs of one type sort
+// as strings in generation order:
+//
+//	users, err := idgen.NewPrefixed("user", idgen.NewUUIDv7())
+//	id := users.Generate()
+//	fmt.Println(id.String()) // user_01h455vb4pex5vsknk084sn02q
+//
+//	id, err = users.Parse("user_01h455vb4pex5vsknk084sn02q") // underlying UUIDv7 ID
+//
+// # Serialization
+//
+// ID implements sql.Scanner, driver.Valuer, encoding.TextMarshaler,
+// encoding.BinaryMarshaler and json.Marshaler with their counterparts. IDs
+// with an int64 form are stored as integers, others as strings. JSON holds the
+// String form, the same as MarshalText, so prefixed IDs keep their prefix.
+//
+// Reading back only restores the int64 and string forms. To get the native
+// type from Unwrap again, decode with the Decoder of the implementation:
+//
+//	var id idgen.ID
+//	err := row.Scan(idgen.ScanWith(idgen.SnowflakeDecoder, &id))
+//
+//	id, err = idgen.Decode(idgen.UUIDv7Decoder, req.ID) // req.ID unmarshaled from JSON
+//
+// # Node ID Assignment
+//
+// Replicas sharing a node ID generate duplicate IDs. NewSnowflakeWithProvider
+// resolves the node ID from a NodeIDProvider instead of configuration:
+//
+//   - EnvNodeID: an environment variable
+//   - HostnameNodeID: the StatefulSet pod ordinal ("web-3" → 3)
+//   - IPv4NodeID: the lower bits of a private IPv4 address
+//   - NewLease: a lease in a shared directory, with TTL and heartbeat
+//   - FirstNodeID: the first of several providers that succeeds
+//
+// For example, an explicit variable with the pod ordinal as fallback:
+//
+//	gen, err := idgen.NewSnowflakeWithProvider(idgen.FirstNodeID(
+//	    idgen.EnvNodeID("NODE_ID"),
+//	    idgen.HostnameNodeID(""),
+//	))
+//
+// Leases are released by Snowflake.Close. A generator stops once its lease
+// is lost or not renewed within TTL: GenerateE returns ErrNodeIDLost, while
+// Generate and GenerateN panic, so lease users should call GenerateE.
+//
+// # Batch Generation
+//
+// Generators implementing BatchGenerator, such as Snowflake, create many IDs
+// at once with far less locking than repeated Generate calls. GenerateN uses
+// it when available and falls back to Generate otherwise:
+//
+//	ids := idgen.GenerateN(gen, 10000)
+//
+// # Metrics
+//
+// Create the generator with snowflake.WithStats to count generated IDs and
+// waits caused by step exhaustion, which show a node saturating its 4096 IDs
+// per millisecond. Stats returns a snapshot; StatsHandler serves it in the
+// Prometheus text exposition format:
+//
+//	gen, err := idgen.NewSnowflakeWithOptions(cfg.NodeID, snowflake.WithStats())
+//	fmt.Println(gen.Stats().StepWaits)
+//
+//	http.Handle("/metrics/idgen", idgen.StatsHandler(gen))
+//
 // # Global Generator (Use with Caution)
 //
 // A global generator is provided for simple use cases, but has limitations:
@@ -0,0 +1,112 @@
+package idgen
+
+import (
+	"database/sql/driver"
+	"encoding"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// The methods below serialize an ID through its String and Int64 forms.
+// Unmarshal and Scan have no Decoder, so they restore those forms only and
+// Unwrap returns nil; pass the result to Decode to rebuild the native type.
+
+// MarshalText implements encoding.TextMarshaler using the String form.
+func (id ID) MarshalText() ([]byte, error) {
+	return []byte(id.strVal), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler. Empty text is the zero ID.
+func (id *ID) UnmarshalText(b []byte) error {
+	*id = plainID(string(b))
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler. The zero ID is null; other IDs are
+// JSON strings holding the String form, like MarshalText, so prefixed IDs
+// keep their prefix.
+func (id ID) MarshalJSON() ([]byte, error) {
+	if id.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(id.strVal)
+}
+
+// UnmarshalJSON implements json.Unmarshaler. It accepts null, strings and
+// integers.
+func (id *ID) UnmarshalJSON(b []byte) error {
+	switch {
+	case string(b) == "null":
+		*id = ID{}
+	case len(b) > 0 && b[0] == '"':
+		var s string
+		if err := json.Unmarshal(b, &s); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidID, err)
+		}
+		*id = plainID(s)
+	default:
+		v, err := strconv.ParseInt(string(b), 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidID, b)
+		}
+		*id = NewID(v, string(b), nil)
+	}
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler: the underlying value's
+// binary form if it has one, e.g. 16 bytes for UUIDv7 and ULID, otherwise the
+// int64 form as 8 big endian bytes.
+func (id ID) MarshalBinary() ([]byte, error) {
+	if m, ok := id.raw.(encoding.BinaryMarshaler); ok {
+		return m.MarshalBinary()
+	}
+	if id.intVal == 0 && id.strVal != "" {
+		return nil, fmt.Errorf("%w: %q has no binary form", ErrInvalidID, id.strVal)
+	}
+	return binary.BigEndian.AppendUint64(nil, uint64(id.intVal)), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler for the 8-byte int64
+// form. Use Decoder.DecodeBinary for other forms.
+func (id *ID) UnmarshalBinary(b []byte) error {
+	if len(b) != 8 {
+		return fmt.Errorf("%w: %d bytes; use Decoder.DecodeBinary", ErrInvalidID, len(b))
+	}
+	v := int64(binary.BigEndian.Uint64(b))
+	*id = NewID(v, strconv.FormatInt(v, 10), nil)
+	return nil
+}
+
+// Value implements driver.Valuer. IDs with an int64 form are stored as
+// integers, others as strings, and the zero ID as NULL.
+func (id ID) Value() (driver.Value, error) {
+	switch {
+	case id.IsZero():
+		return nil, nil
+	case id.intVal != 0:
+		return id.intVal, nil
+	default:
+		return id.strVal, nil
+	}
+}
+
+// Scan implements sql.Scanner. It accepts integers, text and NULL; use
+// ScanWith to rebuild the native type.
+func (id *ID) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*id = ID{}
+	case int64:
+		*id = NewID(v, strconv.FormatInt(v, 10), nil)
+	case string:
+		*id = plainID(v)
+	case []byte:
+		*id = plainID(string(v))
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalidID, src)
+	}
+	return nil
+}
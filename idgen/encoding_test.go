@@ -0,0 +1,184 @@
+package idgen
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+var (
+	_ encoding.TextMarshaler     = ID{}
+	_ encoding.TextUnmarshaler   = (*ID)(nil)
+	_ encoding.BinaryMarshaler   = ID{}
+	_ encoding.BinaryUnmarshaler = (*ID)(nil)
+	_ json.Marshaler             = ID{}
+	_ json.Unmarshaler           = (*ID)(nil)
+	_ driver.Valuer              = ID{}
+	_ sql.Scanner                = (*ID)(nil)
+)
+
+func TestID_MarshalJSON(t *testing.T) {
+	sf, _ := NewSnowflake(1)
+	sid := sf.Generate()
+	uid := NewUUIDv7().Generate()
+
+	tests := []struct {
+		name string
+		id   ID
+		want string
+	}{
+		{name: "zero", id: ID{}, want: `null`},
+		{name: "snowflake", id: sid, want: `"` + sid.String() + `"`},
+		{name: "uuidv7", id: uid, want: `"` + uid.String() + `"`},
+		{name: "plain", id: NewID(42, "42", nil), want: `"42"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.id)
+			if err != nil || string(got) != tt.want {
+				t.Errorf("json.Marshal() = %s, %v, want %s", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestID_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    ID
+		wantErr bool
+	}{
+		{name: "null", input: `null`, want: ID{}},
+		{name: "string", input: `"1234"`, want: NewID(1234, "1234", nil)},
+		{name: "number", input: `1234`, want: NewID(1234, "1234", nil)},
+		{name: "text", input: `"01ARZ3NDEKTSV4RRFFQ69G5FAV"`, want: NewID(0, "01ARZ3NDEKTSV4RRFFQ69G5FAV", nil)},
+		{name: "float", input: `1.5`, wantErr: true},
+		{name: "object", input: `{}`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got ID
+			err := got.UnmarshalJSON([]byte(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidID) {
+				t.Errorf("UnmarshalJSON() error = %v, want %v", err, ErrInvalidID)
+			}
+			if got != tt.want {
+				t.Errorf("UnmarshalJSON() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestID_Text(t *testing.T) {
+	uid := NewUUIDv7().Generate()
+	b, err := uid.MarshalText()
+	if err != nil || string(b) != uid.String() {
+		t.Fatalf("MarshalText() = %s, %v", b, err)
+	}
+	var got ID
+	if err := got.UnmarshalText(b); err != nil || got.String() != uid.String() {
+		t.Errorf("UnmarshalText() = %v, %v, want %v", got, err, uid)
+	}
+}
+
+func TestID_Binary(t *testing.T) {
+	sf, _ := NewSnowflake(1)
+	sid := sf.Generate()
+
+	b, err := sid.MarshalBinary()
+	if err != nil || len(b) != 8 {
+		t.Fatalf("MarshalBinary() = %x, %v, want 8 bytes", b, err)
+	}
+	var got ID
+	if err := got.UnmarshalBinary(b); err != nil || got.Int64() != sid.Int64() || got.String() != sid.String() {
+		t.Errorf("UnmarshalBinary() = %v, %v, want %v", got, err, sid)
+	}
+
+	uid := NewUUIDv7().Generate()
+	b, err = uid.MarshalBinary()
+	if err != nil || len(b) != 16 {
+		t.Fatalf("MarshalBinary() = %x, %v, want 16 bytes", b, err)
+	}
+	if err := got.UnmarshalBinary(b); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("UnmarshalBinary(16 bytes) error = %v, want %v", err, ErrInvalidID)
+	}
+	if _, err := NewID(0, "abc", nil).MarshalBinary(); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("MarshalBinary() error = %v, want %v", err, ErrInvalidID)
+	}
+}
+
+func TestID_SQL(t *testing.T) {
+	sf, _ := NewSnowflake(1)
+	sid := sf.Generate()
+	uid := NewUUIDv7().Generate()
+
+	values := []struct {
+		id   ID
+		want driver.Value
+	}{
+		{id: ID{}, want: nil},
+		{id: sid, want: sid.Int64()},
+		{id: uid, want: uid.String()},
+	}
+	for _, tt := range values {
+		if got, err := tt.id.Value(); err != nil || got != tt.want {
+			t.Errorf("Value() = %v, %v, want %v", got, err, tt.want)
+		}
+	}
+
+	scans := []struct {
+		src     any
+		want    ID
+		wantErr bool
+	}{
+		{src: nil, want: ID{}},
+		{src: int64(42), want: NewID(42, "42", nil)},
+		{src: []byte("42"), want: NewID(42, "42", nil)},
+		{src: uid.String(), want: NewID(0, uid.String(), nil)},
+		{src: 1.5, wantErr: true},
+	}
+	for _, tt := range scans {
+		got := NewID(1, "1", nil)
+		err := got.Scan(tt.src)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("Scan(%v) error = %v, wantErr %v", tt.src, err, tt.wantErr)
+		}
+		if err == nil && got != tt.want {
+			t.Errorf("Scan(%v) = %#v, want %#v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestID_JSONRoundTrip(t *testing.T) {
+	type order struct {
+		ID    ID `json:"id"`
+		Owner ID `json:"owner,omitzero"`
+	}
+
+	sf, _ := NewSnowflake(1)
+	in := order{ID: sf.Generate()}
+	b, err := json.Marshal(in)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if !bytes.Contains(b, []byte(in.ID.String())) || bytes.Contains(b, []byte("owner")) {
+		t.Errorf("json.Marshal() = %s", b)
+	}
+
+	var out order
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	got, err := Decode(SnowflakeDecoder, out.ID)
+	if err != nil || got != in.ID {
+		t.Errorf("Decode() = %v, %v, want %v", got, err, in.ID)
+	}
+}
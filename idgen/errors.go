@@ -1,6 +1,10 @@
 package idgen
 
-import "errors"
+import (
+	"errors"
+
+	"github.com/chinayin/gox/internal/sentinel"
+)
 
 // Sentinel errors for idgen package.
 var (
@@ -9,4 +13,36 @@ var (
 
 	// ErrAlreadyInitialized is returned when SetDefault is called more than once.
 	ErrAlreadyInitialized = errors.New("gox/idgen: default generator already initialized")
+
+	// ErrNodeIDUnavailable is returned when a NodeIDProvider cannot resolve a node ID.
+	ErrNodeIDUnavailable = errors.New("gox/idgen: node ID unavailable")
+
+	// ErrInvalidID is returned when a stored value cannot be decoded into an ID.
+	ErrInvalidID = errors.New("gox/idgen: invalid ID")
+
+	// ErrInvalidPrefix is returned by NewPrefixed when the type prefix is invalid.
+	ErrInvalidPrefix = errors.New("gox/idgen: invalid prefix")
+
+	// ErrInvalidPrefixedID is returned by Prefixed.Parse when the input is not
+	// a valid prefixed ID of its type.
+	ErrInvalidPrefixedID = errors.New("gox/idgen: invalid prefixed ID")
+
+	// ErrInvalidLeaseOptions is returned by NewLease when the options are inconsistent.
+	ErrInvalidLeaseOptions = errors.New("gox/idgen: invalid lease options")
+
+	// ErrNodeIDLost is returned by Snowflake.GenerateE, and Snowflake.Generate
+	// panics with it, once the lease holding the node ID is lost.
+	ErrNodeIDLost = errors.New("gox/idgen: node ID lease lost")
 )
+
+// Register the sentinels so that gox/log names them in expanded error chains.
+func init() {
+	sentinel.Register("idgen.ErrNotInitialized", ErrNotInitialized)
+	sentinel.Register("idgen.ErrAlreadyInitialized", ErrAlreadyInitialized)
+	sentinel.Register("idgen.ErrNodeIDUnavailable", ErrNodeIDUnavailable)
+	sentinel.Register("idgen.ErrInvalidID", ErrInvalidID)
+	sentinel.Register("idgen.ErrInvalidPrefix", ErrInvalidPrefix)
+	sentinel.Register("idgen.ErrInvalidPrefixedID", ErrInvalidPrefixedID)
+	sentinel.Register("idgen.ErrInvalidLeaseOptions", ErrInvalidLeaseOptions)
+	sentinel.Register("idgen.ErrNodeIDLost", ErrNodeIDLost)
+}
@@ -0,0 +1,25 @@
+package idgen
+
+import (
+	"testing"
+
+	"github.com/chinayin/gox/internal/sentinel"
+)
+
+func TestSentinelsRegistered(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{err: ErrNotInitialized, want: "idgen.ErrNotInitialized"},
+		{err: ErrNodeIDUnavailable, want: "idgen.ErrNodeIDUnavailable"},
+		{err: ErrInvalidID, want: "idgen.ErrInvalidID"},
+		{err: ErrInvalidPrefixedID, want: "idgen.ErrInvalidPrefixedID"},
+		{err: ErrNodeIDLost, want: "idgen.ErrNodeIDLost"},
+	}
+	for _, tt := range tests {
+		if got := sentinel.Name(tt.err); got != tt.want {
+			t.Errorf("sentinel.Name(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
@@ -7,6 +7,30 @@ type Generator interface {
 	Generate() ID
 }
 
+// BatchGenerator is implemented by generators that create many IDs more
+// cheaply at once than by calling Generate repeatedly.
+type BatchGenerator interface {
+	Generator
+	// GenerateN creates n unique IDs in increasing order.
+	GenerateN(n int) []ID
+}
+
+// GenerateN creates n IDs with g, using GenerateN if g is a BatchGenerator.
+// It returns nil if n is not positive.
+func GenerateN(g Generator, n int) []ID {
+	if n <= 0 {
+		return nil
+	}
+	if bg, ok := g.(BatchGenerator); ok {
+		return bg.GenerateN(n)
+	}
+	ids := make([]ID, n)
+	for i := range ids {
+		ids[i] = g.Generate()
+	}
+	return ids
+}
+
 // ========== Global Generator ==========
 
 var defaultGen atomic.Pointer[Generator]
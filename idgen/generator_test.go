@@ -161,6 +161,72 @@ func TestSnowflake_Generate_Concurrent(t *testing.T) {
 	}
 }
 
+func TestSnowflake_GenerateN(t *testing.T) {
+	gen, err := NewSnowflake(1)
+	if err != nil {
+		t.Fatalf("NewSnowflake() error = %v", err)
+	}
+
+	ids := gen.GenerateN(5000)
+	if len(ids) != 5000 {
+		t.Fatalf("GenerateN() len = %d, want 5000", len(ids))
+	}
+	for i, id := range ids {
+		if id.String() == "" {
+			t.Fatalf("ID.String() should not be empty")
+		}
+		if i > 0 && id.Int64() <= ids[i-1].Int64() {
+			t.Fatalf("ID %d not greater than %d", id.Int64(), ids[i-1].Int64())
+		}
+	}
+	if ids := gen.GenerateN(0); ids != nil {
+		t.Errorf("GenerateN(0) = %v, want nil", ids)
+	}
+}
+
+// plainGenerator only implements Generator
+type plainGenerator struct{ n int64 }
+
+func (g *plainGenerator) Generate() ID {
+	g.n++
+	return NewID(g.n, "", nil)
+}
+
+func TestGenerateN(t *testing.T) {
+	gen, _ := NewSnowflake(1)
+	if got := GenerateN(gen, 10); len(got) != 10 {
+		t.Errorf("GenerateN(batch) len = %d, want 10", len(got))
+	}
+
+	got := GenerateN(&plainGenerator{}, 3)
+	if len(got) != 3 || got[0].Int64() != 1 || got[2].Int64() != 3 {
+		t.Errorf("GenerateN(plain) = %v, want IDs 1..3", got)
+	}
+	if got := GenerateN(gen, -1); got != nil {
+		t.Errorf("GenerateN(-1) = %v, want nil", got)
+	}
+}
+
+func BenchmarkSnowflake_Generate(b *testing.B) {
+	gen, _ := NewSnowflake(1)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		_ = gen.Generate()
+	}
+}
+
+func BenchmarkSnowflake_GenerateN(b *testing.B) {
+	gen, _ := NewSnowflake(1)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i += 1000 {
+		_ = gen.GenerateN(1000)
+	}
+}
+
 func TestSetDefault(t *testing.T) {
 	// Reset before test
 	ResetDefault()
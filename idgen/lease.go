@@ -0,0 +1,301 @@
+package idgen
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultLeaseTTL is the lease TTL used when LeaseOptions.TTL is zero.
+const DefaultLeaseTTL = 30 * time.Second
+
+// renewLease refreshes the modification time of a lease file. Tests replace
+// it to simulate renewal failures.
+var renewLease = func(path string, now time.Time) error {
+	return os.Chtimes(path, now, now)
+}
+
+// LeaseOptions configures a Lease.
+type LeaseOptions struct {
+	// TTL is how long a lease stays valid without a heartbeat. Leases of
+	// crashed processes can be taken over after TTL. Default DefaultLeaseTTL.
+	TTL time.Duration
+	// HeartbeatInterval is how often the lease is renewed. It must be shorter
+	// than TTL. Default TTL/3.
+	HeartbeatInterval time.Duration
+	// Owner identifies the holder in the lease file. Default "hostname:pid".
+	Owner string
+}
+
+// A Lease is a NodeIDProvider that allocates node IDs from a directory shared
+// by all instances, e.g. a volume mounted into every replica.
+//
+// Each node ID is a file "node-<id>.lease" created exclusively in the
+// directory. A background heartbeat refreshes its modification time; files
+// not refreshed within TTL are considered abandoned and may be taken over.
+// Close stops the heartbeat and releases the node ID. A Snowflake created
+// from the lease stops generating IDs once the lease is lost, including when
+// no renewal succeeded within TTL, e.g. while the process was paused.
+type Lease struct {
+	dir  string
+	opts LeaseOptions
+
+	mu    sync.Mutex
+	id    int64
+	path  string
+	token string
+	stop  chan struct{}
+	done  chan struct{}
+
+	renewed  atomic.Pointer[time.Time] // last successful renewal, nil while no node ID is held
+	lost     chan struct{}
+	lostOnce sync.Once
+}
+
+var (
+	_ NodeIDProvider = (*Lease)(nil)
+	_ io.Closer      = (*Lease)(nil)
+)
+
+// NewLease returns a lease allocator using dir as the shared lock directory.
+// The directory is created on first use. It returns ErrInvalidLeaseOptions if
+// HeartbeatInterval is not shorter than TTL, since the lease would expire
+// between renewals.
+func NewLease(dir string, opts LeaseOptions) (*Lease, error) {
+	if opts.TTL <= 0 {
+		opts.TTL = DefaultLeaseTTL
+	}
+	if opts.HeartbeatInterval <= 0 {
+		opts.HeartbeatInterval = opts.TTL / 3
+	}
+	if opts.HeartbeatInterval >= opts.TTL {
+		return nil, fmt.Errorf("%w: heartbeat interval %s must be shorter than TTL %s",
+			ErrInvalidLeaseOptions, opts.HeartbeatInterval, opts.TTL)
+	}
+	if opts.Owner == "" {
+		hostname, _ := os.Hostname()
+		opts.Owner = hostname + ":" + strconv.Itoa(os.Getpid())
+	}
+	return &Lease{dir: dir, opts: opts, id: -1, lost: make(chan struct{})}, nil
+}
+
+// NodeID acquires the lowest free node ID in [0, maxID] and starts the
+// heartbeat. Later calls return the same ID until Close.
+func (l *Lease) NodeID(maxID int64) (int64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.id >= 0 {
+		return l.id, nil
+	}
+	if err := os.MkdirAll(l.dir, 0o750); err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrNodeIDUnavailable, err)
+	}
+
+	token, err := newLeaseToken(l.opts.Owner)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %w", ErrNodeIDUnavailable, err)
+	}
+	for id := int64(0); id <= maxID; id++ {
+		path := filepath.Join(l.dir, fmt.Sprintf("node-%d.lease", id))
+		now := time.Now()
+		ok, err := l.acquire(path, token)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %w", ErrNodeIDUnavailable, err)
+		}
+		if ok {
+			renewed := renewedAt(path, now)
+			l.renewed.Store(&renewed)
+			l.id, l.path, l.token = id, path, token
+			l.stop, l.done = make(chan struct{}), make(chan struct{})
+			go l.heartbeat(path, token, l.stop, l.done)
+			return id, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: all %d node IDs in %s are leased", ErrNodeIDUnavailable, maxID+1, l.dir)
+}
+
+// Lost returns a channel that is closed when the heartbeat finds the lease
+// was taken over or removed, e.g. after the process was paused for longer
+// than TTL, or fails to renew it, and when Held finds it expired. IDs
+// generated afterwards may collide with the new holder's, so a Snowflake
+// using the lease stops generating them.
+func (l *Lease) Lost() <-chan struct{} {
+	return l.lost
+}
+
+// Held reports whether the lease holds a node ID that is safe to use: it has
+// not been lost and was renewed less than TTL ago. Once TTL has passed since
+// the last successful renewal, another instance may take the lease over, so
+// Held marks it lost without waiting for the next heartbeat, which may be
+// late or stalled.
+func (l *Lease) Held() bool {
+	select {
+	case <-l.lost:
+		return false
+	default:
+	}
+	renewed := l.renewed.Load()
+	if renewed == nil {
+		return false
+	}
+	if time.Since(*renewed) >= l.opts.TTL {
+		l.markLost()
+		return false
+	}
+	return true
+}
+
+// markLost closes the Lost channel.
+func (l *Lease) markLost() {
+	l.lostOnce.Do(func() { close(l.lost) })
+}
+
+// Close stops the heartbeat and releases the node ID if it is still held.
+func (l *Lease) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.id < 0 {
+		return nil
+	}
+	close(l.stop)
+	<-l.done
+
+	var err error
+	if l.owns(l.path, l.token) {
+		err = os.Remove(l.path)
+	}
+	l.id, l.path, l.token = -1, "", ""
+	l.renewed.Store(nil)
+	return err
+}
+
+// acquire tries to create the lease file, taking it over if it has expired.
+func (l *Lease) acquire(path, token string) (bool, error) {
+	ok, err := createLeaseFile(path, token)
+	if ok || err != nil {
+		return ok, err
+	}
+	if !l.expired(path) {
+		return false, nil
+	}
+
+	// Serialize takeovers with a lock directory so that two instances do not
+	// both remove the expired file and each create their own.
+	lock := path + ".takeover"
+	if err := os.Mkdir(lock, 0o750); err != nil {
+		if errors.Is(err, fs.ErrExist) && l.expired(lock) {
+			_ = os.Remove(lock) // left behind by a crashed takeover; retried on the next ID scan
+		}
+		return false, nil
+	}
+	defer func() { _ = os.Remove(lock) }()
+
+	if !l.expired(path) {
+		return false, nil
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return false, err
+	}
+	return createLeaseFile(path, token)
+}
+
+// expired reports whether path was last refreshed more than TTL ago.
+func (l *Lease) expired(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && time.Since(info.ModTime()) > l.opts.TTL
+}
+
+// owns reports whether the lease file still holds token.
+func (l *Lease) owns(path, token string) bool {
+	// #nosec G304 -- path is built from the configured lease directory
+	data, err := os.ReadFile(path)
+	return err == nil && string(data) == token
+}
+
+// heartbeat refreshes the lease file until stop is closed or the lease is
+// lost. A failed renewal counts as lost: another instance may take the lease
+// over once it expires. So does a tick more than TTL after the last renewal,
+// even if the file is still ours, since a takeover may be under way.
+func (l *Lease) heartbeat(path, token string, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(l.opts.HeartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if !l.Held() {
+				return
+			}
+			if !l.owns(path, token) {
+				l.markLost()
+				return
+			}
+			now := time.Now()
+			if err := renewLease(path, now); err != nil {
+				l.markLost()
+				return
+			}
+			renewed := renewedAt(path, now)
+			l.renewed.Store(&renewed)
+		}
+	}
+}
+
+// renewedAt returns when path was renewed as seen by other instances: its
+// modification time, which the file system may have truncated, but no later
+// than now, so that the result keeps now's monotonic clock reading.
+func renewedAt(path string, now time.Time) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return now
+	}
+	if d := info.ModTime().Sub(now); d < 0 {
+		return now.Add(d)
+	}
+	return now
+}
+
+// createLeaseFile creates path exclusively and writes token into it.
+// It returns false without error if the file already exists.
+func createLeaseFile(path, token string) (bool, error) {
+	// #nosec G304 -- path is built from the configured lease directory
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if errors.Is(err, fs.ErrExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	_, err = f.WriteString(token)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		_ = os.Remove(path)
+		return false, err
+	}
+	return true, nil
+}
+
+// newLeaseToken returns a token unique to this lease holder.
+func newLeaseToken(owner string) (string, error) {
+	var b [8]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	return owner + " " + hex.EncodeToString(b[:]), nil
+}
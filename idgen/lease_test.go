@@ -0,0 +1,305 @@
+package idgen
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// mustNewLease 创建租约，选项无效时终止测试
+func mustNewLease(t *testing.T, dir string, opts LeaseOptions) *Lease {
+	t.Helper()
+	l, err := NewLease(dir, opts)
+	if err != nil {
+		t.Fatalf("NewLease() error = %v", err)
+	}
+	return l
+}
+
+func TestLease_AllocatesDistinctIDs(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "leases")
+
+	const n = 8
+	leases := make([]*Lease, n)
+	ids := make([]int64, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		leases[i] = mustNewLease(t, dir, LeaseOptions{TTL: time.Minute})
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			id, err := leases[i].NodeID(1023)
+			if err != nil {
+				t.Errorf("NodeID() error = %v", err)
+			}
+			ids[i] = id
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[int64]bool, n)
+	for _, id := range ids {
+		if seen[id] {
+			t.Fatalf("node ID %d leased twice: %v", id, ids)
+		}
+		seen[id] = true
+	}
+
+	// 重复调用返回同一 ID
+	if again, _ := leases[0].NodeID(1023); again != ids[0] {
+		t.Errorf("NodeID() again = %d, want %d", again, ids[0])
+	}
+
+	// 释放后可被重新分配
+	if err := leases[0].Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	next := mustNewLease(t, dir, LeaseOptions{TTL: time.Minute})
+	if id, err := next.NodeID(1023); err != nil || id != ids[0] {
+		t.Errorf("NodeID() after release = %d, %v, want %d", id, err, ids[0])
+	}
+
+	for _, l := range append(leases[1:], next) {
+		_ = l.Close()
+	}
+}
+
+func TestLease_Exhausted(t *testing.T) {
+	dir := t.TempDir()
+
+	a := mustNewLease(t, dir, LeaseOptions{TTL: time.Minute})
+	b := mustNewLease(t, dir, LeaseOptions{TTL: time.Minute})
+	t.Cleanup(func() { _ = a.Close(); _ = b.Close() })
+
+	if _, err := a.NodeID(0); err != nil {
+		t.Fatalf("NodeID() error = %v", err)
+	}
+	if _, err := b.NodeID(0); !errors.Is(err, ErrNodeIDUnavailable) {
+		t.Errorf("NodeID() error = %v, want %v", err, ErrNodeIDUnavailable)
+	}
+}
+
+func TestLease_TakeOverExpired(t *testing.T) {
+	dir := t.TempDir()
+
+	// 模拟崩溃进程遗留的租约
+	stale := filepath.Join(dir, "node-0.lease")
+	if err := os.WriteFile(stale, []byte("crashed:1 00"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(stale, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	l := mustNewLease(t, dir, LeaseOptions{TTL: time.Minute})
+	t.Cleanup(func() { _ = l.Close() })
+	if id, err := l.NodeID(1023); err != nil || id != 0 {
+		t.Errorf("NodeID() = %d, %v, want expired ID 0", id, err)
+	}
+}
+
+func TestLease_Heartbeat(t *testing.T) {
+	dir := t.TempDir()
+
+	l := mustNewLease(t, dir, LeaseOptions{TTL: 60 * time.Millisecond, HeartbeatInterval: 10 * time.Millisecond})
+	t.Cleanup(func() { _ = l.Close() })
+	if _, err := l.NodeID(0); err != nil {
+		t.Fatal(err)
+	}
+
+	// 心跳持续续约，超过 TTL 后其他实例仍无法接管
+	time.Sleep(150 * time.Millisecond)
+	other := mustNewLease(t, dir, LeaseOptions{TTL: 60 * time.Millisecond})
+	if _, err := other.NodeID(0); !errors.Is(err, ErrNodeIDUnavailable) {
+		t.Errorf("NodeID() error = %v, want %v while heartbeat is running", err, ErrNodeIDUnavailable)
+	}
+	select {
+	case <-l.Lost():
+		t.Error("lease should not be lost")
+	default:
+	}
+}
+
+func TestLease_Lost(t *testing.T) {
+	dir := t.TempDir()
+
+	l := mustNewLease(t, dir, LeaseOptions{TTL: time.Minute, HeartbeatInterval: 5 * time.Millisecond})
+	if _, err := l.NodeID(0); err != nil {
+		t.Fatal(err)
+	}
+
+	// 租约文件被其他实例接管
+	if err := os.WriteFile(filepath.Join(dir, "node-0.lease"), []byte("other:2 ff"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-l.Lost():
+	case <-time.After(time.Second):
+		t.Fatal("Lost() should be closed after takeover")
+	}
+
+	// Close 不删除他人的租约
+	if err := l.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "node-0.lease")); err != nil {
+		t.Errorf("Close() removed a lease it no longer owns: %v", err)
+	}
+}
+
+func TestNewSnowflakeWithProvider_Lease(t *testing.T) {
+	dir := t.TempDir()
+
+	a, err := NewSnowflakeWithProvider(mustNewLease(t, dir, LeaseOptions{}))
+	if err != nil {
+		t.Fatalf("NewSnowflakeWithProvider() error = %v", err)
+	}
+	b, err := NewSnowflakeWithProvider(mustNewLease(t, dir, LeaseOptions{}))
+	if err != nil {
+		t.Fatalf("NewSnowflakeWithProvider() error = %v", err)
+	}
+	if a.NodeID() == b.NodeID() {
+		t.Errorf("generators share node ID %d", a.NodeID())
+	}
+
+	if err := a.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "node-0.lease")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Close() should release the lease, stat error = %v", err)
+	}
+	_ = b.Close()
+}
+
+func TestNewLease_InvalidHeartbeat(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    LeaseOptions
+		wantErr bool
+	}{
+		{name: "defaults", opts: LeaseOptions{}},
+		{name: "shorter than TTL", opts: LeaseOptions{TTL: time.Second, HeartbeatInterval: 500 * time.Millisecond}},
+		{name: "equal to TTL", opts: LeaseOptions{TTL: time.Second, HeartbeatInterval: time.Second}, wantErr: true},
+		{name: "longer than default TTL", opts: LeaseOptions{HeartbeatInterval: time.Minute}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLease(t.TempDir(), tt.opts)
+			if tt.wantErr != errors.Is(err, ErrInvalidLeaseOptions) {
+				t.Errorf("NewLease() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLease_LostOnRenewalFailure(t *testing.T) {
+	renew := renewLease
+	t.Cleanup(func() { renewLease = renew })
+	renewLease = func(string, time.Time) error { return os.ErrPermission }
+
+	l := mustNewLease(t, t.TempDir(), LeaseOptions{TTL: time.Minute, HeartbeatInterval: 5 * time.Millisecond})
+	t.Cleanup(func() { _ = l.Close() })
+	if _, err := l.NodeID(0); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-l.Lost():
+	case <-time.After(time.Second):
+		t.Fatal("Lost() should be closed when the lease cannot be renewed")
+	}
+}
+
+func TestSnowflake_StopsAfterLeaseLost(t *testing.T) {
+	dir := t.TempDir()
+
+	lease := mustNewLease(t, dir, LeaseOptions{TTL: time.Minute, HeartbeatInterval: 5 * time.Millisecond})
+	gen, err := NewSnowflakeWithProvider(lease)
+	if err != nil {
+		t.Fatalf("NewSnowflakeWithProvider() error = %v", err)
+	}
+	t.Cleanup(func() { _ = gen.Close() })
+
+	if _, err := gen.GenerateE(); err != nil {
+		t.Fatalf("GenerateE() error = %v before the lease is lost", err)
+	}
+
+	// 租约被其他实例接管
+	if err := os.WriteFile(filepath.Join(dir, "node-0.lease"), []byte("other:2 ff"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-lease.Lost():
+	case <-time.After(time.Second):
+		t.Fatal("Lost() should be closed after takeover")
+	}
+
+	if _, err := gen.GenerateE(); !errors.Is(err, ErrNodeIDLost) {
+		t.Errorf("GenerateE() error = %v, want %v", err, ErrNodeIDLost)
+	}
+	defer func() {
+		if r := recover(); r != ErrNodeIDLost {
+			t.Errorf("Generate() panic = %v, want %v", r, ErrNodeIDLost)
+		}
+	}()
+	gen.Generate()
+}
+
+func TestSnowflake_StopsWhenRenewalStalls(t *testing.T) {
+	dir := t.TempDir()
+	const ttl = 60 * time.Millisecond
+
+	// 续约卡住，模拟进程暂停或文件系统无响应：心跳不再推进，也不会报告失败
+	release := make(chan struct{})
+	renew := renewLease
+	t.Cleanup(func() { renewLease = renew })
+	renewLease = func(string, time.Time) error {
+		<-release
+		return nil
+	}
+
+	lease := mustNewLease(t, dir, LeaseOptions{TTL: ttl, HeartbeatInterval: 5 * time.Millisecond})
+	gen, err := NewSnowflakeWithProvider(lease)
+	if err != nil {
+		t.Fatalf("NewSnowflakeWithProvider() error = %v", err)
+	}
+	t.Cleanup(func() { _ = gen.Close() })
+	t.Cleanup(func() { close(release) })
+
+	path := filepath.Join(dir, "node-0.lease")
+	deadline := time.Now().Add(time.Second)
+	for {
+		_, genErr := gen.GenerateE()
+		// 生成成功时租约必须仍未过期，其他实例无法接管
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if genErr == nil && time.Since(info.ModTime()) > ttl {
+			t.Fatal("GenerateE() succeeded after the lease could be taken over")
+		}
+		if errors.Is(genErr, ErrNodeIDLost) {
+			break
+		}
+		if genErr != nil {
+			t.Fatalf("GenerateE() error = %v", genErr)
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("GenerateE() should fail once the lease is not renewed within TTL")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	select {
+	case <-lease.Lost():
+	default:
+		t.Error("Lost() should be closed once the lease expires locally")
+	}
+	if lease.Held() {
+		t.Error("Held() = true, want false after expiry")
+	}
+}
@@ -0,0 +1,166 @@
+package idgen
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// NodeIDProvider resolves the Snowflake node ID of the current process.
+//
+// maxID is the largest node ID the generator accepts (1023 for the default
+// layout). Providers that hold resources, such as a lease, also implement
+// io.Closer; Snowflake.Close releases them.
+type NodeIDProvider interface {
+	NodeID(maxID int64) (int64, error)
+}
+
+// NodeIDProviderFunc adapts a function to a NodeIDProvider.
+type NodeIDProviderFunc func(maxID int64) (int64, error)
+
+// NodeID calls f(maxID).
+func (f NodeIDProviderFunc) NodeID(maxID int64) (int64, error) {
+	return f(maxID)
+}
+
+// EnvNodeID returns a provider that reads the node ID from the environment
+// variable name, e.g. one set from the Downward API or a deployment manifest.
+func EnvNodeID(name string) NodeIDProvider {
+	return NodeIDProviderFunc(func(maxID int64) (int64, error) {
+		v, ok := os.LookupEnv(name)
+		if !ok || v == "" {
+			return 0, fmt.Errorf("%w: environment variable %s is not set", ErrNodeIDUnavailable, name)
+		}
+		id, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %s=%q is not an integer", ErrNodeIDUnavailable, name, v)
+		}
+		return checkNodeID(id, maxID)
+	})
+}
+
+// HostnameNodeID returns a provider that derives the node ID from the
+// trailing number of the hostname, e.g. 3 for the StatefulSet pod "web-3".
+// An empty hostname uses os.Hostname.
+//
+// Only StatefulSet pods have stable, unique ordinals; Deployment pod names
+// end in a random suffix and are rejected.
+func HostnameNodeID(hostname string) NodeIDProvider {
+	return NodeIDProviderFunc(func(maxID int64) (int64, error) {
+		name := hostname
+		if name == "" {
+			var err error
+			if name, err = os.Hostname(); err != nil {
+				return 0, fmt.Errorf("%w: %w", ErrNodeIDUnavailable, err)
+			}
+		}
+		// Strip the domain, e.g. web-3.web.default.svc.cluster.local
+		name, _, _ = strings.Cut(name, ".")
+
+		i := strings.LastIndexFunc(name, func(r rune) bool { return r < '0' || r > '9' })
+		suffix := name[i+1:]
+		if suffix == "" || (i >= 0 && name[i] != '-') {
+			return 0, fmt.Errorf("%w: hostname %q has no ordinal suffix", ErrNodeIDUnavailable, name)
+		}
+		id, err := strconv.ParseInt(suffix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("%w: hostname %q: %w", ErrNodeIDUnavailable, name, err)
+		}
+		return checkNodeID(id, maxID)
+	})
+}
+
+// IPv4NodeID returns a provider that derives the node ID from the lower bits
+// of a private IPv4 address (10.0.0.0/8, 172.16.0.0/12, 192.168.0.0/16 or
+// 100.64.0.0/10). An invalid addr uses the first private address of the
+// host's interfaces.
+//
+// IDs are unique as long as the addresses differ in their lower bits, e.g.
+// within a /22 subnet for the default 10 node bits.
+func IPv4NodeID(addr netip.Addr) NodeIDProvider {
+	return NodeIDProviderFunc(func(maxID int64) (int64, error) {
+		ip := addr
+		if !ip.IsValid() {
+			var err error
+			if ip, err = privateIPv4(); err != nil {
+				return 0, err
+			}
+		}
+		ip = ip.Unmap()
+		if !ip.Is4() || !isPrivateIPv4(ip) {
+			return 0, fmt.Errorf("%w: %s is not a private IPv4 address", ErrNodeIDUnavailable, ip)
+		}
+		b := ip.As4()
+		v := int64(b[0])<<24 | int64(b[1])<<16 | int64(b[2])<<8 | int64(b[3])
+		return v & maxID, nil
+	})
+}
+
+// FirstNodeID returns a provider that tries each provider in order and
+// returns the first node ID resolved, e.g. an explicit environment variable
+// before the hostname ordinal.
+func FirstNodeID(providers ...NodeIDProvider) NodeIDProvider {
+	return NodeIDProviderFunc(func(maxID int64) (int64, error) {
+		errs := make([]error, 0, len(providers))
+		for _, p := range providers {
+			id, err := p.NodeID(maxID)
+			if err == nil {
+				return id, nil
+			}
+			errs = append(errs, err)
+		}
+		if len(errs) == 0 {
+			return 0, fmt.Errorf("%w: no providers", ErrNodeIDUnavailable)
+		}
+		return 0, errors.Join(errs...)
+	})
+}
+
+// checkNodeID reports an error if id is outside [0, maxID].
+func checkNodeID(id, maxID int64) (int64, error) {
+	if id < 0 || id > maxID {
+		return 0, fmt.Errorf("%w: node ID %d out of range [0, %d]", ErrNodeIDUnavailable, id, maxID)
+	}
+	return id, nil
+}
+
+// privatePrefixes are the IPv4 ranges accepted by IPv4NodeID.
+var privatePrefixes = []netip.Prefix{
+	netip.MustParsePrefix("10.0.0.0/8"),
+	netip.MustParsePrefix("172.16.0.0/12"),
+	netip.MustParsePrefix("192.168.0.0/16"),
+	netip.MustParsePrefix("100.64.0.0/10"), // carrier-grade NAT, used by some CNIs
+}
+
+// isPrivateIPv4 reports whether ip is in one of privatePrefixes.
+func isPrivateIPv4(ip netip.Addr) bool {
+	for _, p := range privatePrefixes {
+		if p.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// privateIPv4 returns the first private IPv4 address of the host's interfaces.
+func privateIPv4() (netip.Addr, error) {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("%w: %w", ErrNodeIDUnavailable, err)
+	}
+	for _, a := range addrs {
+		ipnet, ok := a.(*net.IPNet)
+		if !ok {
+			continue
+		}
+		ip, ok := netip.AddrFromSlice(ipnet.IP)
+		if ok && ip.Unmap().Is4() && isPrivateIPv4(ip.Unmap()) {
+			return ip.Unmap(), nil
+		}
+	}
+	return netip.Addr{}, fmt.Errorf("%w: no private IPv4 address found", ErrNodeIDUnavailable)
+}
@@ -0,0 +1,139 @@
+package idgen
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+
+	"github.com/chinayin/gox/idgen/snowflake"
+)
+
+func TestEnvNodeID(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		set     bool
+		want    int64
+		wantErr bool
+	}{
+		{name: "valid", value: "42", set: true, want: 42},
+		{name: "trimmed", value: " 7\n", set: true, want: 7},
+		{name: "unset", set: false, wantErr: true},
+		{name: "empty", value: "", set: true, wantErr: true},
+		{name: "not a number", value: "abc", set: true, wantErr: true},
+		{name: "out of range", value: "1024", set: true, wantErr: true},
+		{name: "negative", value: "-1", set: true, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const key = "GOX_TEST_NODE_ID"
+			if tt.set {
+				t.Setenv(key, tt.value)
+			}
+			got, err := EnvNodeID(key).NodeID(1023)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NodeID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrNodeIDUnavailable) {
+				t.Errorf("NodeID() error = %v, want %v", err, ErrNodeIDUnavailable)
+			}
+			if got != tt.want {
+				t.Errorf("NodeID() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHostnameNodeID(t *testing.T) {
+	tests := []struct {
+		hostname string
+		want     int64
+		wantErr  bool
+	}{
+		{hostname: "web-0", want: 0},
+		{hostname: "web-3", want: 3},
+		{hostname: "kafka-broker-12.kafka.default.svc.cluster.local", want: 12},
+		{hostname: "17", want: 17},
+		{hostname: "web-1024", wantErr: true},
+		{hostname: "api-7d9f8b6c4-x2k9p", wantErr: true},
+		{hostname: "api-7d9f8b6c4-x2k95", wantErr: true},
+		{hostname: "web", wantErr: true},
+		{hostname: "web-99999999999999999999", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.hostname, func(t *testing.T) {
+			got, err := HostnameNodeID(tt.hostname).NodeID(1023)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NodeID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NodeID() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIPv4NodeID(t *testing.T) {
+	tests := []struct {
+		addr    string
+		want    int64
+		wantErr bool
+	}{
+		{addr: "10.0.0.5", want: 5},
+		{addr: "10.0.3.255", want: 1023},
+		{addr: "10.0.4.1", want: 1},
+		{addr: "172.16.1.2", want: 258},
+		{addr: "192.168.0.10", want: 10},
+		{addr: "100.64.2.0", want: 512},
+		{addr: "::ffff:10.0.0.9", want: 9},
+		{addr: "8.8.8.8", wantErr: true},
+		{addr: "fd00::1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.addr, func(t *testing.T) {
+			got, err := IPv4NodeID(netip.MustParseAddr(tt.addr)).NodeID(1023)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NodeID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NodeID() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFirstNodeID(t *testing.T) {
+	fail := NodeIDProviderFunc(func(int64) (int64, error) { return 0, ErrNodeIDUnavailable })
+	fixed := NodeIDProviderFunc(func(int64) (int64, error) { return 9, nil })
+
+	if got, err := FirstNodeID(fail, fixed).NodeID(1023); err != nil || got != 9 {
+		t.Errorf("FirstNodeID() = %d, %v, want 9", got, err)
+	}
+	if _, err := FirstNodeID(fail, fail).NodeID(1023); !errors.Is(err, ErrNodeIDUnavailable) {
+		t.Errorf("FirstNodeID() error = %v, want %v", err, ErrNodeIDUnavailable)
+	}
+	if _, err := FirstNodeID().NodeID(1023); !errors.Is(err, ErrNodeIDUnavailable) {
+		t.Errorf("FirstNodeID() error = %v, want %v", err, ErrNodeIDUnavailable)
+	}
+}
+
+func TestNewSnowflakeWithProvider(t *testing.T) {
+	gen, err := NewSnowflakeWithProvider(HostnameNodeID("web-7"))
+	if err != nil {
+		t.Fatalf("NewSnowflakeWithProvider() error = %v", err)
+	}
+	if gen.NodeID() != 7 {
+		t.Errorf("NodeID() = %d, want 7", gen.NodeID())
+	}
+	sf, ok := gen.Generate().Unwrap().(snowflake.ID)
+	if !ok || sf.Node() != 7 {
+		t.Errorf("generated ID node = %d, want 7", sf.Node())
+	}
+	if err := gen.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+
+	if _, err := NewSnowflakeWithProvider(EnvNodeID("GOX_TEST_UNSET_NODE_ID")); !errors.Is(err, ErrNodeIDUnavailable) {
+		t.Errorf("NewSnowflakeWithProvider() error = %v, want %v", err, ErrNodeIDUnavailable)
+	}
+}
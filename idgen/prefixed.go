@@ -0,0 +1,209 @@
+package idgen
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/chinayin/gox/idgen/snowflake"
+	"github.com/chinayin/gox/idgen/ulid"
+	"github.com/chinayin/gox/idgen/uuidv7"
+)
+
+// maxPrefixLen is the longest type prefix accepted, as in TypeID.
+const maxPrefixLen = 63
+
+// sortableEncoding is the lower case Crockford base32 alphabet. Its
+// characters are in ASCII order, so fixed-width strings sort like the values.
+const sortableEncoding = "0123456789abcdefghjkmnpqrstvwxyz"
+
+var sortableDecoding [256]byte
+
+func init() {
+	for i := range sortableDecoding {
+		sortableDecoding[i] = 0xFF
+	}
+	for i := range len(sortableEncoding) {
+		sortableDecoding[sortableEncoding[i]] = byte(i)
+	}
+}
+
+// Prefixed wraps a Generator to produce self-describing, sortable string IDs
+// in the TypeID style, e.g. "user_01h455vb4pex5vsknk084sn02q".
+//
+// The suffix is the underlying ID in fixed-width lower case Crockford base32:
+// 26 characters for UUIDv7 and ULID IDs, 13 for IDs with an int64 form such
+// as Snowflake. IDs with the same prefix therefore sort as strings in the
+// order of the underlying IDs.
+type Prefixed struct {
+	prefix string
+	gen    Generator
+}
+
+// NewPrefixed returns a generator that prefixes the IDs of gen with prefix.
+//
+// The prefix may contain lower case ASCII letters and underscores, must not
+// start or end with an underscore and is at most 63 characters long. An empty
+// prefix produces the bare suffix.
+func NewPrefixed(prefix string, gen Generator) (*Prefixed, error) {
+	if err := validatePrefix(prefix); err != nil {
+		return nil, err
+	}
+	return &Prefixed{prefix: prefix, gen: gen}, nil
+}
+
+// Prefix returns the type prefix.
+func (p *Prefixed) Prefix() string {
+	return p.prefix
+}
+
+// Generate creates a new unique ID. Its String is the prefixed form; Int64
+// and Unwrap are those of the underlying ID.
+func (p *Prefixed) Generate() ID {
+	id := p.gen.Generate()
+	return NewID(id.Int64(), p.Format(id), id.Unwrap())
+}
+
+// Format returns the prefixed form of an ID generated by the wrapped generator.
+func (p *Prefixed) Format(id ID) string {
+	var suffix string
+	switch raw := id.Unwrap().(type) {
+	case uuidv7.UUID:
+		suffix = encodeSortable(raw[:])
+	case ulid.ULID:
+		suffix = encodeSortable(raw[:])
+	default:
+		suffix = encodeSortable(binary.BigEndian.AppendUint64(nil, uint64(id.Int64())))
+	}
+	if p.prefix == "" {
+		return suffix
+	}
+	return p.prefix + "_" + suffix
+}
+
+// Parse validates the prefix of s and returns the underlying ID.
+//
+// The suffix must have the width of the wrapped generator's IDs: 26
+// characters for UUIDv7 and ULID, 13 for Snowflake and any other generator,
+// whose IDs are taken to be int64. The ID is rebuilt as a uuidv7.UUID, a
+// ulid.ULID or a snowflake.ID; for other generators the result has the
+// decimal String and no underlying value.
+func (p *Prefixed) Parse(s string) (ID, error) {
+	prefix, suffix := "", s
+	if i := strings.LastIndexByte(s, '_'); i >= 0 {
+		prefix, suffix = s[:i], s[i+1:]
+	}
+	if prefix != p.prefix {
+		return ID{}, fmt.Errorf("%w: %q does not have prefix %q", ErrInvalidPrefixedID, s, p.prefix)
+	}
+
+	switch gen := p.base().(type) {
+	case *UUIDv7:
+		b, ok := decodeSortable(suffix, 16)
+		if !ok {
+			break
+		}
+		u, err := uuidv7.FromBytes(b)
+		if err != nil {
+			return ID{}, fmt.Errorf("%w: %q: %w", ErrInvalidPrefixedID, s, err)
+		}
+		return uuidv7ID(u), nil
+	case *ULID:
+		b, ok := decodeSortable(suffix, 16)
+		if !ok {
+			break
+		}
+		u, err := ulid.FromBytes(b)
+		if err != nil {
+			return ID{}, fmt.Errorf("%w: %q: %w", ErrInvalidPrefixedID, s, err)
+		}
+		return ulidID(u), nil
+	default:
+		b, ok := decodeSortable(suffix, 8)
+		if !ok {
+			break
+		}
+		v := int64(binary.BigEndian.Uint64(b))
+		if _, ok := gen.(*Snowflake); ok {
+			return snowflakeID(snowflake.ParseInt64(v)), nil
+		}
+		return NewID(v, strconv.FormatInt(v, 10), nil), nil
+	}
+	return ID{}, fmt.Errorf("%w: %q has an invalid suffix", ErrInvalidPrefixedID, s)
+}
+
+// base returns the innermost wrapped generator, skipping nested Prefixed.
+func (p *Prefixed) base() Generator {
+	gen := p.gen
+	for {
+		inner, ok := gen.(*Prefixed)
+		if !ok {
+			return gen
+		}
+		gen = inner.gen
+	}
+}
+
+// validatePrefix reports an error if prefix is not a valid type prefix.
+func validatePrefix(prefix string) error {
+	if len(prefix) > maxPrefixLen {
+		return fmt.Errorf("%w: %q is longer than %d characters", ErrInvalidPrefix, prefix, maxPrefixLen)
+	}
+	if strings.HasPrefix(prefix, "_") || strings.HasSuffix(prefix, "_") {
+		return fmt.Errorf("%w: %q starts or ends with an underscore", ErrInvalidPrefix, prefix)
+	}
+	for i := range len(prefix) {
+		if c := prefix[i]; (c < 'a' || c > 'z') && c != '_' {
+			return fmt.Errorf("%w: %q contains %q", ErrInvalidPrefix, prefix, c)
+		}
+	}
+	return nil
+}
+
+// encodeSortable encodes b in fixed-width sortableEncoding, padding the most
+// significant character with zero bits.
+func encodeSortable(b []byte) string {
+	out := make([]byte, (len(b)*8+4)/5)
+	var acc, bits uint
+	j := len(out)
+	for i := len(b) - 1; i >= 0; i-- {
+		acc |= uint(b[i]) << bits
+		bits += 8
+		for ; bits >= 5; bits -= 5 {
+			j--
+			out[j] = sortableEncoding[acc&0x1F]
+			acc >>= 5
+		}
+	}
+	if bits > 0 {
+		out[0] = sortableEncoding[acc&0x1F]
+	}
+	return string(out)
+}
+
+// decodeSortable decodes the fixed-width encoding of n bytes. It reports
+// false for invalid characters and values that overflow n bytes.
+func decodeSortable(s string, n int) ([]byte, bool) {
+	if len(s) != (n*8+4)/5 {
+		return nil, false
+	}
+	out := make([]byte, n)
+	var acc, bits uint
+	j := n
+	for i := len(s) - 1; i >= 0; i-- {
+		d := sortableDecoding[s[i]]
+		if d == 0xFF {
+			return nil, false
+		}
+		acc |= uint(d) << bits
+		bits += 5
+		if bits >= 8 && j > 0 {
+			j--
+			out[j] = byte(acc)
+			acc >>= 8
+			bits -= 8
+		}
+	}
+	return out, acc == 0
+}
@@ -0,0 +1,198 @@
+package idgen
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/chinayin/gox/idgen/snowflake"
+	"github.com/chinayin/gox/idgen/ulid"
+	"github.com/chinayin/gox/idgen/uuidv7"
+)
+
+func TestNewPrefixed_InvalidPrefix(t *testing.T) {
+	gen := NewUUIDv7()
+	for _, prefix := range []string{"User", "user-id", "_user", "user_", "user1", strings.Repeat("a", 64)} {
+		if _, err := NewPrefixed(prefix, gen); !errors.Is(err, ErrInvalidPrefix) {
+			t.Errorf("NewPrefixed(%q) error = %v, want %v", prefix, err, ErrInvalidPrefix)
+		}
+	}
+	for _, prefix := range []string{"", "user", "user_account", strings.Repeat("a", 63)} {
+		if _, err := NewPrefixed(prefix, gen); err != nil {
+			t.Errorf("NewPrefixed(%q) error = %v", prefix, err)
+		}
+	}
+}
+
+func TestPrefixed_TypeIDVector(t *testing.T) {
+	// TypeID specification example
+	p, _ := NewPrefixed("user", NewUUIDv7())
+	u := uuidv7.MustParse("01890a5d-ac96-774b-bcce-b302099a8057")
+	const want = "user_01h455vb4pex5vsknk084sn02q"
+
+	if got := p.Format(uuidv7ID(u)); got != want {
+		t.Errorf("Format() = %s, want %s", got, want)
+	}
+	id, err := p.Parse(want)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	if id.Unwrap() != u || id.String() != u.String() {
+		t.Errorf("Parse() = %v, want %v", id, u)
+	}
+}
+
+func TestPrefixed_RoundTrip(t *testing.T) {
+	sf, _ := NewSnowflake(1)
+	tests := []struct {
+		name   string
+		gen    Generator
+		length int
+	}{
+		{name: "snowflake", gen: sf, length: len("order_") + 13},
+		{name: "uuidv7", gen: NewUUIDv7(), length: len("order_") + 26},
+		{name: "ulid", gen: NewULID(), length: len("order_") + 26},
+		{name: "plain", gen: &plainGenerator{n: 1 << 40}, length: len("order_") + 13},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewPrefixed("order", tt.gen)
+			if err != nil {
+				t.Fatalf("NewPrefixed() error = %v", err)
+			}
+
+			var strs []string
+			for range 100 {
+				id := p.Generate()
+				s := id.String()
+				if len(s) != tt.length || !strings.HasPrefix(s, "order_") {
+					t.Fatalf("Generate().String() = %s, want order_ and length %d", s, tt.length)
+				}
+
+				got, err := p.Parse(s)
+				if err != nil {
+					t.Fatalf("Parse(%s) error = %v", s, err)
+				}
+				if got.Int64() != id.Int64() || got.Unwrap() != id.Unwrap() {
+					t.Fatalf("Parse(%s) = %v, want underlying ID of %v", s, got, id)
+				}
+				if p.Format(got) != s {
+					t.Fatalf("Format(Parse(%s)) = %s", s, p.Format(got))
+				}
+				strs = append(strs, s)
+			}
+
+			// Strings sort in generation order
+			if !slices.IsSorted(strs) {
+				t.Errorf("prefixed IDs are not sorted: %v", strs)
+			}
+		})
+	}
+}
+
+func TestPrefixed_JSONRoundTrip(t *testing.T) {
+	sf, _ := NewSnowflake(1)
+	p, err := NewPrefixed("user", sf)
+	if err != nil {
+		t.Fatalf("NewPrefixed() error = %v", err)
+	}
+	id := p.Generate()
+
+	// JSON keeps the prefix, like String and MarshalText
+	b, err := json.Marshal(id)
+	if err != nil || string(b) != `"`+id.String()+`"` {
+		t.Fatalf("json.Marshal() = %s, %v, want %q", b, err, id.String())
+	}
+	text, _ := id.MarshalText()
+	if string(text) != id.String() {
+		t.Errorf("MarshalText() = %s, want %s", text, id.String())
+	}
+
+	var out ID
+	if err := json.Unmarshal(b, &out); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	got, err := p.Parse(out.String())
+	if err != nil {
+		t.Fatalf("Parse(%s) error = %v", out, err)
+	}
+	if got.Int64() != id.Int64() {
+		t.Errorf("Parse() = %d, want %d", got.Int64(), id.Int64())
+	}
+}
+
+func TestPrefixed_ParseUnwrap(t *testing.T) {
+	sf, _ := NewSnowflake(1)
+	ps, _ := NewPrefixed("a", sf)
+	if id, _ := ps.Parse(ps.Generate().String()); id.Unwrap() == nil {
+		t.Error("Parse() should unwrap to snowflake.ID")
+	} else if _, ok := id.Unwrap().(snowflake.ID); !ok {
+		t.Errorf("Parse().Unwrap() type = %T, want snowflake.ID", id.Unwrap())
+	}
+
+	pu, _ := NewPrefixed("a", NewULID())
+	if id, _ := pu.Parse(pu.Generate().String()); id.Unwrap() == nil {
+		t.Error("Parse() should unwrap to ulid.ULID")
+	} else if _, ok := id.Unwrap().(ulid.ULID); !ok {
+		t.Errorf("Parse().Unwrap() type = %T, want ulid.ULID", id.Unwrap())
+	}
+}
+
+func TestPrefixed_ParseInvalid(t *testing.T) {
+	p, _ := NewPrefixed("user", NewUUIDv7())
+	bare, _ := NewPrefixed("", NewUUIDv7())
+	sf, _ := NewSnowflake(1)
+	ps, _ := NewPrefixed("user", sf)
+	pu, _ := NewPrefixed("user", NewULID())
+
+	tests := []struct {
+		name  string
+		p     *Prefixed
+		input string
+	}{
+		{name: "wrong prefix", p: p, input: "order_01h455vb4pex5vsknk084sn02q"},
+		{name: "missing prefix", p: p, input: "01h455vb4pex5vsknk084sn02q"},
+		{name: "unexpected prefix", p: bare, input: "user_01h455vb4pex5vsknk084sn02q"},
+		{name: "upper case", p: p, input: "user_01H455VB4PEX5VSKNK084SN02Q"},
+		{name: "invalid character", p: p, input: "user_01h455vb4pex5vsknk084sn02u"},
+		{name: "overflow", p: p, input: "user_81h455vb4pex5vsknk084sn02q"},
+		{name: "wrong length", p: p, input: "user_01h455vb4pex5vsknk084sn02"},
+		{name: "not version 7", p: p, input: "user_00000000000000000000000000"},
+		{name: "empty", p: p, input: ""},
+		{name: "int64 suffix for UUIDv7", p: p, input: "user_0000000000001"},
+		{name: "int64 suffix for ULID", p: pu, input: "user_0000000000001"},
+		{name: "128-bit suffix for Snowflake", p: ps, input: "user_01h455vb4pex5vsknk084sn02q"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.p.Parse(tt.input); !errors.Is(err, ErrInvalidPrefixedID) {
+				t.Errorf("Parse(%q) error = %v, want %v", tt.input, err, ErrInvalidPrefixedID)
+			}
+		})
+	}
+}
+
+func TestEncodeSortable(t *testing.T) {
+	tests := []struct {
+		in   []byte
+		want string
+	}{
+		{in: make([]byte, 8), want: "0000000000000"},
+		{in: []byte{0, 0, 0, 0, 0, 0, 0, 1}, want: "0000000000001"},
+		{in: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, want: "fzzzzzzzzzzzz"},
+	}
+	for _, tt := range tests {
+		got := encodeSortable(tt.in)
+		if got != tt.want {
+			t.Errorf("encodeSortable(%x) = %s, want %s", tt.in, got, tt.want)
+		}
+		if b, ok := decodeSortable(got, len(tt.in)); !ok || string(b) != string(tt.in) {
+			t.Errorf("decodeSortable(%s) = %x, %v, want %x", got, b, ok, tt.in)
+		}
+	}
+	if _, ok := decodeSortable("g000000000000", 8); ok {
+		t.Error("decodeSortable() should reject values overflowing 64 bits")
+	}
+}
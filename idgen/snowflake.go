@@ -1,6 +1,8 @@
 package idgen
 
 import (
+	"io"
+
 	"github.com/chinayin/gox/idgen/snowflake"
 )
 
@@ -9,7 +11,16 @@ const DefaultNodeID int64 = 1
 
 // Snowflake wraps the snowflake.Node to implement the Generator interface.
 type Snowflake struct {
-	node *snowflake.Node
+	node     *snowflake.Node
+	nodeID   int64
+	provider NodeIDProvider
+	holder   nodeIDHolder // nil if the provider cannot lose the node ID
+}
+
+// nodeIDHolder is implemented by providers that can lose their node ID, such
+// as a Lease.
+type nodeIDHolder interface {
+	Held() bool
 }
 
 // NewSnowflake creates a new Snowflake ID generator.
@@ -33,11 +44,137 @@ func NewSnowflake(nodeID ...int64) (*Snowflake, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &Snowflake{node: node}, nil
+	return &Snowflake{node: node, nodeID: id}, nil
+}
+
+// NewSnowflakeWithOptions creates a new Snowflake ID generator for nodeID
+// with options for the underlying snowflake.Node, e.g. snowflake.WithStats
+// to enable Stats:
+//
+//	gen, err := idgen.NewSnowflakeWithOptions(cfg.NodeID, snowflake.WithStats())
+func NewSnowflakeWithOptions(nodeID int64, opts ...snowflake.Option) (*Snowflake, error) {
+	node, err := snowflake.NewNode(nodeID, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Snowflake{node: node, nodeID: nodeID}, nil
+}
+
+// NewSnowflakeWithProvider creates a new Snowflake ID generator whose node ID
+// is resolved by p, e.g. from the pod ordinal or a lease. opts configure the
+// underlying snowflake.Node.
+//
+// Example:
+//
+//	// Kubernetes: NODE_ID if set, otherwise the StatefulSet ordinal
+//	gen, err := idgen.NewSnowflakeWithProvider(idgen.FirstNodeID(
+//	    idgen.EnvNodeID("NODE_ID"),
+//	    idgen.HostnameNodeID(""),
+//	))
+//
+//	// Shared volume: lease a free node ID, released by Close
+//	lease, err := idgen.NewLease("/shared/idgen", idgen.LeaseOptions{})
+//	gen, err := idgen.NewSnowflakeWithProvider(lease)
+//	defer gen.Close()
+//
+// If p can lose its node ID, like a Lease, the generator stops once it is
+// lost, since IDs generated afterwards may collide with the new holder's:
+// GenerateE returns ErrNodeIDLost, while Generate and GenerateN PANIC with
+// it. Generators backed by a Lease should therefore use GenerateE, or
+// recover from the panic.
+func NewSnowflakeWithProvider(p NodeIDProvider, opts ...snowflake.Option) (*Snowflake, error) {
+	id, err := p.NodeID(snowflake.DefaultLayout().MaxNode())
+	if err != nil {
+		return nil, err
+	}
+	node, err := snowflake.NewNode(id, opts...)
+	if err != nil {
+		_ = closeProvider(p)
+		return nil, err
+	}
+	s := &Snowflake{node: node, nodeID: id, provider: p}
+	if h, ok := p.(nodeIDHolder); ok {
+		s.holder = h
+	}
+	return s, nil
+}
+
+// NodeID returns the node ID of the generator.
+func (s *Snowflake) NodeID() int64 {
+	return s.nodeID
+}
+
+// Stats returns a snapshot of the generator's counters. Generated, StepWaits
+// and WaitTime stay zero unless the generator was created with
+// snowflake.WithStats.
+func (s *Snowflake) Stats() snowflake.Stats {
+	return s.node.Stats()
+}
+
+// Close releases the node ID if the generator's NodeIDProvider holds one,
+// such as a Lease. The generator must not be used afterwards.
+func (s *Snowflake) Close() error {
+	return closeProvider(s.provider)
+}
+
+// closeProvider closes p if it implements io.Closer.
+func closeProvider(p NodeIDProvider) error {
+	if c, ok := p.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
 }
 
 // Generate creates a new unique ID.
+// It panics with ErrNodeIDLost once the provider's lease is lost; use
+// GenerateE to handle that case.
 func (s *Snowflake) Generate() ID {
-	sf := s.node.Generate()
+	s.mustHoldNodeID()
+	return snowflakeID(s.node.Generate())
+}
+
+// GenerateE creates a new unique ID, returning ErrNodeIDLost once the
+// provider's lease is lost and the errors of snowflake.Node.GenerateE.
+func (s *Snowflake) GenerateE() (ID, error) {
+	if s.nodeIDLost() {
+		return ID{}, ErrNodeIDLost
+	}
+	sf, err := s.node.GenerateE()
+	if err != nil {
+		return ID{}, err
+	}
+	return snowflakeID(sf), nil
+}
+
+// GenerateN creates n unique IDs in increasing order, reserving a whole
+// millisecond's sequence range per lock acquisition. Like Generate, it
+// panics with ErrNodeIDLost once the provider's lease is lost.
+func (s *Snowflake) GenerateN(n int) []ID {
+	s.mustHoldNodeID()
+	sfs := s.node.GenerateN(n)
+	if sfs == nil {
+		return nil
+	}
+	ids := make([]ID, len(sfs))
+	for i, sf := range sfs {
+		ids[i] = snowflakeID(sf)
+	}
+	return ids
+}
+
+// nodeIDLost reports whether the provider has lost the node ID.
+func (s *Snowflake) nodeIDLost() bool {
+	return s.holder != nil && !s.holder.Held()
+}
+
+// mustHoldNodeID panics with ErrNodeIDLost if the provider has lost the node
+// ID: IDs generated afterwards may collide with the new holder's.
+func (s *Snowflake) mustHoldNodeID() {
+	if s.nodeIDLost() {
+		panic(ErrNodeIDLost)
+	}
+}
+
+func snowflakeID(sf snowflake.ID) ID {
 	return NewID(sf.Int64(), sf.String(), sf)
 }
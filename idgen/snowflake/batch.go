@@ -0,0 +1,87 @@
+package snowflake
+
+// WithLockFree makes the node reserve steps with a compare-and-swap loop on
+// a single atomic word instead of the mutex. It scales better when many
+// goroutines share one node; under low contention the mutex is as fast.
+func WithLockFree() Option {
+	return func(n *Node) {
+		n.lockFree = true
+	}
+}
+
+// GenerateN creates and returns count unique snowflake IDs in increasing
+// order. It reserves the remaining steps of a millisecond at once, so it
+// synchronizes once per millisecond instead of once per ID.
+//
+// Like Generate, it never fails; clock rollbacks fall back to RollbackLogical
+// where the policy would fail. It returns nil if count is not positive.
+func (n *Node) GenerateN(count int) []ID {
+	if count <= 0 {
+		return nil
+	}
+
+	ids := make([]ID, 0, count)
+	for len(ids) < count {
+		now, step, k, _ := n.reserve(int64(count-len(ids)), false)
+		for i := range k {
+			ids = append(ids, n.compose(now, step+i))
+		}
+	}
+	return ids
+}
+
+// compose builds the ID for a timestamp and step of the node.
+func (n *Node) compose(now, step int64) ID {
+	return ID(now<<n.timeShift | n.node<<n.nodeShift | step)
+}
+
+// reserve claims up to k consecutive steps within one millisecond. It returns
+// the timestamp, the first step and the number of steps claimed (at least 1).
+// When strict is true, rollbacks the policy does not allow return an error.
+func (n *Node) reserve(k int64, strict bool) (now, step, count int64, err error) {
+	if n.lockFree {
+		return n.reserveCAS(k, strict)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if now, err = n.checkClock(n.clock(), n.time, strict); err != nil {
+		return 0, 0, 0, err
+	}
+	now, step, count = n.claim(now, n.time, n.step, k)
+	n.time, n.step = now, step+count-1
+	n.stats.addGenerated(count)
+	return now, step, count, nil
+}
+
+// reserveCAS is reserve without the mutex. The last timestamp and step are
+// packed into n.state and advanced with compare-and-swap.
+func (n *Node) reserveCAS(k int64, strict bool) (now, step, count int64, err error) {
+	for {
+		state := n.state.Load()
+		last, lastStep := int64(state>>n.nodeShift), int64(state)&n.stepMask
+
+		if now, err = n.checkClock(n.clock(), last, strict); err != nil {
+			return 0, 0, 0, err
+		}
+		now, step, count = n.claim(now, last, lastStep, k)
+		if n.state.CompareAndSwap(state, uint64(now)<<n.nodeShift|uint64(step+count-1)) {
+			n.stats.addGenerated(count)
+			return now, step, count, nil
+		}
+	}
+}
+
+// claim computes the steps to hand out at now (not earlier than last), given
+// the last timestamp and step used.
+func (n *Node) claim(now, last, lastStep, k int64) (int64, int64, int64) {
+	var step int64
+	if now == last {
+		step = lastStep + 1
+		if step > n.stepMask {
+			now, step = n.nextTime(last), 0
+		}
+	}
+	return now, step, min(k, n.stepMask-step+1)
+}
@@ -0,0 +1,186 @@
+package snowflake
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestGenerateN(t *testing.T) {
+	for _, lockFree := range []bool{false, true} {
+		var opts []Option
+		if lockFree {
+			opts = append(opts, WithLockFree())
+		}
+		node, err := NewNode(1, opts...)
+		if err != nil {
+			t.Fatalf("NewNode() error = %v", err)
+		}
+
+		// 跨越多个毫秒的 step 区间
+		ids := node.GenerateN(10000)
+		if len(ids) != 10000 {
+			t.Fatalf("GenerateN() len = %d, want 10000", len(ids))
+		}
+		for i := 1; i < len(ids); i++ {
+			if ids[i] <= ids[i-1] {
+				t.Fatalf("lockFree=%v: ID %d not greater than %d", lockFree, ids[i], ids[i-1])
+			}
+		}
+		if next := node.Generate(); next <= ids[len(ids)-1] {
+			t.Errorf("lockFree=%v: Generate() = %d after batch ending %d", lockFree, next, ids[len(ids)-1])
+		}
+	}
+}
+
+func TestGenerateN_Empty(t *testing.T) {
+	node, _ := NewNode(1)
+	for _, n := range []int{0, -1} {
+		if ids := node.GenerateN(n); ids != nil {
+			t.Errorf("GenerateN(%d) = %v, want nil", n, ids)
+		}
+	}
+}
+
+func TestGenerateN_Rollback(t *testing.T) {
+	node, now := newFakeClockNode(t)
+	layout := node.Layout()
+
+	last := node.Generate()
+	now.Store(900)
+
+	// StepBits=2：回拨期间借用逻辑毫秒，仍保持递增
+	ids := node.GenerateN(10)
+	for _, id := range ids {
+		if id <= last {
+			t.Fatalf("ID %d is not increasing after %d", id, last)
+		}
+		last = id
+	}
+	if got := layout.Decode(last).Time - layout.Epoch; got != 1002 {
+		t.Errorf("logical time = %d, want 1002", got)
+	}
+	if got := node.Rollbacks(); got != 1 {
+		t.Errorf("Rollbacks() = %d, want 1", got)
+	}
+}
+
+func TestGenerate_LockFreeConcurrent(t *testing.T) {
+	node, err := NewNode(1, WithLockFree())
+	if err != nil {
+		t.Fatalf("NewNode() error = %v", err)
+	}
+
+	const workers, perWorker = 8, 5000
+	results := make([][]ID, workers)
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids := make([]ID, 0, perWorker)
+			for i := range perWorker {
+				if i%2 == 0 {
+					ids = append(ids, node.Generate())
+				} else {
+					ids = append(ids, node.GenerateN(3)...)
+				}
+			}
+			results[w] = ids
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[ID]bool, workers*perWorker*2)
+	for _, ids := range results {
+		for i, id := range ids {
+			if seen[id] {
+				t.Fatalf("duplicate ID %d", id)
+			}
+			seen[id] = true
+			if i > 0 && id <= ids[i-1] {
+				t.Fatalf("ID %d not greater than %d within one goroutine", id, ids[i-1])
+			}
+		}
+	}
+}
+
+// newBenchNode 创建 step 位宽足够大的 Node，避免默认布局每毫秒 4096 个 ID
+// 的上限掩盖加锁开销
+func newBenchNode(b *testing.B, opts ...Option) *Node {
+	b.Helper()
+	node, err := NewNodeWithLayout(1, Layout{Epoch: Epoch, NodeBits: 1, StepBits: 21}, opts...)
+	if err != nil {
+		b.Fatalf("NewNodeWithLayout() error = %v", err)
+	}
+	return node
+}
+
+func BenchmarkGenerate_PerCall(b *testing.B) {
+	node := newBenchNode(b)
+
+	b.ReportAllocs()
+
+	b.ResetTimer()
+	for range b.N {
+		_ = node.Generate()
+	}
+}
+
+func BenchmarkGenerateN(b *testing.B) {
+	node := newBenchNode(b)
+
+	b.ReportAllocs()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i += 1000 {
+		_ = node.GenerateN(1000)
+	}
+}
+
+func BenchmarkGenerateN_LockFree(b *testing.B) {
+	node := newBenchNode(b, WithLockFree())
+
+	b.ReportAllocs()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i += 1000 {
+		_ = node.GenerateN(1000)
+	}
+}
+
+func BenchmarkGenerate_LockFree(b *testing.B) {
+	node := newBenchNode(b, WithLockFree())
+
+	b.ReportAllocs()
+
+	b.ResetTimer()
+	for range b.N {
+		_ = node.Generate()
+	}
+}
+
+func BenchmarkGenerate_Parallel(b *testing.B) {
+	node := newBenchNode(b)
+
+	b.ReportAllocs()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = node.Generate()
+		}
+	})
+}
+
+func BenchmarkGenerate_ParallelLockFree(b *testing.B) {
+	node := newBenchNode(b, WithLockFree())
+
+	b.ReportAllocs()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_ = node.Generate()
+		}
+	})
+}
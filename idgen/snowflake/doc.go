@@ -32,4 +32,88 @@
 //	fmt.Println(id.Time())
 //	fmt.Println(id.Node())
 //	fmt.Println(id.Step())
+//
+// # Encodings
+//
+// Base2, Base32, Base36, Base58 and String are variable-width and do not sort
+// in ID order; Bytes and Base64 encode the decimal string. For the 8-byte big
+// endian integer and for strings that sort in ID order, use:
+//
+//	id.Base64URL()      // 11 characters, unpadded base64url of the 8 bytes
+//	id.Crockford32()    // 13 characters, sortable
+//	id.SortableBase58() // 11 characters, sortable, Bitcoin alphabet
+//	id.SortableBase62() // 11 characters, sortable
+//
+// The sortable forms are zero-padded and treat the ID as unsigned. Their
+// parsers reject wrong lengths and values that overflow 64 bits.
+//
+// # Serialization
+//
+// ID implements sql.Scanner and driver.Valuer (stored as BIGINT), text and
+// binary (8-byte big endian) marshaling, and JSON. JSON uses quoted strings
+// because JavaScript numbers lose precision above 2^53; NumberID marshals to
+// bare numbers instead. Both unmarshal from either form.
+//
+// # Custom Layout
+//
+// NewNode uses the layout described by the package-level Epoch, NodeBits and
+// StepBits variables, and ID.Time, ID.Node and ID.Step decode with them.
+// When generators with different layouts share a process, give each node its
+// own Layout and decode with it:
+//
+//	layout := snowflake.Layout{Epoch: epochMillis, NodeBits: 16, StepBits: 6}
+//	node, err := snowflake.NewNodeWithLayout(42, layout)
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+//	parts := layout.Decode(node.Generate())
+//	fmt.Println(parts.Time, parts.Node, parts.Step)
+//
+// Set Layout.UseSignBit to let the timestamp use the sign bit, doubling the
+// lifetime at the cost of IDs becoming negative as int64. Negative IDs
+// encode with a leading '-' in String, Base2, Base32, Base36 and Base58; the
+// fixed-width encodings (Crockford32, SortableBase58, SortableBase62) encode
+// the unsigned 64-bit value.
+//
+// # Clock Rollback
+//
+// Nodes read the wall clock, so a rollback happens when the system time is
+// stepped back, e.g. by NTP or by hand. When the clock reads earlier than
+// the last generated ID, the node applies its RollbackPolicy:
+// RollbackLogical (default) continues from the last timestamp, RollbackWait
+// sleeps out rollbacks within a tolerance, and RollbackError fails. Generate never fails and falls back to the logical
+// clock; GenerateE returns ErrClockRollback instead:
+//
+//	node, _ := snowflake.NewNode(1, snowflake.WithRollbackPolicy(snowflake.RollbackWait, 50*time.Millisecond))
+//
+//	id, err := node.GenerateE()
+//	if errors.Is(err, snowflake.ErrClockRollback) {
+//	    // retry later or alert
+//	}
+//
+//	rollbacks.Set(float64(node.Rollbacks())) // export for alerting
+//
+// # Batch Generation
+//
+// Generate synchronizes once per ID. GenerateN reserves the remaining steps
+// of a millisecond at once and fills the slice outside the lock, which suits
+// bulk imports:
+//
+//	ids := node.GenerateN(10000)
+//
+// WithLockFree replaces the mutex with a compare-and-swap loop on a single
+// atomic word, which helps when many goroutines share one node:
+//
+//	node, _ := snowflake.NewNode(1, snowflake.WithLockFree())
+//
+// # Stats
+//
+// WithStats counts generated IDs and the waits for the next millisecond when
+// the step space is exhausted, a sign that the node is saturated. Stats
+// returns a snapshot together with the rollback counters:
+//
+//	node, _ := snowflake.NewNode(1, snowflake.WithStats())
+//	st := node.Stats()
+//	fmt.Println(st.Generated, st.StepWaits, st.WaitTime, st.Rollbacks)
 package snowflake
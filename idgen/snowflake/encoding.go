@@ -0,0 +1,82 @@
+package snowflake
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+)
+
+// NumberID is an ID that marshals to JSON as a bare number, e.g. 1234,
+// instead of the quoted string used by ID. Use it for fields whose consumers
+// expect numbers and do not lose precision above 2^53:
+//
+//	type Order struct {
+//		ID snowflake.NumberID `json:"id"`
+//	}
+type NumberID ID
+
+// MarshalJSON writes the ID as a bare JSON number.
+func (f NumberID) MarshalJSON() ([]byte, error) {
+	return strconv.AppendInt(make([]byte, 0, 20), int64(f), 10), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a quoted decimal string.
+func (f *NumberID) UnmarshalJSON(b []byte) error {
+	return (*ID)(f).UnmarshalJSON(b)
+}
+
+// MarshalText implements encoding.TextMarshaler using the decimal form.
+func (f ID) MarshalText() ([]byte, error) {
+	return strconv.AppendInt(nil, int64(f), 10), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler for the decimal form.
+func (f *ID) UnmarshalText(b []byte) error {
+	id, err := ParseBytes(b)
+	if err != nil {
+		return fmt.Errorf("invalid snowflake ID %q: %w", b, err)
+	}
+	*f = id
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler using the 8-byte big
+// endian form returned by IntBytes.
+func (f ID) MarshalBinary() ([]byte, error) {
+	b := f.IntBytes()
+	return b[:], nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler for the 8-byte big
+// endian form.
+func (f *ID) UnmarshalBinary(b []byte) error {
+	if len(b) != 8 {
+		return fmt.Errorf("invalid snowflake ID: %d bytes, want 8", len(b))
+	}
+	*f = ID(int64(binary.BigEndian.Uint64(b))) //nolint:gosec // G115: snowflake IDs fit in int64
+	return nil
+}
+
+// Value implements driver.Valuer, storing the ID as an integer (BIGINT).
+func (f ID) Value() (driver.Value, error) {
+	return int64(f), nil
+}
+
+// Scan implements sql.Scanner. It accepts integers and decimal text; NULL
+// scans as 0.
+func (f *ID) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*f = 0
+	case int64:
+		*f = ID(v)
+	case []byte:
+		return f.UnmarshalText(v)
+	case string:
+		return f.UnmarshalText([]byte(v))
+	default:
+		return fmt.Errorf("cannot scan %T into snowflake ID", src)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package snowflake
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"testing"
+)
+
+var (
+	_ encoding.TextMarshaler     = ID(0)
+	_ encoding.TextUnmarshaler   = (*ID)(nil)
+	_ encoding.BinaryMarshaler   = ID(0)
+	_ encoding.BinaryUnmarshaler = (*ID)(nil)
+	_ driver.Valuer              = ID(0)
+	_ sql.Scanner                = (*ID)(nil)
+)
+
+func TestID_Text(t *testing.T) {
+	id := ID(1116823421972381696)
+
+	b, err := id.MarshalText()
+	if err != nil || string(b) != "1116823421972381696" {
+		t.Fatalf("MarshalText() = %s, %v, want 1116823421972381696", b, err)
+	}
+	var got ID
+	if err := got.UnmarshalText(b); err != nil || got != id {
+		t.Errorf("UnmarshalText() = %d, %v, want %d", got, err, id)
+	}
+	if err := got.UnmarshalText([]byte("abc")); err == nil {
+		t.Error("UnmarshalText() should reject non-decimal input")
+	}
+
+	// 作为 map key 时使用文本形式
+	out, _ := json.Marshal(map[ID]bool{id: true})
+	if string(out) != `{"1116823421972381696":true}` {
+		t.Errorf("json.Marshal(map) = %s", out)
+	}
+}
+
+func TestID_Binary(t *testing.T) {
+	id := ID(13587)
+
+	b, err := id.MarshalBinary()
+	if err != nil || !bytes.Equal(b, []byte{0, 0, 0, 0, 0, 0, 0x35, 0x13}) {
+		t.Fatalf("MarshalBinary() = %x, %v", b, err)
+	}
+	var got ID
+	if err := got.UnmarshalBinary(b); err != nil || got != id {
+		t.Errorf("UnmarshalBinary() = %d, %v, want %d", got, err, id)
+	}
+	if err := got.UnmarshalBinary([]byte("13587")); err == nil {
+		t.Error("UnmarshalBinary() should reject input that is not 8 bytes")
+	}
+}
+
+func TestID_SQL(t *testing.T) {
+	id := ID(13587)
+	if v, err := id.Value(); err != nil || v != int64(13587) {
+		t.Errorf("Value() = %v, %v, want int64 13587", v, err)
+	}
+
+	tests := []struct {
+		name    string
+		src     any
+		want    ID
+		wantErr bool
+	}{
+		{name: "int64", src: int64(13587), want: 13587},
+		{name: "bytes", src: []byte("13587"), want: 13587},
+		{name: "string", src: "13587", want: 13587},
+		{name: "null", src: nil, want: 0},
+		{name: "invalid text", src: "abc", wantErr: true},
+		{name: "unsupported type", src: 1.5, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ID(1)
+			err := got.Scan(tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Scan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("Scan() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNumberID_JSON(t *testing.T) {
+	id := ID(13587)
+	b, err := json.Marshal(struct {
+		ID  NumberID
+		Str ID
+	}{NumberID(id), id})
+	if err != nil || string(b) != `{"ID":13587,"Str":"13587"}` {
+		t.Fatalf("json.Marshal() = %s, %v, want {\"ID\":13587,\"Str\":\"13587\"}", b, err)
+	}
+
+	// 数字与字符串形式均可解析，与字段类型无关
+	for _, in := range []string{`13587`, `"13587"`} {
+		var got NumberID
+		if err := json.Unmarshal([]byte(in), &got); err != nil || got != NumberID(id) {
+			t.Errorf("json.Unmarshal(%s) into NumberID = %d, %v, want %d", in, got, err, id)
+		}
+		var plain ID
+		if err := json.Unmarshal([]byte(in), &plain); err != nil || plain != id {
+			t.Errorf("json.Unmarshal(%s) into ID = %d, %v, want %d", in, plain, err, id)
+		}
+	}
+	var got NumberID
+	if err := got.UnmarshalJSON([]byte(`1.5`)); err == nil {
+		t.Error("UnmarshalJSON() should reject non-integer numbers")
+	}
+}
@@ -0,0 +1,109 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// maxNodeStepBits is the total number of bits Node and Step may share.
+const maxNodeStepBits = 22
+
+// ErrInvalidLayout is returned by NewNodeWithLayout when the layout is invalid.
+var ErrInvalidLayout = errors.New("invalid layout")
+
+// ErrInvalidNode is returned by NewNodeWithLayout when the node number does
+// not fit in the layout's NodeBits.
+var ErrInvalidNode = errors.New("invalid node number")
+
+// A Layout describes how a snowflake ID is split into time, node and step
+// fields. Each Node keeps its own Layout, so generators with different layouts
+// can coexist in one process; decode their IDs with the same Layout.
+//
+// The zero value is not usable; start from DefaultLayout.
+type Layout struct {
+	// Epoch is the custom epoch in unix milliseconds.
+	Epoch int64
+	// NodeBits is the number of bits used for the node number.
+	NodeBits uint8
+	// StepBits is the number of bits used for the step (sequence) number.
+	// NodeBits and StepBits share a total of 22 bits.
+	StepBits uint8
+	// UseSignBit lets the time field use the most significant bit, doubling
+	// the usable lifetime. IDs become negative as int64 once the time field
+	// reaches the sign bit; leave it false to keep IDs positive.
+	UseSignBit bool
+}
+
+// Parts holds the fields of a decoded snowflake ID.
+type Parts struct {
+	Time int64 // unix timestamp in milliseconds
+	Node int64
+	Step int64
+}
+
+// DefaultLayout returns the layout described by the package-level Epoch,
+// NodeBits and StepBits variables at the time of the call.
+func DefaultLayout() Layout {
+	return Layout{
+		Epoch:    Epoch,
+		NodeBits: NodeBits,
+		StepBits: StepBits,
+	}
+}
+
+// Validate reports whether the layout can be used to generate IDs.
+func (l Layout) Validate() error {
+	if l.NodeBits+l.StepBits > maxNodeStepBits || l.NodeBits > maxNodeStepBits || l.StepBits > maxNodeStepBits {
+		return fmt.Errorf("%w: NodeBits(%d)+StepBits(%d) exceeds %d bits", ErrInvalidLayout, l.NodeBits, l.StepBits, maxNodeStepBits)
+	}
+	if l.Epoch < 0 || l.Epoch > time.Now().UnixMilli() {
+		return fmt.Errorf("%w: epoch %d must be between 0 and now", ErrInvalidLayout, l.Epoch)
+	}
+	return nil
+}
+
+// TimeBits returns the number of bits used for the timestamp.
+func (l Layout) TimeBits() uint8 {
+	bits := 63 - l.NodeBits - l.StepBits
+	if l.UseSignBit {
+		bits++
+	}
+	return bits
+}
+
+// MaxNode returns the largest node number that fits in the layout.
+func (l Layout) MaxNode() int64 {
+	return -1 ^ (-1 << l.NodeBits)
+}
+
+// MaxStep returns the largest step number that fits in the layout.
+func (l Layout) MaxStep() int64 {
+	return -1 ^ (-1 << l.StepBits)
+}
+
+// Decode splits a snowflake ID generated with this layout into its fields.
+func (l Layout) Decode(id ID) Parts {
+	u := uint64(id) //nolint:gosec // G115: reinterpret bits, the sign bit may belong to the time field
+	return Parts{
+		Time: int64(u>>(l.NodeBits+l.StepBits)) + l.Epoch, //nolint:gosec // G115: at most 64-NodeBits-StepBits bits
+		Node: int64(u>>l.StepBits) & l.MaxNode(),          //nolint:gosec // G115: masked to NodeBits
+		Step: int64(u) & l.MaxStep(),                      //nolint:gosec // G115: masked to StepBits
+	}
+}
+
+// Time returns the unix timestamp in milliseconds of an ID generated with
+// this layout.
+func (l Layout) Time(id ID) int64 {
+	return l.Decode(id).Time
+}
+
+// Node returns the node number of an ID generated with this layout.
+func (l Layout) Node(id ID) int64 {
+	return l.Decode(id).Node
+}
+
+// Step returns the step number of an ID generated with this layout.
+func (l Layout) Step(id ID) int64 {
+	return l.Decode(id).Step
+}
@@ -0,0 +1,138 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDefaultLayout(t *testing.T) {
+	l := DefaultLayout()
+	if l.Epoch != Epoch || l.NodeBits != NodeBits || l.StepBits != StepBits || l.UseSignBit {
+		t.Errorf("DefaultLayout() = %+v, want package-level values", l)
+	}
+	if got := l.TimeBits(); got != 41 {
+		t.Errorf("TimeBits() = %d, want 41", got)
+	}
+
+	node, err := NewNode(1)
+	if err != nil {
+		t.Fatalf("NewNode() error = %v", err)
+	}
+	if node.Layout() != l {
+		t.Errorf("NewNode().Layout() = %+v, want %+v", node.Layout(), l)
+	}
+}
+
+func TestLayout_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		layout  Layout
+		wantErr bool
+	}{
+		{"default", DefaultLayout(), false},
+		{"sonyflake-like", Layout{Epoch: Epoch, NodeBits: 16, StepBits: 6}, false},
+		{"too many bits", Layout{Epoch: Epoch, NodeBits: 12, StepBits: 12}, true},
+		{"uint8 overflow", Layout{Epoch: Epoch, NodeBits: 200, StepBits: 100}, true},
+		{"negative epoch", Layout{Epoch: -1, NodeBits: 10, StepBits: 12}, true},
+		{"future epoch", Layout{Epoch: time.Now().Add(time.Hour).UnixMilli(), NodeBits: 10, StepBits: 12}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.layout.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidLayout) {
+				t.Errorf("Validate() error = %v, want %v", err, ErrInvalidLayout)
+			}
+		})
+	}
+}
+
+func TestNewNodeWithLayout(t *testing.T) {
+	layout := Layout{Epoch: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC).UnixMilli(), NodeBits: 5, StepBits: 8}
+
+	if _, err := NewNodeWithLayout(32, layout); !errors.Is(err, ErrInvalidNode) {
+		t.Errorf("NewNodeWithLayout(32) error = %v, want %v", err, ErrInvalidNode)
+	}
+	if _, err := NewNodeWithLayout(1, Layout{NodeBits: 20, StepBits: 20}); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("NewNodeWithLayout() error = %v, want %v", err, ErrInvalidLayout)
+	}
+
+	node, err := NewNodeWithLayout(31, layout)
+	if err != nil {
+		t.Fatalf("NewNodeWithLayout() error = %v", err)
+	}
+
+	before := time.Now().UnixMilli()
+	var last ID
+	for i := range 1000 {
+		id := node.Generate()
+		if id <= last {
+			t.Fatalf("IDs must increase: %d after %d", id, last)
+		}
+		last = id
+
+		p := layout.Decode(id)
+		if p.Node != 31 {
+			t.Fatalf("Decode(%d).Node = %d, want 31 (iteration %d)", id, p.Node, i)
+		}
+		if p.Step > layout.MaxStep() {
+			t.Fatalf("Decode(%d).Step = %d exceeds %d", id, p.Step, layout.MaxStep())
+		}
+		if now := time.Now().UnixMilli(); p.Time < before || p.Time > now {
+			t.Fatalf("Decode(%d).Time = %d, want between %d and %d", id, p.Time, before, now)
+		}
+	}
+}
+
+func TestLayout_CoexistingLayouts(t *testing.T) {
+	layout := Layout{Epoch: Epoch, NodeBits: 4, StepBits: 4}
+	custom, err := NewNodeWithLayout(9, layout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	std, err := NewNode(9)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 两种布局的 ID 分别按各自的 Layout 解码
+	customID, stdID := custom.Generate(), std.Generate()
+	if got := layout.Node(customID); got != 9 {
+		t.Errorf("layout.Node() = %d, want 9", got)
+	}
+	if got := std.Layout().Node(stdID); got != 9 || got != stdID.Node() {
+		t.Errorf("std.Layout().Node() = %d, want 9", got)
+	}
+	if got, want := layout.Time(customID), std.Layout().Time(stdID); got-want > 1 || want-got > 1 {
+		t.Errorf("decoded times differ: %d vs %d", got, want)
+	}
+	if got := layout.Step(customID); got != 0 {
+		t.Errorf("layout.Step() = %d, want 0", got)
+	}
+
+	// 全局变量描述的是默认布局，无法正确解码自定义布局的 ID
+	if customID.Node() == 9 && customID.Time() == layout.Time(customID) {
+		t.Error("ID methods should decode with the package-level layout")
+	}
+}
+
+func TestLayout_UseSignBit(t *testing.T) {
+	layout := Layout{Epoch: 0, NodeBits: 10, StepBits: 12, UseSignBit: true}
+	if got := layout.TimeBits(); got != 42 {
+		t.Errorf("TimeBits() = %d, want 42", got)
+	}
+
+	// 时间字段占用符号位时 ID 为负数，仍可正确解码
+	ms := int64(1) << 41
+	id := ID(uint64(ms)<<22 | 5<<12 | 7) //nolint:gosec // G115: test value sets the sign bit on purpose
+	if id >= 0 {
+		t.Fatalf("ID = %d, want negative", id)
+	}
+	want := Parts{Time: ms, Node: 5, Step: 7}
+	if got := layout.Decode(id); got != want {
+		t.Errorf("Decode() = %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,139 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrClockRollback is returned by GenerateE when the clock moved backwards
+// and the node's RollbackPolicy does not allow generating an ID.
+var ErrClockRollback = errors.New("clock moved backwards")
+
+// DefaultRollbackTolerance is the maximum rollback RollbackWait waits out
+// when no tolerance is given.
+const DefaultRollbackTolerance = 10 * time.Millisecond
+
+// A RollbackPolicy decides what a Node does when the clock reads earlier
+// than the timestamp of the last generated ID.
+type RollbackPolicy int
+
+const (
+	// RollbackLogical keeps generating from the last timestamp, as if the
+	// clock had stopped, and moves the timestamp forward by one millisecond
+	// whenever the step overflows. IDs stay unique and increasing; their
+	// timestamps run ahead of the clock until it catches up.
+	RollbackLogical RollbackPolicy = iota
+	// RollbackWait sleeps until the clock catches up when the rollback is
+	// within the tolerance, and fails otherwise.
+	RollbackWait
+	// RollbackError fails on any rollback.
+	RollbackError
+)
+
+// String returns the name of the policy.
+func (p RollbackPolicy) String() string {
+	switch p {
+	case RollbackLogical:
+		return "logical"
+	case RollbackWait:
+		return "wait"
+	case RollbackError:
+		return "error"
+	default:
+		return fmt.Sprintf("RollbackPolicy(%d)", int(p))
+	}
+}
+
+// An Option configures a Node.
+type Option func(*Node)
+
+// WithRollbackPolicy sets how the node handles the clock moving backwards.
+// tolerance is only used by RollbackWait; zero means DefaultRollbackTolerance.
+//
+// Generate never fails: where the policy would fail, it falls back to
+// RollbackLogical. Use GenerateE to receive ErrClockRollback instead.
+func WithRollbackPolicy(policy RollbackPolicy, tolerance time.Duration) Option {
+	return func(n *Node) {
+		n.policy = policy
+		n.tolerance = tolerance
+		if n.tolerance <= 0 {
+			n.tolerance = DefaultRollbackTolerance
+		}
+	}
+}
+
+// GenerateE creates and returns a unique snowflake ID like Generate, but
+// returns ErrClockRollback when the clock moved backwards and the node's
+// RollbackPolicy does not allow generating an ID.
+func (n *Node) GenerateE() (ID, error) {
+	now, step, _, err := n.reserve(1, true)
+	if err != nil {
+		return 0, err
+	}
+	return n.compose(now, step), nil
+}
+
+// Rollbacks returns how many times the node observed the clock moving
+// backwards. A rollback is counted once until the clock catches up again.
+func (n *Node) Rollbacks() uint64 {
+	return n.rollbacks.Load()
+}
+
+// checkClock compares a clock reading with the timestamp of the last ID,
+// counts rollbacks and applies the policy. It returns the timestamp to
+// continue from.
+func (n *Node) checkClock(now, last int64, strict bool) (int64, error) {
+	if now >= last {
+		if n.behind.Load() {
+			n.behind.Store(false)
+		}
+		return now, nil
+	}
+	if n.behind.CompareAndSwap(false, true) {
+		n.rollbacks.Add(1)
+	}
+	return n.rollback(now, last, strict)
+}
+
+// rollback applies the policy to a clock reading earlier than last. When
+// strict is false, policies that would fail fall back to RollbackLogical.
+func (n *Node) rollback(now, last int64, strict bool) (int64, error) {
+	behind := time.Duration(last-now) * time.Millisecond
+
+	switch n.policy {
+	case RollbackWait:
+		if behind <= n.tolerance {
+			for now < last {
+				time.Sleep(time.Duration(last-now) * time.Millisecond)
+				now = n.clock()
+			}
+			return now, nil
+		}
+	case RollbackError:
+	default:
+		return last, nil
+	}
+
+	if strict {
+		return 0, fmt.Errorf("%w: by %s (policy %s)", ErrClockRollback, behind, n.policy)
+	}
+	return last, nil
+}
+
+// nextTime returns the timestamp to use after the step overflowed at last.
+// It waits for the next millisecond, or borrows it when the clock is behind.
+func (n *Node) nextTime(last int64) int64 {
+	now := n.clock()
+	if now == last {
+		start := n.stats.waitStart()
+		for now == last {
+			now = n.clock()
+		}
+		n.stats.waitDone(start)
+	}
+	if now < last {
+		return last + 1
+	}
+	return now
+}
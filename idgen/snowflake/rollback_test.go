@@ -0,0 +1,134 @@
+package snowflake
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newFakeClockNode 创建使用可控时钟的 Node
+func newFakeClockNode(t *testing.T, opts ...Option) (*Node, *atomic.Int64) {
+	t.Helper()
+	node, err := NewNodeWithLayout(1, Layout{Epoch: Epoch, NodeBits: 10, StepBits: 2}, opts...)
+	if err != nil {
+		t.Fatalf("NewNodeWithLayout() error = %v", err)
+	}
+	var now atomic.Int64
+	now.Store(1000)
+	node.clock = now.Load
+	return node, &now
+}
+
+func TestGenerate_RollbackLogical(t *testing.T) {
+	node, now := newFakeClockNode(t)
+	layout := node.Layout()
+
+	last := node.Generate()
+	now.Store(900) // 时钟回拨 100ms
+
+	seen := map[ID]bool{last: true}
+	for range 10 {
+		id := node.Generate()
+		if id <= last || seen[id] {
+			t.Fatalf("ID %d is not unique and increasing after %d", id, last)
+		}
+		seen[id] = true
+		last = id
+	}
+
+	// StepBits=2：每毫秒 4 个 ID，溢出后借用后续的毫秒
+	if got := layout.Decode(last).Time - layout.Epoch; got != 1002 {
+		t.Errorf("logical time = %d, want 1002", got)
+	}
+	if got := node.Rollbacks(); got != 1 {
+		t.Errorf("Rollbacks() = %d, want 1", got)
+	}
+
+	// 时钟追上后恢复正常，再次回拨计为新的事件
+	now.Store(2000)
+	node.Generate()
+	now.Store(1500)
+	node.Generate()
+	if got := node.Rollbacks(); got != 2 {
+		t.Errorf("Rollbacks() = %d, want 2", got)
+	}
+}
+
+func TestGenerateE_RollbackError(t *testing.T) {
+	node, now := newFakeClockNode(t, WithRollbackPolicy(RollbackError, 0))
+
+	first, err := node.GenerateE()
+	if err != nil {
+		t.Fatalf("GenerateE() error = %v", err)
+	}
+
+	now.Store(999)
+	if _, err := node.GenerateE(); !errors.Is(err, ErrClockRollback) {
+		t.Errorf("GenerateE() error = %v, want %v", err, ErrClockRollback)
+	}
+
+	// Generate 不返回错误，退化为逻辑时钟
+	if id := node.Generate(); id <= first {
+		t.Errorf("Generate() = %d, want greater than %d", id, first)
+	}
+	if got := node.Rollbacks(); got != 1 {
+		t.Errorf("Rollbacks() = %d, want 1", got)
+	}
+}
+
+func TestGenerateE_RollbackWait(t *testing.T) {
+	node, now := newFakeClockNode(t, WithRollbackPolicy(RollbackWait, 50*time.Millisecond))
+
+	last := node.Generate()
+
+	// 超过容忍值：返回错误
+	now.Store(900)
+	if _, err := node.GenerateE(); !errors.Is(err, ErrClockRollback) {
+		t.Errorf("GenerateE() error = %v, want %v", err, ErrClockRollback)
+	}
+
+	// 容忍范围内：等待时钟追上
+	now.Store(990)
+	go func() {
+		time.Sleep(5 * time.Millisecond)
+		now.Store(1001)
+	}()
+	id, err := node.GenerateE()
+	if err != nil {
+		t.Fatalf("GenerateE() error = %v", err)
+	}
+	if id <= last {
+		t.Errorf("GenerateE() = %d, want greater than %d", id, last)
+	}
+	if got := node.Layout().Decode(id).Time - node.Layout().Epoch; got != 1001 {
+		t.Errorf("time after wait = %d, want 1001", got)
+	}
+}
+
+func TestWithRollbackPolicy_DefaultTolerance(t *testing.T) {
+	node, err := NewNode(1, WithRollbackPolicy(RollbackWait, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if node.tolerance != DefaultRollbackTolerance || node.policy != RollbackWait {
+		t.Errorf("policy = %s/%s, want wait/%s", node.policy, node.tolerance, DefaultRollbackTolerance)
+	}
+	if got := RollbackPolicy(9).String(); got != "RollbackPolicy(9)" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestNewNode_WallClock(t *testing.T) {
+	node, err := NewNode(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 默认时钟读取系统时间而非单调时钟，系统时间回拨时才能触发 RollbackPolicy
+	before := time.Now().UnixMilli() - Epoch
+	got := node.clock()
+	after := time.Now().UnixMilli() - Epoch
+	if got < before || got > after {
+		t.Errorf("clock() = %d, want between %d and %d", got, before, after)
+	}
+}
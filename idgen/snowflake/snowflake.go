@@ -6,11 +6,16 @@ import (
 	"encoding/binary"
 	"errors"
 	"fmt"
+	"math"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// The package-level layout variables are read by NewNode (via DefaultLayout)
+// and by ID.Time, ID.Node and ID.Step. Prefer NewNodeWithLayout and
+// Layout.Decode when more than one layout is used in a process.
 var (
 	// Epoch is set to the twitter snowflake epoch of Nov 04 2010 01:42:54 UTC in milliseconds
 	// You may customize this to set a different epoch for your application.
@@ -69,17 +74,28 @@ func init() {
 // A Node struct holds the basic information needed for a snowflake generator
 // node
 type Node struct {
-	mu    sync.Mutex
-	epoch time.Time
-	time  int64
-	node  int64
-	step  int64
+	mu     sync.Mutex
+	time   int64
+	node   int64
+	step   int64
+	layout Layout
 
 	nodeMax   int64
 	nodeMask  int64
 	stepMask  int64
 	timeShift uint8
 	nodeShift uint8
+
+	clock     func() int64 // milliseconds since epoch
+	policy    RollbackPolicy
+	tolerance time.Duration
+	behind    atomic.Bool // the clock is behind the last ID's timestamp
+	rollbacks atomic.Uint64
+
+	lockFree bool
+	state    atomic.Uint64 // time<<StepBits | step of the last ID, used when lockFree
+
+	stats *nodeStats // nil unless WithStats
 }
 
 // An ID is a custom type used for a snowflake ID.  This is used so we can
@@ -87,60 +103,59 @@ type Node struct {
 type ID int64
 
 // NewNode returns a new snowflake node that can be used to generate snowflake
-// IDs
-func NewNode(node int64) (*Node, error) {
-	if NodeBits+StepBits > 22 {
-		return nil, errors.New("remember, you have a total 22 bits to share between Node/Step")
+// IDs, using the layout described by the package-level Epoch, NodeBits and
+// StepBits variables.
+func NewNode(node int64, opts ...Option) (*Node, error) {
+	return NewNodeWithLayout(node, DefaultLayout(), opts...)
+}
+
+// NewNodeWithLayout returns a new snowflake node that generates IDs with the
+// given layout. The layout is copied, so later changes to the package-level
+// variables do not affect the node.
+func NewNodeWithLayout(node int64, layout Layout, opts ...Option) (*Node, error) {
+	if err := layout.Validate(); err != nil {
+		return nil, err
 	}
-	n := Node{}
+	n := Node{layout: layout}
 	n.node = node
-	n.nodeMax = -1 ^ (-1 << NodeBits)
-	n.nodeMask = n.nodeMax << StepBits
-	n.stepMask = -1 ^ (-1 << StepBits)
-	n.timeShift = NodeBits + StepBits
-	n.nodeShift = StepBits
+	n.nodeMax = layout.MaxNode()
+	n.nodeMask = n.nodeMax << layout.StepBits
+	n.stepMask = layout.MaxStep()
+	n.timeShift = layout.NodeBits + layout.StepBits
+	n.nodeShift = layout.StepBits
 
 	if n.node < 0 || n.node > n.nodeMax {
-		return nil, errors.New("Node number must be between 0 and " + strconv.FormatInt(n.nodeMax, 10))
+		return nil, fmt.Errorf("%w: node number must be between 0 and %d", ErrInvalidNode, n.nodeMax)
 	}
 
-	curTime := time.Now()
-	// add time.Duration to curTime to make sure we use the monotonic clock if available
-	n.epoch = curTime.Add(time.Unix(Epoch/1000, (Epoch%1000)*1000000).Sub(curTime))
+	// read the wall clock rather than the monotonic one, so that the
+	// RollbackPolicy sees NTP steps and manual changes of the system time
+	n.clock = func() int64 { return time.Now().UnixMilli() - layout.Epoch }
+	n.tolerance = DefaultRollbackTolerance
+
+	for _, opt := range opts {
+		opt(&n)
+	}
 
 	return &n, nil
 }
 
+// Layout returns the layout used by the node. Use it to decode the IDs the
+// node generates.
+func (n *Node) Layout() Layout {
+	return n.layout
+}
+
 // Generate creates and returns a unique snowflake ID
 // To help guarantee uniqueness
 // - Make sure your system is keeping accurate system time
 // - Make sure you never have multiple nodes running with the same node ID
+//
+// If the clock moves backwards, the node's RollbackPolicy applies; policies
+// that would fail fall back to RollbackLogical. Use GenerateE to fail instead.
 func (n *Node) Generate() ID {
-	n.mu.Lock()
-	defer n.mu.Unlock()
-
-	now := time.Since(n.epoch).Milliseconds()
-
-	if now == n.time {
-		n.step = (n.step + 1) & n.stepMask
-
-		if n.step == 0 {
-			for now <= n.time {
-				now = time.Since(n.epoch).Milliseconds()
-			}
-		}
-	} else {
-		n.step = 0
-	}
-
-	n.time = now
-
-	r := ID((now)<<n.timeShift |
-		(n.node << n.nodeShift) |
-		(n.step),
-	)
-
-	return r
+	now, step, _, _ := n.reserve(1, false)
+	return n.compose(now, step)
 }
 
 // Int64 returns an int64 of the snowflake ID
@@ -177,41 +192,20 @@ func ParseBase2(id string) (ID, error) {
 
 // Base32 uses the z-base-32 character set but encodes and decodes similar
 // to base58, allowing it to create an even smaller result string.
+// Negative IDs, produced by layouts that use the sign bit, are prefixed with
+// '-' like Base2 and Base36.
 // NOTE: There are many different base32 implementations so becareful when
 // doing any interoperation.
 func (f ID) Base32() string {
-	if f < 32 {
-		return string(encodeBase32Map[f])
-	}
-
-	b := make([]byte, 0, 12)
-	for f >= 32 {
-		b = append(b, encodeBase32Map[f%32])
-		f /= 32
-	}
-	b = append(b, encodeBase32Map[f])
-
-	for x, y := 0, len(b)-1; x < y; x, y = x+1, y-1 {
-		b[x], b[y] = b[y], b[x]
-	}
-
-	return string(b)
+	return encodeSigned(f, encodeBase32Map)
 }
 
-// ParseBase32 parses a base32 []byte into a snowflake ID
+// ParseBase32 parses a base32 []byte into a snowflake ID, accepting a leading
+// '-' for negative IDs.
 // NOTE: There are many different base32 implementations so becareful when
 // doing any interoperation.
 func ParseBase32(b []byte) (ID, error) {
-	var id int64
-
-	for i := range b {
-		if decodeBase32Map[b[i]] == 0xFF {
-			return -1, ErrInvalidBase32
-		}
-		id = id*32 + int64(decodeBase32Map[b[i]])
-	}
-
-	return ID(id), nil
+	return decodeSigned(b, &decodeBase32Map, 32, ErrInvalidBase32)
 }
 
 // Base36 returns a base36 string of the snowflake ID
@@ -225,41 +219,81 @@ func ParseBase36(id string) (ID, error) {
 	return ID(i), err
 }
 
-// Base58 returns a base58 string of the snowflake ID
+// Base58 returns a base58 string of the snowflake ID. Negative IDs are
+// prefixed with '-' like Base2 and Base36.
 func (f ID) Base58() string {
-	if f < 58 {
-		return string(encodeBase58Map[f])
-	}
+	return encodeSigned(f, encodeBase58Map)
+}
 
-	b := make([]byte, 0, 11)
-	for f >= 58 {
-		b = append(b, encodeBase58Map[f%58])
-		f /= 58
-	}
-	b = append(b, encodeBase58Map[f])
+// ParseBase58 parses a base58 []byte into a snowflake ID, accepting a leading
+// '-' for negative IDs.
+func ParseBase58(b []byte) (ID, error) {
+	return decodeSigned(b, &decodeBase58Map, 58, ErrInvalidBase58)
+}
 
-	for x, y := 0, len(b)-1; x < y; x, y = x+1, y-1 {
-		b[x], b[y] = b[y], b[x]
+// encodeSigned encodes f in the alphabet, most significant digit first, with
+// a leading '-' when f is negative.
+func encodeSigned(f ID, alphabet string) string {
+	base := uint64(len(alphabet))
+	u := uint64(f) //nolint:gosec // G115: the magnitude is taken below
+	if f < 0 {
+		u = -u
 	}
 
-	return string(b)
+	var b [14]byte // 13 base32 digits of 2^63, plus the sign
+	i := len(b)
+	for {
+		i--
+		b[i] = alphabet[u%base]
+		u /= base
+		if u == 0 {
+			break
+		}
+	}
+	if f < 0 {
+		i--
+		b[i] = '-'
+	}
+	return string(b[i:])
 }
 
-// ParseBase58 parses a base58 []byte into a snowflake ID
-func ParseBase58(b []byte) (ID, error) {
-	var id int64
+// decodeSigned decodes b in the alphabet of decodeMap, accepting a leading
+// '-' for negative values, and reports errInvalid for invalid characters or
+// values outside the int64 range.
+func decodeSigned(b []byte, decodeMap *[256]byte, base uint64, errInvalid error) (ID, error) {
+	digits := b
+	limit := uint64(math.MaxInt64)
+	neg := len(b) > 0 && b[0] == '-'
+	if neg {
+		digits = b[1:]
+		limit++ // -MinInt64
+		if len(digits) == 0 {
+			return -1, errInvalid
+		}
+	}
 
-	for i := range b {
-		if decodeBase58Map[b[i]] == 0xFF {
-			return -1, ErrInvalidBase58
+	var u uint64
+	for _, c := range digits {
+		if decodeMap[c] == 0xFF {
+			return -1, errInvalid
+		}
+		d := uint64(decodeMap[c])
+		if u > (limit-d)/base {
+			return -1, fmt.Errorf("%w: %q overflows int64", errInvalid, b)
 		}
-		id = id*58 + int64(decodeBase58Map[b[i]])
+		u = u*base + d
 	}
 
-	return ID(id), nil
+	if neg {
+		u = -u
+	}
+	return ID(u), nil //nolint:gosec // G115: range checked above
 }
 
 // Base64 returns a base64 string of the snowflake ID
+//
+// Note that it encodes the decimal string returned by Bytes, not the 8-byte
+// integer; use Base64URL for a compact encoding of the integer.
 func (f ID) Base64() string {
 	return base64.StdEncoding.EncodeToString(f.Bytes())
 }
@@ -274,6 +308,9 @@ func ParseBase64(id string) (ID, error) {
 }
 
 // Bytes returns a byte slice of the snowflake ID
+//
+// Note that it holds the decimal string, not the 8-byte integer; use IntBytes
+// or MarshalBinary for the big endian integer.
 func (f ID) Bytes() []byte {
 	return []byte(f.String())
 }
@@ -301,7 +338,7 @@ func ParseIntBytes(id [8]byte) ID {
 // Time returns an int64 unix timestamp in milliseconds of the snowflake ID time
 //
 // 注意：解码使用当前 NodeBits/StepBits 即时计算，
-// 须与生成该 ID 时的位宽配置一致。
+// 须与生成该 ID 时的位宽配置一致；自定义布局请使用 Layout.Decode。
 func (f ID) Time() int64 {
 	return (int64(f) >> (NodeBits + StepBits)) + Epoch
 }
@@ -309,7 +346,7 @@ func (f ID) Time() int64 {
 // Node returns an int64 of the snowflake ID node number
 //
 // 注意：解码使用当前 NodeBits/StepBits 即时计算，
-// 须与生成该 ID 时的位宽配置一致。
+// 须与生成该 ID 时的位宽配置一致；自定义布局请使用 Layout.Decode。
 func (f ID) Node() int64 {
 	nodeMax := int64(-1 ^ (-1 << NodeBits))
 	return int64(f) & (nodeMax << StepBits) >> StepBits
@@ -318,12 +355,13 @@ func (f ID) Node() int64 {
 // Step returns an int64 of the snowflake step (or sequence) number
 //
 // 注意：解码使用当前 NodeBits/StepBits 即时计算，
-// 须与生成该 ID 时的位宽配置一致。
+// 须与生成该 ID 时的位宽配置一致；自定义布局请使用 Layout.Decode。
 func (f ID) Step() int64 {
 	return int64(f) & (-1 ^ (-1 << StepBits))
 }
 
-// MarshalJSON returns a json byte array string of the snowflake ID.
+// MarshalJSON returns a json byte array string of the snowflake ID. Use
+// NumberID to write a bare number instead.
 func (f ID) MarshalJSON() ([]byte, error) {
 	buff := make([]byte, 0, 22)
 	buff = append(buff, '"')
@@ -333,7 +371,18 @@ func (f ID) MarshalJSON() ([]byte, error) {
 }
 
 // UnmarshalJSON converts a json byte array of a snowflake ID into an ID type.
+// It accepts both quoted strings and bare numbers, so IDs written by ID and
+// NumberID read back either way.
 func (f *ID) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] != '"' {
+		i, err := strconv.ParseInt(string(b), 10, 64)
+		if err != nil {
+			return JSONSyntaxError{b}
+		}
+		*f = ID(i)
+		return nil
+	}
+
 	if len(b) < 3 || b[0] != '"' || b[len(b)-1] != '"' {
 		return JSONSyntaxError{b}
 	}
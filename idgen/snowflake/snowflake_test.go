@@ -2,6 +2,7 @@ package snowflake
 
 import (
 	"bytes"
+	"math"
 	"testing"
 )
 
@@ -223,6 +224,34 @@ func TestBase58(t *testing.T) {
 	}
 }
 
+func TestBase32Base58_Negative(t *testing.T) {
+	tests := []struct {
+		id  ID
+		b32 string
+		b58 string
+	}{
+		{-5, "-f", "-6"},
+		{-1, "-b", "-2"},
+		{0, "y", "1"},
+		{math.MinInt64, "", ""},
+	}
+
+	for _, tt := range tests {
+		b32, b58 := tt.id.Base32(), tt.id.Base58()
+		if tt.b32 != "" && (b32 != tt.b32 || b58 != tt.b58) {
+			t.Errorf("ID(%d) Base32() = %q, Base58() = %q, want %q, %q", tt.id, b32, b58, tt.b32, tt.b58)
+		}
+
+		// 编码结果可以解析回原 ID
+		if got, err := ParseBase32([]byte(b32)); err != nil || got != tt.id {
+			t.Errorf("ParseBase32(%q) = %d, %v, want %d", b32, got, err, tt.id)
+		}
+		if got, err := ParseBase58([]byte(b58)); err != nil || got != tt.id {
+			t.Errorf("ParseBase58(%q) = %d, %v, want %d", b58, got, err, tt.id)
+		}
+	}
+}
+
 func TestBase64(t *testing.T) {
 	node, err := NewNode(0)
 	if err != nil {
@@ -337,7 +366,8 @@ func TestUnmarshalJSON(t *testing.T) {
 		expectedErr error
 	}{
 		{`"13587"`, 13587, nil},
-		{`1`, 0, JSONSyntaxError{[]byte(`1`)}},
+		{`1`, 1, nil},
+		{`1.5`, 0, JSONSyntaxError{[]byte(`1.5`)}},
 		{`"invalid`, 0, JSONSyntaxError{[]byte(`"invalid`)}},
 	}
 
@@ -497,6 +527,42 @@ func TestParseBase32(t *testing.T) {
 			want:    -1,
 			wantErr: true,
 		},
+		{
+			name:    "max int64",
+			arg:     "8999999999999",
+			want:    math.MaxInt64,
+			wantErr: false,
+		},
+		{
+			name:    "overflow",
+			arg:     "e999999999999",
+			want:    -1,
+			wantErr: true,
+		},
+		{
+			name:    "negative",
+			arg:     "-b8wjm1zroyyyy",
+			want:    -1427970479175499776,
+			wantErr: false,
+		},
+		{
+			name:    "min int64",
+			arg:     "-eyyyyyyyyyyyy",
+			want:    math.MinInt64,
+			wantErr: false,
+		},
+		{
+			name:    "negative overflow",
+			arg:     "-eyyyyyyyyyyyb",
+			want:    -1,
+			wantErr: true,
+		},
+		{
+			name:    "sign only",
+			arg:     "-",
+			want:    -1,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -549,6 +615,36 @@ func TestParseBase58(t *testing.T) {
 			want:    -1,
 			wantErr: true,
 		},
+		{
+			name:    "max int64",
+			arg:     "npL6MjP8Qfc",
+			want:    math.MaxInt64,
+			wantErr: false,
+		},
+		{
+			name:    "overflow",
+			arg:     "npL6MjP8Qfd",
+			want:    -1,
+			wantErr: true,
+		},
+		{
+			name:    "long input overflows",
+			arg:     "4jgmnx8Js8A4jgmnx8Js8A",
+			want:    -1,
+			wantErr: true,
+		},
+		{
+			name:    "negative",
+			arg:     "-4jgmnx8Js8A",
+			want:    -1428076403798048768,
+			wantErr: false,
+		},
+		{
+			name:    "double sign",
+			arg:     "--4jgmnx8Js8A",
+			want:    -1,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
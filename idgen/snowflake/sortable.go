@@ -0,0 +1,161 @@
+package snowflake
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// ErrInvalidBase62 is returned by ParseSortableBase62 when given an invalid string
+var ErrInvalidBase62 = errors.New("invalid base62")
+
+// ErrInvalidBase64 is returned by ParseBase64URL when given an invalid string
+var ErrInvalidBase64 = errors.New("invalid base64")
+
+// Alphabets of the fixed-width encodings. Their characters are in ASCII order,
+// so zero-padded strings sort like the unsigned 64-bit values.
+const (
+	encodeCrockford32 = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	encodeSortable58  = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	encodeSortable62  = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// Widths of the fixed-width encodings: the digits needed for 2^64-1.
+const (
+	crockford32Width = 13
+	sortable58Width  = 11
+	sortable62Width  = 11
+)
+
+var (
+	decodeCrockford32 [256]byte
+	decodeSortable58  [256]byte
+	decodeSortable62  [256]byte
+)
+
+func init() {
+	for _, table := range []*[256]byte{&decodeCrockford32, &decodeSortable58, &decodeSortable62} {
+		for i := range table {
+			table[i] = 0xFF
+		}
+	}
+	for i := range len(encodeCrockford32) {
+		decodeCrockford32[encodeCrockford32[i]] = byte(i)
+		decodeCrockford32[encodeCrockford32[i]|0x20] = byte(i) // lower case
+	}
+	// Crockford decodes the easily confused I, L and O as 1, 1 and 0.
+	for _, c := range []byte("IiLl") {
+		decodeCrockford32[c] = 1
+	}
+	decodeCrockford32['O'], decodeCrockford32['o'] = 0, 0
+
+	for i := range len(encodeSortable58) {
+		decodeSortable58[encodeSortable58[i]] = byte(i)
+	}
+	for i := range len(encodeSortable62) {
+		decodeSortable62[encodeSortable62[i]] = byte(i)
+	}
+}
+
+// Base64URL returns the unpadded base64url encoding of the 8-byte big endian
+// integer, always 11 characters. Unlike Base64, it encodes the integer rather
+// than the decimal string. It does not sort in ID order.
+func (f ID) Base64URL() string {
+	b := f.IntBytes()
+	return base64.RawURLEncoding.EncodeToString(b[:])
+}
+
+// ParseBase64URL converts a Base64URL string into a snowflake ID
+func ParseBase64URL(id string) (ID, error) {
+	b, err := base64.RawURLEncoding.DecodeString(id)
+	if err != nil || len(b) != 8 {
+		return -1, fmt.Errorf("%w: %q", ErrInvalidBase64, id)
+	}
+	return ID(int64(binary.BigEndian.Uint64(b))), nil //nolint:gosec // G115: snowflake IDs fit in int64
+}
+
+// Crockford32 returns the 64-bit integer in Crockford's base32, zero-padded
+// to 13 upper case characters. Strings sort in ID order.
+func (f ID) Crockford32() string {
+	return encodeFixed(uint64(f), encodeCrockford32, crockford32Width) //nolint:gosec // G115: encoded as unsigned
+}
+
+// ParseCrockford32 converts a Crockford32 string into a snowflake ID. It is
+// case insensitive, decodes I and L as 1 and O as 0, and rejects strings that
+// are not 13 characters or overflow 64 bits.
+func ParseCrockford32(id string) (ID, error) {
+	v, err := decodeFixed(id, &decodeCrockford32, 32, crockford32Width)
+	if err != nil {
+		return -1, fmt.Errorf("%w: %w", ErrInvalidBase32, err)
+	}
+	return ID(v), nil //nolint:gosec // G115: decoded as unsigned
+}
+
+// SortableBase58 returns the 64-bit integer in base58 using the Bitcoin
+// alphabet, zero-padded to 11 characters. Unlike Base58, strings sort in ID
+// order.
+func (f ID) SortableBase58() string {
+	return encodeFixed(uint64(f), encodeSortable58, sortable58Width) //nolint:gosec // G115: encoded as unsigned
+}
+
+// ParseSortableBase58 converts a SortableBase58 string into a snowflake ID.
+// It rejects strings that are not 11 characters or overflow 64 bits.
+func ParseSortableBase58(id string) (ID, error) {
+	v, err := decodeFixed(id, &decodeSortable58, 58, sortable58Width)
+	if err != nil {
+		return -1, fmt.Errorf("%w: %w", ErrInvalidBase58, err)
+	}
+	return ID(v), nil //nolint:gosec // G115: decoded as unsigned
+}
+
+// SortableBase62 returns the 64-bit integer in base62 (0-9, A-Z, a-z),
+// zero-padded to 11 characters. Strings sort in ID order.
+func (f ID) SortableBase62() string {
+	return encodeFixed(uint64(f), encodeSortable62, sortable62Width) //nolint:gosec // G115: encoded as unsigned
+}
+
+// ParseSortableBase62 converts a SortableBase62 string into a snowflake ID.
+// It rejects strings that are not 11 characters or overflow 64 bits.
+func ParseSortableBase62(id string) (ID, error) {
+	v, err := decodeFixed(id, &decodeSortable62, 62, sortable62Width)
+	if err != nil {
+		return -1, fmt.Errorf("%w: %w", ErrInvalidBase62, err)
+	}
+	return ID(v), nil //nolint:gosec // G115: decoded as unsigned
+}
+
+// encodeFixed encodes v in the alphabet, zero-padded to width characters.
+func encodeFixed(v uint64, alphabet string, width int) string {
+	base := uint64(len(alphabet))
+	b := make([]byte, width)
+	for i := width - 1; i >= 0; i-- {
+		b[i] = alphabet[v%base]
+		v /= base
+	}
+	return string(b)
+}
+
+// decodeFixed decodes a width-character string, rejecting invalid characters
+// and values that overflow 64 bits.
+func decodeFixed(s string, decode *[256]byte, base uint64, width int) (uint64, error) {
+	if len(s) != width {
+		return 0, fmt.Errorf("%q has length %d, want %d", s, len(s), width)
+	}
+
+	var v uint64
+	for i := range len(s) {
+		d := decode[s[i]]
+		if d == 0xFF {
+			return 0, fmt.Errorf("%q has invalid character %q", s, s[i])
+		}
+		hi, lo := bits.Mul64(v, base)
+		sum, carry := bits.Add64(lo, uint64(d), 0)
+		if hi != 0 || carry != 0 {
+			return 0, fmt.Errorf("%q overflows 64 bits", s)
+		}
+		v = sum
+	}
+	return v, nil
+}
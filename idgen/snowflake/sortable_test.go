@@ -0,0 +1,99 @@
+package snowflake
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestBase64URL(t *testing.T) {
+	id := ID(13587)
+	s := id.Base64URL()
+	if s != "AAAAAAAANRM" {
+		t.Errorf("Base64URL() = %s, want AAAAAAAANRM", s)
+	}
+	if got, err := ParseBase64URL(s); err != nil || got != id {
+		t.Errorf("ParseBase64URL() = %d, %v, want %d", got, err, id)
+	}
+
+	for _, in := range []string{"", "AAAAAAAANR", "AAAAAAAANRMA", "AAAAAAAANR+", "AAAAAAAANRM="} {
+		if _, err := ParseBase64URL(in); !errors.Is(err, ErrInvalidBase64) {
+			t.Errorf("ParseBase64URL(%q) error = %v, want %v", in, err, ErrInvalidBase64)
+		}
+	}
+}
+
+func TestSortableEncodings(t *testing.T) {
+	encodings := []struct {
+		name   string
+		encode func(ID) string
+		parse  func(string) (ID, error)
+		err    error
+		width  int
+		max    string // 2^64-1
+	}{
+		{name: "crockford32", encode: ID.Crockford32, parse: ParseCrockford32, err: ErrInvalidBase32, width: 13, max: "FZZZZZZZZZZZZ"},
+		{name: "base58", encode: ID.SortableBase58, parse: ParseSortableBase58, err: ErrInvalidBase58, width: 11, max: "jpXCZedGfVQ"},
+		{name: "base62", encode: ID.SortableBase62, parse: ParseSortableBase62, err: ErrInvalidBase62, width: 11, max: "LygHa16AHYF"},
+	}
+
+	ids := []ID{0, 1, 57, 58, 61, 62, 1 << 32, 1116823421972381696, math.MaxInt64, -1, math.MinInt64}
+	for range 100 {
+		ids = append(ids, ID(rand.Int64()))
+	}
+
+	for _, enc := range encodings {
+		t.Run(enc.name, func(t *testing.T) {
+			strs := make([]string, len(ids))
+			for i, id := range ids {
+				s := enc.encode(id)
+				if len(s) != enc.width {
+					t.Fatalf("encode(%d) = %s, want width %d", id, s, enc.width)
+				}
+				got, err := enc.parse(s)
+				if err != nil || got != id {
+					t.Fatalf("parse(%s) = %d, %v, want %d", s, got, err, id)
+				}
+				strs[i] = s
+			}
+
+			// 字符串顺序与无符号 64 位整数顺序一致
+			sorted := slices.Clone(ids)
+			sort.Slice(sorted, func(i, j int) bool { return uint64(sorted[i]) < uint64(sorted[j]) })
+			slices.Sort(strs)
+			for i, id := range sorted {
+				if strs[i] != enc.encode(id) {
+					t.Fatalf("sorted strings do not match ID order at %d: %s vs %s", i, strs[i], enc.encode(id))
+				}
+			}
+
+			if got := enc.encode(-1); got != enc.max {
+				t.Errorf("encode(2^64-1) = %s, want %s", got, enc.max)
+			}
+
+			// 溢出、长度不符与非法字符
+			overflow := string(enc.max[0]+1) + enc.max[1:]
+			for _, in := range []string{overflow, enc.max[1:], enc.max + "0", "", strings.Repeat("!", enc.width)} {
+				if got, err := enc.parse(in); !errors.Is(err, enc.err) || got != -1 {
+					t.Errorf("parse(%q) = %d, %v, want -1, %v", in, got, err, enc.err)
+				}
+			}
+		})
+	}
+}
+
+func TestParseCrockford32_Aliases(t *testing.T) {
+	want, _ := ParseCrockford32("0000000000011")
+	for _, in := range []string{"0000000000011", "O0000000000iL", "o00000000001l"} {
+		if got, err := ParseCrockford32(in); err != nil || got != want {
+			t.Errorf("ParseCrockford32(%q) = %d, %v, want %d", in, got, err, want)
+		}
+	}
+	if _, err := ParseCrockford32("000000000001U"); !errors.Is(err, ErrInvalidBase32) {
+		t.Errorf("ParseCrockford32() error = %v, want %v", err, ErrInvalidBase32)
+	}
+}
@@ -0,0 +1,77 @@
+package snowflake
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// WithStats makes the node count the IDs it generates and the times it waits
+// for the next millisecond because the step space was exhausted, as reported
+// by Stats. The counters cost an atomic add per reservation.
+func WithStats() Option {
+	return func(n *Node) {
+		n.stats = new(nodeStats)
+	}
+}
+
+// Stats is a snapshot of a node's counters.
+type Stats struct {
+	// Generated is the number of IDs generated. Zero without WithStats.
+	Generated uint64
+	// StepWaits is the number of times the step space of a millisecond was
+	// exhausted and the node spun until the next one. Zero without WithStats.
+	StepWaits uint64
+	// WaitTime is the total time spent in step waits. Zero without WithStats.
+	WaitTime time.Duration
+	// Rollbacks is the number of clock rollbacks, as returned by Rollbacks.
+	Rollbacks uint64
+	// ClockBehind reports whether the clock read earlier than the last ID's
+	// timestamp on the last generation.
+	ClockBehind bool
+}
+
+// Stats returns a snapshot of the node's counters. Rollbacks and ClockBehind
+// are always tracked; the other counters require WithStats.
+func (n *Node) Stats() Stats {
+	s := Stats{
+		Rollbacks:   n.rollbacks.Load(),
+		ClockBehind: n.behind.Load(),
+	}
+	if n.stats != nil {
+		s.Generated = n.stats.generated.Load()
+		s.StepWaits = n.stats.stepWaits.Load()
+		s.WaitTime = time.Duration(n.stats.waitNanos.Load()) //nolint:gosec // G115: total nanoseconds fit in int64
+	}
+	return s
+}
+
+// nodeStats holds the counters enabled by WithStats. Its methods do nothing
+// on a nil receiver, so callers need not check whether stats are enabled.
+type nodeStats struct {
+	generated atomic.Uint64
+	stepWaits atomic.Uint64
+	waitNanos atomic.Uint64
+}
+
+// addGenerated counts count generated IDs.
+func (s *nodeStats) addGenerated(count int64) {
+	if s != nil {
+		s.generated.Add(uint64(count)) //nolint:gosec // G115: count is positive
+	}
+}
+
+// waitStart returns the start time of a step wait.
+func (s *nodeStats) waitStart() time.Time {
+	if s == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// waitDone counts a step wait that started at start.
+func (s *nodeStats) waitDone(start time.Time) {
+	if s != nil {
+		s.stepWaits.Add(1)
+		s.waitNanos.Add(uint64(time.Since(start))) //nolint:gosec // G115: elapsed time is positive
+	}
+}
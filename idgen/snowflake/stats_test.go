@@ -0,0 +1,84 @@
+package snowflake
+
+import (
+	"testing"
+)
+
+func TestStats_StepWaits(t *testing.T) {
+	node, _ := newFakeClockNode(t, WithStats())
+
+	// 前 6 次读取返回 1000，之后返回 1001：第 5 个 ID 耗尽序列号后自旋等待一次
+	var calls int
+	node.clock = func() int64 {
+		calls++
+		if calls > 6 {
+			return 1001
+		}
+		return 1000
+	}
+
+	// StepBits=2：每毫秒 4 个 ID
+	for range 8 {
+		node.Generate()
+	}
+
+	got := node.Stats()
+	if got.Generated != 8 {
+		t.Errorf("Stats().Generated = %d, want 8", got.Generated)
+	}
+	if got.StepWaits != 1 {
+		t.Errorf("Stats().StepWaits = %d, want 1", got.StepWaits)
+	}
+}
+
+func TestStats_GenerateN(t *testing.T) {
+	for _, opts := range [][]Option{{WithStats()}, {WithStats(), WithLockFree()}} {
+		node, err := NewNode(1, opts...)
+		if err != nil {
+			t.Fatalf("NewNode() error = %v", err)
+		}
+
+		node.GenerateN(10000)
+		node.Generate()
+		if got := node.Stats().Generated; got != 10001 {
+			t.Errorf("Stats().Generated = %d, want 10001", got)
+		}
+	}
+}
+
+func TestStats_WaitTime(t *testing.T) {
+	// StepBits=1：每毫秒 2 个 ID，20 个 ID 至少跨越 10ms，必然发生等待
+	node, err := NewNodeWithLayout(1, Layout{Epoch: Epoch, NodeBits: 10, StepBits: 1}, WithStats())
+	if err != nil {
+		t.Fatalf("NewNodeWithLayout() error = %v", err)
+	}
+	node.GenerateN(20)
+
+	got := node.Stats()
+	if got.StepWaits == 0 {
+		t.Error("Stats().StepWaits = 0, want > 0")
+	}
+	if got.WaitTime <= 0 {
+		t.Errorf("Stats().WaitTime = %v, want > 0", got.WaitTime)
+	}
+}
+
+func TestStats_Disabled(t *testing.T) {
+	node, now := newFakeClockNode(t)
+
+	node.Generate()
+	now.Store(900) // 时钟回拨 100ms
+	node.Generate()
+
+	// 未启用 WithStats 时仍统计回拨
+	want := Stats{Rollbacks: 1, ClockBehind: true}
+	if got := node.Stats(); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+
+	now.Store(2000)
+	node.Generate()
+	if got := node.Stats(); got.ClockBehind {
+		t.Error("Stats().ClockBehind = true after the clock caught up, want false")
+	}
+}
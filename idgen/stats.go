@@ -0,0 +1,75 @@
+package idgen
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/chinayin/gox/idgen/snowflake"
+)
+
+// statsContentType is the content type of the Prometheus text exposition format.
+const statsContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// WriteStats writes the stats of gens to w in the Prometheus text exposition
+// format, labeled by node ID:
+//
+//	idgen_snowflake_ids_generated_total{node="1"} 10240
+//	idgen_snowflake_step_waits_total{node="1"} 2
+//	idgen_snowflake_step_wait_seconds_total{node="1"} 0.001532
+//	idgen_snowflake_clock_rollbacks_total{node="1"} 0
+//	idgen_snowflake_clock_behind{node="1"} 0
+func WriteStats(w io.Writer, gens ...*Snowflake) error {
+	metrics := []struct {
+		name, typ, help string
+		value           func(snowflake.Stats) string
+	}{
+		{"idgen_snowflake_ids_generated_total", "counter", "IDs generated.", func(s snowflake.Stats) string {
+			return strconv.FormatUint(s.Generated, 10)
+		}},
+		{"idgen_snowflake_step_waits_total", "counter", "Waits for the next millisecond after the step space was exhausted.", func(s snowflake.Stats) string {
+			return strconv.FormatUint(s.StepWaits, 10)
+		}},
+		{"idgen_snowflake_step_wait_seconds_total", "counter", "Time spent waiting after the step space was exhausted.", func(s snowflake.Stats) string {
+			return strconv.FormatFloat(s.WaitTime.Seconds(), 'g', -1, 64)
+		}},
+		{"idgen_snowflake_clock_rollbacks_total", "counter", "Times the clock moved backwards.", func(s snowflake.Stats) string {
+			return strconv.FormatUint(s.Rollbacks, 10)
+		}},
+		{"idgen_snowflake_clock_behind", "gauge", "Whether the clock is behind the last ID's timestamp (1) or not (0).", func(s snowflake.Stats) string {
+			if s.ClockBehind {
+				return "1"
+			}
+			return "0"
+		}},
+	}
+
+	// Snapshot each generator once so that its metrics are consistent.
+	stats := make([]snowflake.Stats, len(gens))
+	for i, g := range gens {
+		stats[i] = g.Stats()
+	}
+
+	var b strings.Builder
+	for _, m := range metrics {
+		b.WriteString("# HELP " + m.name + " " + m.help + "\n")
+		b.WriteString("# TYPE " + m.name + " " + m.typ + "\n")
+		for i, g := range gens {
+			b.WriteString(m.name + `{node="` + strconv.FormatInt(g.NodeID(), 10) + `"} ` + m.value(stats[i]) + "\n")
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// StatsHandler returns an http.Handler that serves the stats of gens in the
+// Prometheus text exposition format:
+//
+//	http.Handle("/metrics/idgen", idgen.StatsHandler(gen))
+func StatsHandler(gens ...*Snowflake) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", statsContentType)
+		_ = WriteStats(w, gens...)
+	})
+}
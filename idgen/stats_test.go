@@ -0,0 +1,88 @@
+package idgen
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/chinayin/gox/idgen/snowflake"
+)
+
+func TestNewSnowflakeWithOptions(t *testing.T) {
+	gen, err := NewSnowflakeWithOptions(3, snowflake.WithStats(), snowflake.WithLockFree())
+	if err != nil {
+		t.Fatalf("NewSnowflakeWithOptions() error = %v", err)
+	}
+	if gen.NodeID() != 3 {
+		t.Errorf("NodeID() = %d, want 3", gen.NodeID())
+	}
+
+	if _, err := NewSnowflakeWithOptions(1024); err == nil {
+		t.Error("NewSnowflakeWithOptions(1024) error = nil, want error")
+	}
+}
+
+func TestSnowflake_Stats(t *testing.T) {
+	gen, err := NewSnowflakeWithOptions(1, snowflake.WithStats())
+	if err != nil {
+		t.Fatalf("NewSnowflakeWithOptions() error = %v", err)
+	}
+	gen.Generate()
+	gen.GenerateN(99)
+	if got := gen.Stats().Generated; got != 100 {
+		t.Errorf("Stats().Generated = %d, want 100", got)
+	}
+
+	// Without snowflake.WithStats only rollbacks are tracked.
+	plain, err := NewSnowflake()
+	if err != nil {
+		t.Fatalf("NewSnowflake() error = %v", err)
+	}
+	plain.Generate()
+	if got := plain.Stats(); got != (snowflake.Stats{}) {
+		t.Errorf("Stats() = %+v, want zero", got)
+	}
+}
+
+func TestWriteStats(t *testing.T) {
+	gen1, _ := NewSnowflakeWithOptions(1, snowflake.WithStats())
+	gen2, _ := NewSnowflakeWithOptions(2, snowflake.WithStats())
+	gen1.GenerateN(5)
+	gen2.Generate()
+
+	var b strings.Builder
+	if err := WriteStats(&b, gen1, gen2); err != nil {
+		t.Fatalf("WriteStats() error = %v", err)
+	}
+	out := b.String()
+
+	for _, want := range []string{
+		"# TYPE idgen_snowflake_ids_generated_total counter\n",
+		`idgen_snowflake_ids_generated_total{node="1"} 5` + "\n",
+		`idgen_snowflake_ids_generated_total{node="2"} 1` + "\n",
+		"# TYPE idgen_snowflake_step_waits_total counter\n",
+		"# TYPE idgen_snowflake_step_wait_seconds_total counter\n",
+		`idgen_snowflake_clock_rollbacks_total{node="2"} 0` + "\n",
+		"# TYPE idgen_snowflake_clock_behind gauge\n",
+		`idgen_snowflake_clock_behind{node="1"} 0` + "\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("WriteStats() output missing %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestStatsHandler(t *testing.T) {
+	gen, _ := NewSnowflakeWithOptions(7, snowflake.WithStats())
+	gen.Generate()
+
+	rec := httptest.NewRecorder()
+	StatsHandler(gen).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+
+	if got := rec.Header().Get("Content-Type"); got != statsContentType {
+		t.Errorf("Content-Type = %q, want %q", got, statsContentType)
+	}
+	if want := `idgen_snowflake_ids_generated_total{node="7"} 1`; !strings.Contains(rec.Body.String(), want) {
+		t.Errorf("body missing %q:\n%s", want, rec.Body.String())
+	}
+}
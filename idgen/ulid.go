@@ -0,0 +1,35 @@
+package idgen
+
+import "github.com/chinayin/gox/idgen/ulid"
+
+// ULID wraps ulid.Generator to implement the Generator interface.
+//
+// The IDs it returns have no int64 form: Int64 returns 0, String returns the
+// 26-character ULID and Unwrap returns a ulid.ULID.
+type ULID struct {
+	gen *ulid.Generator
+}
+
+// NewULID creates a new ULID generator. ULIDs need no node ID; they stay
+// unique across processes through their 80 random bits.
+func NewULID() *ULID {
+	return &ULID{gen: ulid.NewGenerator()}
+}
+
+// Generate creates a new unique ID.
+func (g *ULID) Generate() ID {
+	return ulidID(g.gen.Generate())
+}
+
+// ParseULID parses a ULID string into an ID.
+func ParseULID(s string) (ID, error) {
+	u, err := ulid.Parse(s)
+	if err != nil {
+		return ID{}, err
+	}
+	return ulidID(u), nil
+}
+
+func ulidID(u ulid.ULID) ID {
+	return NewID(0, u.String(), u)
+}
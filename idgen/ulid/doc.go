@@ -0,0 +1,32 @@
+// Package ulid implements ULIDs, Universally Unique Lexicographically
+// Sortable Identifiers (https://github.com/ulid/spec).
+//
+// # ID Structure (128 bit)
+//
+//	+------------------------------------------------------------+
+//	| 48 Bit Unix ms Timestamp | 80 Bit Randomness               |
+//	+------------------------------------------------------------+
+//
+// The string form is 26 Crockford base32 characters, e.g.
+// 01ARZ3NDEKTSV4RRFFQ69G5FAV: 10 for the timestamp and 16 for the randomness.
+//
+// # Features
+//
+//   - Sorts by creation time, as bytes and as strings
+//   - Strictly increasing within a process, even within one millisecond
+//   - Case-insensitive parsing
+//   - Thread-safe generation
+//
+// # Basic Usage
+//
+//	id := ulid.New()
+//	fmt.Println(id.String())
+//	fmt.Println(id.Time())
+//
+//	id, err := ulid.Parse("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Use idgen.NewULID to generate ULIDs through the idgen.Generator interface.
+package ulid
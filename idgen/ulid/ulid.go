@@ -0,0 +1,219 @@
+package ulid
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrInvalidULID is returned by Parse when the input is not a valid ULID.
+var ErrInvalidULID = errors.New("gox/idgen/ulid: invalid ULID")
+
+// encoding is the Crockford base32 alphabet used by ULIDs.
+const encoding = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// maxMillis is the largest timestamp the 48-bit field holds.
+const maxMillis = 1<<48 - 1
+
+var decoding [256]byte
+
+func init() {
+	for i := range decoding {
+		decoding[i] = 0xFF
+	}
+	for i := range len(encoding) {
+		decoding[encoding[i]] = byte(i)
+		decoding[encoding[i]|0x20] = byte(i) // lower case
+	}
+}
+
+// A ULID is a Universally Unique Lexicographically Sortable Identifier: a
+// 48-bit unix timestamp in milliseconds followed by 80 bits of random data,
+// written as 26 Crockford base32 characters.
+type ULID [16]byte
+
+// Zero is the zero ULID.
+var Zero ULID
+
+// New returns a new ULID from the package's default Generator.
+func New() ULID {
+	return defaultGen.Generate()
+}
+
+var defaultGen = NewGenerator()
+
+// Parse parses the 26-character string form of a ULID, in either case.
+func Parse(s string) (ULID, error) {
+	if len(s) != 26 {
+		return Zero, fmt.Errorf("%w: %q has length %d", ErrInvalidULID, s, len(s))
+	}
+	// 26 characters hold 130 bits; the first may only use the lower 3.
+	if decoding[s[0]] > 7 {
+		return Zero, fmt.Errorf("%w: %q overflows 128 bits", ErrInvalidULID, s)
+	}
+
+	var hi, lo uint64 // bits 127..64 and 63..0
+	for i := range len(s) {
+		d := decoding[s[i]]
+		if d == 0xFF {
+			return Zero, fmt.Errorf("%w: %q", ErrInvalidULID, s)
+		}
+		hi = hi<<5 | lo>>59
+		lo = lo<<5 | uint64(d)
+	}
+
+	var u ULID
+	binary.BigEndian.PutUint64(u[:8], hi)
+	binary.BigEndian.PutUint64(u[8:], lo)
+	return u, nil
+}
+
+// FromBytes returns the ULID stored in b, which must hold 16 bytes.
+func FromBytes(b []byte) (ULID, error) {
+	var u ULID
+	if len(b) != len(u) {
+		return Zero, fmt.Errorf("%w: %d bytes", ErrInvalidULID, len(b))
+	}
+	copy(u[:], b)
+	return u, nil
+}
+
+// MustParse is like Parse but panics if s cannot be parsed.
+func MustParse(s string) ULID {
+	u, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// String returns the 26-character upper case form of the ULID.
+func (u ULID) String() string {
+	hi := binary.BigEndian.Uint64(u[:8])
+	lo := binary.BigEndian.Uint64(u[8:])
+
+	var b [26]byte
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i] = encoding[lo&0x1F]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(b[:])
+}
+
+// Bytes returns the 16 bytes of the ULID.
+func (u ULID) Bytes() []byte {
+	return u[:]
+}
+
+// MarshalText implements encoding.TextMarshaler using the String form.
+func (u ULID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using Parse.
+func (u *ULID) UnmarshalText(b []byte) error {
+	v, err := Parse(string(b))
+	if err != nil {
+		return err
+	}
+	*u = v
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler using the 16 bytes.
+func (u ULID) MarshalBinary() ([]byte, error) {
+	return u[:], nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler using FromBytes.
+func (u *ULID) UnmarshalBinary(b []byte) error {
+	v, err := FromBytes(b)
+	if err != nil {
+		return err
+	}
+	*u = v
+	return nil
+}
+
+// IsZero reports whether u is Zero.
+func (u ULID) IsZero() bool {
+	return u == Zero
+}
+
+// UnixMilli returns the timestamp of the ULID in unix milliseconds.
+func (u ULID) UnixMilli() int64 {
+	return int64(u[0])<<40 | int64(u[1])<<32 | int64(u[2])<<24 |
+		int64(u[3])<<16 | int64(u[4])<<8 | int64(u[5])
+}
+
+// Time returns the timestamp of the ULID.
+func (u ULID) Time() time.Time {
+	return time.UnixMilli(u.UnixMilli())
+}
+
+// A Generator creates ULIDs that increase strictly within a process.
+//
+// ULIDs generated in the same millisecond increment the random part by one,
+// as in the ULID monotonicity spec. Instead of failing when the random part
+// overflows or the clock moves backwards, the generator keeps counting from
+// the last timestamp and lets it run ahead of the clock.
+type Generator struct {
+	mu    sync.Mutex
+	clock func() int64 // unix milliseconds
+	ms    int64
+	hi    uint16 // random bits 79..64
+	lo    uint64 // random bits 63..0
+}
+
+// NewGenerator returns a Generator using the system clock.
+func NewGenerator() *Generator {
+	return &Generator{clock: func() int64 { return time.Now().UnixMilli() }}
+}
+
+// Generate returns a new ULID greater than any ULID previously returned by g.
+// It is safe for concurrent use.
+func (g *Generator) Generate() ULID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if now := g.clock(); now > g.ms {
+		g.ms = now
+		g.reseed()
+	} else if !g.increment() {
+		g.ms++
+		g.reseed()
+	}
+	return g.compose()
+}
+
+// reseed fills the random part. The top bit is cleared so that the counter
+// has plenty of room before it overflows.
+func (g *Generator) reseed() {
+	var b [10]byte
+	_, _ = rand.Read(b[:])
+	g.hi = binary.BigEndian.Uint16(b[:2]) & 0x7fff
+	g.lo = binary.BigEndian.Uint64(b[2:])
+}
+
+// increment adds one to the 80-bit random part and reports false on overflow.
+func (g *Generator) increment() bool {
+	g.lo++
+	if g.lo != 0 {
+		return true
+	}
+	g.hi++
+	return g.hi != 0
+}
+
+// compose builds the ULID from the generator state.
+func (g *Generator) compose() ULID {
+	var u ULID
+	ms := uint64(g.ms) & maxMillis
+	binary.BigEndian.PutUint64(u[0:8], ms<<16|uint64(g.hi))
+	binary.BigEndian.PutUint64(u[8:16], g.lo)
+	return u
+}
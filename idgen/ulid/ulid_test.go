@@ -0,0 +1,151 @@
+package ulid
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	// ULID 规范中的示例
+	const spec = "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "upper case", input: spec, want: spec},
+		{name: "lower case", input: strings.ToLower(spec), want: spec},
+		{name: "max", input: "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", want: "7ZZZZZZZZZZZZZZZZZZZZZZZZZ"},
+		{name: "overflow", input: "8ZZZZZZZZZZZZZZZZZZZZZZZZZ", wantErr: true},
+		{name: "invalid character", input: "01ARZ3NDEKTSV4RRFFQ69G5FAU", wantErr: true},
+		{name: "too long", input: spec + "0", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrInvalidULID) {
+					t.Errorf("Parse() error = %v, want %v", err, ErrInvalidULID)
+				}
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("Parse().String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	if got := MustParse(spec).UnixMilli(); got != 1469922850259 {
+		t.Errorf("UnixMilli() = %d, want 1469922850259", got)
+	}
+	if got := MustParse("00000000000000000000000000"); !got.IsZero() {
+		t.Errorf("Parse(zero) = %v, want Zero", got)
+	}
+}
+
+func TestNew(t *testing.T) {
+	before := time.Now().UnixMilli()
+	id := New()
+	after := time.Now().UnixMilli()
+
+	if ms := id.UnixMilli(); ms < before || ms > after {
+		t.Errorf("UnixMilli() = %d, want within [%d, %d]", ms, before, after)
+	}
+	if got, err := Parse(id.String()); err != nil || got != id {
+		t.Errorf("Parse(String()) = %s, %v, want %s", got, err, id)
+	}
+}
+
+func TestGenerator_Monotonic(t *testing.T) {
+	g := NewGenerator()
+	g.clock = func() int64 { return 1000 } // 同一毫秒
+
+	last := g.Generate()
+	for range 1000 {
+		id := g.Generate()
+		if bytes.Compare(id[:], last[:]) <= 0 || id.String() <= last.String() {
+			t.Fatalf("ULID %s not greater than %s", id, last)
+		}
+		last = id
+	}
+
+	// 时钟回拨后仍递增
+	g.clock = func() int64 { return 900 }
+	if id := g.Generate(); bytes.Compare(id[:], last[:]) <= 0 {
+		t.Errorf("ULID %s not greater than %s after clock rollback", id, last)
+	}
+
+	// 随机段用尽后借用下一毫秒
+	g.hi, g.lo = 1<<16-1, 1<<64-1
+	if id := g.Generate(); id.UnixMilli() != 1001 {
+		t.Errorf("UnixMilli() after overflow = %d, want 1001", id.UnixMilli())
+	}
+}
+
+func TestGenerator_Concurrent(t *testing.T) {
+	g := NewGenerator()
+
+	const goroutines, perGoroutine = 8, 1000
+	var mu sync.Mutex
+	seen := make(map[ULID]bool, goroutines*perGoroutine)
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range perGoroutine {
+				id := g.Generate()
+				mu.Lock()
+				if seen[id] {
+					t.Errorf("duplicate ULID %s", id)
+				}
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkGenerate(b *testing.B) {
+	g := NewGenerator()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		_ = g.Generate()
+	}
+}
+
+func TestULID_Encoding(t *testing.T) {
+	id := New()
+
+	b, err := json.Marshal(id)
+	if err != nil || string(b) != `"`+id.String()+`"` {
+		t.Fatalf("json.Marshal() = %s, %v", b, err)
+	}
+	var got ULID
+	if err := json.Unmarshal(b, &got); err != nil || got != id {
+		t.Errorf("json.Unmarshal() = %s, %v, want %s", got, err, id)
+	}
+
+	bin, _ := id.MarshalBinary()
+	got = Zero
+	if err := got.UnmarshalBinary(bin); err != nil || got != id {
+		t.Errorf("UnmarshalBinary() = %s, %v, want %s", got, err, id)
+	}
+	if err := got.UnmarshalBinary(bin[:8]); !errors.Is(err, ErrInvalidULID) {
+		t.Errorf("UnmarshalBinary(8 bytes) error = %v, want %v", err, ErrInvalidULID)
+	}
+}
@@ -0,0 +1,48 @@
+package idgen
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chinayin/gox/idgen/ulid"
+)
+
+func TestULID_Generate(t *testing.T) {
+	var gen Generator = NewULID()
+
+	id := gen.Generate()
+	if id.Int64() != 0 {
+		t.Errorf("ID.Int64() = %d, want 0", id.Int64())
+	}
+	if id.IsZero() {
+		t.Error("ID should not be zero")
+	}
+	u, ok := id.Unwrap().(ulid.ULID)
+	if !ok {
+		t.Fatalf("ID.Unwrap() type = %T, want ulid.ULID", id.Unwrap())
+	}
+	if u.String() != id.String() {
+		t.Errorf("ID.String() = %s, want %s", id.String(), u)
+	}
+
+	next := gen.Generate()
+	if next.String() <= id.String() {
+		t.Errorf("ID %s not greater than %s", next, id)
+	}
+}
+
+func TestParseULID(t *testing.T) {
+	id := NewULID().Generate()
+
+	got, err := ParseULID(id.String())
+	if err != nil {
+		t.Fatalf("ParseULID() error = %v", err)
+	}
+	if got != id {
+		t.Errorf("ParseULID() = %v, want %v", got, id)
+	}
+
+	if _, err := ParseULID("not-a-ulid"); !errors.Is(err, ulid.ErrInvalidULID) {
+		t.Errorf("ParseULID() error = %v, want %v", err, ulid.ErrInvalidULID)
+	}
+}
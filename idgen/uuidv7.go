@@ -0,0 +1,35 @@
+package idgen
+
+import "github.com/chinayin/gox/idgen/uuidv7"
+
+// UUIDv7 wraps uuidv7.Generator to implement the Generator interface.
+//
+// The IDs it returns have no int64 form: Int64 returns 0, String returns the
+// canonical UUID and Unwrap returns a uuidv7.UUID.
+type UUIDv7 struct {
+	gen *uuidv7.Generator
+}
+
+// NewUUIDv7 creates a new UUIDv7 generator. UUIDs need no node ID; they stay
+// unique across processes through their 74 random bits.
+func NewUUIDv7() *UUIDv7 {
+	return &UUIDv7{gen: uuidv7.NewGenerator()}
+}
+
+// Generate creates a new unique ID.
+func (g *UUIDv7) Generate() ID {
+	return uuidv7ID(g.gen.Generate())
+}
+
+// ParseUUIDv7 parses a version 7 UUID string into an ID.
+func ParseUUIDv7(s string) (ID, error) {
+	u, err := uuidv7.Parse(s)
+	if err != nil {
+		return ID{}, err
+	}
+	return uuidv7ID(u), nil
+}
+
+func uuidv7ID(u uuidv7.UUID) ID {
+	return NewID(0, u.String(), u)
+}
@@ -0,0 +1,27 @@
+// Package uuidv7 implements time-ordered version 7 UUIDs (RFC 9562).
+//
+// # ID Structure (128 bit)
+//
+//	+------------------------------------------------------------------------+
+//	| 48 Bit Unix ms | 4 Bit Ver | 12 Bit Rand A | 2 Bit Var | 62 Bit Rand B |
+//	+------------------------------------------------------------------------+
+//
+// # Features
+//
+//   - Sorts by creation time, as bytes and as strings
+//   - Strictly increasing within a process, even within one millisecond
+//   - Thread-safe generation
+//
+// # Basic Usage
+//
+//	u := uuidv7.New()
+//	fmt.Println(u.String()) // 01890a5d-ac96-774b-bcce-b302099a8057
+//	fmt.Println(u.Time())
+//
+//	u, err := uuidv7.Parse("01890a5d-ac96-774b-bcce-b302099a8057")
+//	if err != nil {
+//	    log.Fatal(err)
+//	}
+//
+// Use idgen.NewUUIDv7 to generate UUIDs through the idgen.Generator interface.
+package uuidv7
@@ -0,0 +1,222 @@
+package uuidv7
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrInvalidUUID is returned by Parse when the input is not a version 7 UUID.
+var ErrInvalidUUID = errors.New("gox/idgen/uuidv7: invalid UUID")
+
+// maxMillis is the largest timestamp the 48-bit field holds.
+const maxMillis = 1<<48 - 1
+
+// A UUID is a version 7 UUID as defined by RFC 9562: a 48-bit unix
+// timestamp in milliseconds followed by 74 bits of random data, with the
+// version and variant bits in between.
+type UUID [16]byte
+
+// Nil is the zero UUID.
+var Nil UUID
+
+// New returns a new UUID from the package's default Generator.
+func New() UUID {
+	return defaultGen.Generate()
+}
+
+var defaultGen = NewGenerator()
+
+// Parse parses the canonical form "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx" or
+// 32 hex digits without hyphens, in either case. It rejects UUIDs that are
+// not version 7 with the RFC 9562 variant.
+func Parse(s string) (UUID, error) {
+	var u UUID
+	var digits string
+	switch len(s) {
+	case 36:
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return Nil, fmt.Errorf("%w: %q", ErrInvalidUUID, s)
+		}
+		digits = s[:8] + s[9:13] + s[14:18] + s[19:23] + s[24:]
+	case 32:
+		digits = s
+	default:
+		return Nil, fmt.Errorf("%w: %q has length %d", ErrInvalidUUID, s, len(s))
+	}
+	if _, err := hex.Decode(u[:], []byte(digits)); err != nil {
+		return Nil, fmt.Errorf("%w: %q", ErrInvalidUUID, s)
+	}
+	if !u.valid() {
+		return Nil, fmt.Errorf("%w: %q is not a version 7 UUID", ErrInvalidUUID, s)
+	}
+	return u, nil
+}
+
+// FromBytes returns the UUID stored in b, which must hold 16 bytes of a
+// version 7 UUID with the RFC 9562 variant.
+func FromBytes(b []byte) (UUID, error) {
+	var u UUID
+	if len(b) != len(u) {
+		return Nil, fmt.Errorf("%w: %d bytes", ErrInvalidUUID, len(b))
+	}
+	copy(u[:], b)
+	if !u.valid() {
+		return Nil, fmt.Errorf("%w: %s is not a version 7 UUID", ErrInvalidUUID, u)
+	}
+	return u, nil
+}
+
+// MustParse is like Parse but panics if s cannot be parsed.
+func MustParse(s string) UUID {
+	u, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// String returns the canonical lowercase form of the UUID.
+func (u UUID) String() string {
+	var b [36]byte
+	hex.Encode(b[0:8], u[0:4])
+	b[8] = '-'
+	hex.Encode(b[9:13], u[4:6])
+	b[13] = '-'
+	hex.Encode(b[14:18], u[6:8])
+	b[18] = '-'
+	hex.Encode(b[19:23], u[8:10])
+	b[23] = '-'
+	hex.Encode(b[24:], u[10:])
+	return string(b[:])
+}
+
+// Bytes returns the 16 bytes of the UUID.
+func (u UUID) Bytes() []byte {
+	return u[:]
+}
+
+// MarshalText implements encoding.TextMarshaler using the String form.
+func (u UUID) MarshalText() ([]byte, error) {
+	return []byte(u.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler using Parse.
+func (u *UUID) UnmarshalText(b []byte) error {
+	v, err := Parse(string(b))
+	if err != nil {
+		return err
+	}
+	*u = v
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler using the 16 bytes.
+func (u UUID) MarshalBinary() ([]byte, error) {
+	return u[:], nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler using FromBytes.
+func (u *UUID) UnmarshalBinary(b []byte) error {
+	v, err := FromBytes(b)
+	if err != nil {
+		return err
+	}
+	*u = v
+	return nil
+}
+
+// IsZero reports whether u is Nil.
+func (u UUID) IsZero() bool {
+	return u == Nil
+}
+
+// Version returns the version number stored in the UUID.
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// valid reports whether u has version 7 and the RFC 9562 variant.
+func (u UUID) valid() bool {
+	return u.Version() == 7 && u[8]&0xc0 == 0x80
+}
+
+// UnixMilli returns the timestamp of the UUID in unix milliseconds.
+func (u UUID) UnixMilli() int64 {
+	return int64(u[0])<<40 | int64(u[1])<<32 | int64(u[2])<<24 |
+		int64(u[3])<<16 | int64(u[4])<<8 | int64(u[5])
+}
+
+// Time returns the timestamp of the UUID.
+func (u UUID) Time() time.Time {
+	return time.UnixMilli(u.UnixMilli())
+}
+
+// A Generator creates UUIDs that increase strictly within a process.
+//
+// UUIDs generated in the same millisecond increment the random field by one
+// (RFC 9562 section 6.2, method 2), so they sort in generation order. If the
+// random field overflows or the clock moves backwards, the generator keeps
+// counting from the last timestamp and lets it run ahead of the clock.
+type Generator struct {
+	mu    sync.Mutex
+	clock func() int64 // unix milliseconds
+	ms    int64
+	randA uint16 // 12 bits
+	randB uint64 // 62 bits
+}
+
+// NewGenerator returns a Generator using the system clock.
+func NewGenerator() *Generator {
+	return &Generator{clock: func() int64 { return time.Now().UnixMilli() }}
+}
+
+// Generate returns a new UUID greater than any UUID previously returned by g.
+// It is safe for concurrent use.
+func (g *Generator) Generate() UUID {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if now := g.clock(); now > g.ms {
+		g.ms = now
+		g.reseed()
+	} else if !g.increment() {
+		g.ms++
+		g.reseed()
+	}
+	return g.compose()
+}
+
+// reseed fills the random fields. The top bit of rand_b is cleared so that
+// the counter has plenty of room before it overflows.
+func (g *Generator) reseed() {
+	var b [10]byte
+	_, _ = rand.Read(b[:])
+	g.randA = binary.BigEndian.Uint16(b[:2]) & 0x0fff
+	g.randB = binary.BigEndian.Uint64(b[2:]) & (1<<61 - 1)
+}
+
+// increment adds one to the 74-bit random field and reports false on
+// overflow.
+func (g *Generator) increment() bool {
+	g.randB++
+	if g.randB < 1<<62 {
+		return true
+	}
+	g.randB = 0
+	g.randA++
+	return g.randA < 1<<12
+}
+
+// compose builds the UUID from the generator state.
+func (g *Generator) compose() UUID {
+	var u UUID
+	ms := uint64(g.ms) & maxMillis
+	binary.BigEndian.PutUint64(u[0:8], ms<<16|0x7000|uint64(g.randA))
+	binary.BigEndian.PutUint64(u[8:16], 0x8000000000000000|g.randB)
+	return u
+}
@@ -0,0 +1,173 @@
+package uuidv7
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	// RFC 9562 附录 A.6 示例
+	const rfc = "017f22e2-79b0-7cc3-98c4-dc0c0c07398f"
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "canonical", input: rfc, want: rfc},
+		{name: "upper case", input: strings.ToUpper(rfc), want: rfc},
+		{name: "no hyphens", input: strings.ReplaceAll(rfc, "-", ""), want: rfc},
+		{name: "misplaced hyphen", input: "017f22e279-b0-7cc3-98c4-dc0c0c07398f", wantErr: true},
+		{name: "not hex", input: "017f22e2-79b0-7cc3-98c4-dc0c0c07398g", wantErr: true},
+		{name: "version 4", input: "017f22e2-79b0-4cc3-98c4-dc0c0c07398f", wantErr: true},
+		{name: "wrong variant", input: "017f22e2-79b0-7cc3-c8c4-dc0c0c07398f", wantErr: true},
+		{name: "too short", input: "017f22e2", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, ErrInvalidUUID) {
+					t.Errorf("Parse() error = %v, want %v", err, ErrInvalidUUID)
+				}
+				return
+			}
+			if got.String() != tt.want {
+				t.Errorf("Parse().String() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	u := MustParse(rfc)
+	if got := u.UnixMilli(); got != 0x017F22E279B0 {
+		t.Errorf("UnixMilli() = %d, want %d", got, int64(0x017F22E279B0))
+	}
+	if got := u.Time().UTC(); !got.Equal(time.Date(2022, 2, 22, 19, 22, 22, 0, time.UTC)) {
+		t.Errorf("Time() = %v, want 2022-02-22 19:22:22 UTC", got)
+	}
+	if u.Version() != 7 {
+		t.Errorf("Version() = %d, want 7", u.Version())
+	}
+}
+
+func TestNew(t *testing.T) {
+	before := time.Now().UnixMilli()
+	u := New()
+	after := time.Now().UnixMilli()
+
+	if u.Version() != 7 || u[8]&0xc0 != 0x80 {
+		t.Errorf("New() = %s, want version 7 with RFC 9562 variant", u)
+	}
+	if ms := u.UnixMilli(); ms < before || ms > after {
+		t.Errorf("UnixMilli() = %d, want within [%d, %d]", ms, before, after)
+	}
+	if got, err := Parse(u.String()); err != nil || got != u {
+		t.Errorf("Parse(String()) = %s, %v, want %s", got, err, u)
+	}
+}
+
+func TestGenerator_Monotonic(t *testing.T) {
+	g := NewGenerator()
+	g.clock = func() int64 { return 1000 } // 同一毫秒
+
+	last := g.Generate()
+	for range 1000 {
+		u := g.Generate()
+		if bytes.Compare(u[:], last[:]) <= 0 || u.String() <= last.String() {
+			t.Fatalf("UUID %s not greater than %s", u, last)
+		}
+		if u.UnixMilli() != 1000 {
+			t.Fatalf("UnixMilli() = %d, want 1000", u.UnixMilli())
+		}
+		last = u
+	}
+
+	// 时钟回拨后仍递增
+	g.clock = func() int64 { return 900 }
+	if u := g.Generate(); bytes.Compare(u[:], last[:]) <= 0 {
+		t.Errorf("UUID %s not greater than %s after clock rollback", u, last)
+	}
+}
+
+func TestGenerator_Overflow(t *testing.T) {
+	g := NewGenerator()
+	g.clock = func() int64 { return 1000 }
+	g.Generate()
+
+	// 随机段用尽后借用下一毫秒
+	g.randA, g.randB = 1<<12-1, 1<<62-1
+	u := g.Generate()
+	if u.UnixMilli() != 1001 {
+		t.Errorf("UnixMilli() after overflow = %d, want 1001", u.UnixMilli())
+	}
+	if u.Version() != 7 || u[8]&0xc0 != 0x80 {
+		t.Errorf("UUID %s lost version or variant bits", u)
+	}
+}
+
+func TestGenerator_Concurrent(t *testing.T) {
+	g := NewGenerator()
+
+	const goroutines, perGoroutine = 8, 1000
+	var mu sync.Mutex
+	seen := make(map[UUID]bool, goroutines*perGoroutine)
+	var wg sync.WaitGroup
+	for range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range perGoroutine {
+				u := g.Generate()
+				mu.Lock()
+				if seen[u] {
+					t.Errorf("duplicate UUID %s", u)
+				}
+				seen[u] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkGenerate(b *testing.B) {
+	g := NewGenerator()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		_ = g.Generate()
+	}
+}
+
+func TestUUID_Encoding(t *testing.T) {
+	u := New()
+
+	b, err := json.Marshal(u)
+	if err != nil || string(b) != `"`+u.String()+`"` {
+		t.Fatalf("json.Marshal() = %s, %v", b, err)
+	}
+	var got UUID
+	if err := json.Unmarshal(b, &got); err != nil || got != u {
+		t.Errorf("json.Unmarshal() = %s, %v, want %s", got, err, u)
+	}
+
+	bin, _ := u.MarshalBinary()
+	got = Nil
+	if err := got.UnmarshalBinary(bin); err != nil || got != u {
+		t.Errorf("UnmarshalBinary() = %s, %v, want %s", got, err, u)
+	}
+	if err := got.UnmarshalBinary(make([]byte, 16)); !errors.Is(err, ErrInvalidUUID) {
+		t.Errorf("UnmarshalBinary(zeros) error = %v, want %v", err, ErrInvalidUUID)
+	}
+}
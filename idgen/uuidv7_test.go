@@ -0,0 +1,48 @@
+package idgen
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/chinayin/gox/idgen/uuidv7"
+)
+
+func TestUUIDv7_Generate(t *testing.T) {
+	var gen Generator = NewUUIDv7()
+
+	id := gen.Generate()
+	if id.Int64() != 0 {
+		t.Errorf("ID.Int64() = %d, want 0", id.Int64())
+	}
+	if id.IsZero() {
+		t.Error("ID should not be zero")
+	}
+	u, ok := id.Unwrap().(uuidv7.UUID)
+	if !ok {
+		t.Fatalf("ID.Unwrap() type = %T, want uuidv7.UUID", id.Unwrap())
+	}
+	if u.String() != id.String() {
+		t.Errorf("ID.String() = %s, want %s", id.String(), u)
+	}
+
+	next := gen.Generate()
+	if next.String() <= id.String() {
+		t.Errorf("ID %s not greater than %s", next, id)
+	}
+}
+
+func TestParseUUIDv7(t *testing.T) {
+	id := NewUUIDv7().Generate()
+
+	got, err := ParseUUIDv7(id.String())
+	if err != nil {
+		t.Fatalf("ParseUUIDv7() error = %v", err)
+	}
+	if got != id {
+		t.Errorf("ParseUUIDv7() = %v, want %v", got, id)
+	}
+
+	if _, err := ParseUUIDv7("not-a-uuid"); !errors.Is(err, uuidv7.ErrInvalidUUID) {
+		t.Errorf("ParseUUIDv7() error = %v, want %v", err, uuidv7.ErrInvalidUUID)
+	}
+}
@@ -0,0 +1,45 @@
+// Package sentinel keeps the registry of named sentinel errors.
+//
+// Packages register their own sentinels in init, and log names them when it
+// expands error chains. The registry has no dependencies so that any gox
+// package can register without importing log.
+package sentinel
+
+import (
+	"reflect"
+	"sync"
+)
+
+var registry = struct {
+	mu    sync.RWMutex
+	items []entry
+}{}
+
+type entry struct {
+	name string
+	err  error
+}
+
+// Register records err under name, e.g. "config.ErrNotFound". Nil errors and
+// errors whose dynamic type is not comparable are ignored.
+func Register(name string, err error) {
+	if err == nil || !reflect.TypeOf(err).Comparable() {
+		return
+	}
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	registry.items = append(registry.items, entry{name: name, err: err})
+}
+
+// Name returns the name err was registered under, or "" if it is not a
+// registered sentinel. It matches identity only, not errors.Is.
+func Name(err error) string {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	for _, e := range registry.items {
+		if reflect.TypeOf(err) == reflect.TypeOf(e.err) && err == e.err {
+			return e.name
+		}
+	}
+	return ""
+}
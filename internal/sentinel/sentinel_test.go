@@ -0,0 +1,41 @@
+package sentinel
+
+import (
+	"errors"
+	"testing"
+)
+
+type codeError struct{ code int }
+
+func (e codeError) Error() string { return "code" }
+
+type sliceError []string
+
+func (sliceError) Error() string { return "slice" }
+
+func TestRegister(t *testing.T) {
+	errCustom := errors.New("custom")
+	Register("app.ErrCustom", errCustom)
+	Register("app.ErrCode", codeError{code: 1})
+	Register("app.ErrSlice", sliceError{"a"}) // not comparable, ignored
+	Register("app.ErrNil", nil)
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{name: "registered", err: errCustom, want: "app.ErrCustom"},
+		{name: "same message", err: errors.New("custom"), want: ""},
+		{name: "comparable value", err: codeError{code: 1}, want: "app.ErrCode"},
+		{name: "other value", err: codeError{code: 2}, want: ""},
+		{name: "not comparable", err: sliceError{"a"}, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Name(tt.err); got != tt.want {
+				t.Errorf("Name() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
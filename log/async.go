@@ -0,0 +1,248 @@
+package log
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 缓冲区溢出策略
+const (
+	OverflowBlock      = "block"       // 阻塞写入方，直到缓冲区有空位
+	OverflowDropOldest = "drop_oldest" // 丢弃缓冲区中最旧的记录
+	OverflowDropNewest = "drop_newest" // 丢弃当前写入的记录
+)
+
+// 异步写入默认值
+const (
+	DefaultAsyncBufferSize   = 1024            // 默认缓冲记录条数
+	DefaultAsyncCloseTimeout = 5 * time.Second // 默认关闭时的刷新超时
+)
+
+// AsyncOptions 异步写入配置
+type AsyncOptions struct {
+	// 以下字段仅用于 log.New（AsyncWriter），zap 适配器忽略
+	BufferSize   int           `mapstructure:"buffer_size" yaml:"buffer_size"`                                                    // 缓冲区可容纳的记录条数，默认 1024
+	Overflow     string        `mapstructure:"overflow" validate:"omitempty,oneof=block drop_oldest drop_newest" yaml:"overflow"` // 溢出策略: block, drop_oldest, drop_newest，默认 block
+	CloseTimeout time.Duration `mapstructure:"close_timeout" yaml:"close_timeout"`                                                // Close 时等待刷新的超时，默认 5s
+
+	// 以下字段仅用于 zap 适配器（zapcore.BufferedWriteSyncer），
+	// 缓冲满时同步写出而不丢弃记录，因此没有溢出策略与丢弃计数
+	BufferBytes   int           `mapstructure:"buffer_bytes" yaml:"buffer_bytes"`     // 缓冲字节数，默认 256KB
+	FlushInterval time.Duration `mapstructure:"flush_interval" yaml:"flush_interval"` // 定时刷新间隔，默认 30s
+}
+
+// AsyncWriter 异步缓冲写入器
+//
+// 每次 Write 视为一条完整记录，放入有界环形缓冲区后立即返回，
+// 由后台 goroutine 批量写入下游。缓冲区满时按溢出策略处理。
+// 经 WriteRecord 写入的记录会连同级别与消息转交给实现 RecordWriter 的下游。
+type AsyncWriter struct {
+	w            io.Writer
+	overflow     string
+	closeTimeout time.Duration
+
+	mu       sync.Mutex
+	notEmpty *sync.Cond
+	notFull  *sync.Cond
+	ring     []asyncRecord
+	head     int
+	size     int
+	closed   bool
+
+	dropped atomic.Uint64
+	err     atomic.Pointer[error]
+	done    chan struct{}
+}
+
+var (
+	_ io.WriteCloser = (*AsyncWriter)(nil)
+	_ RecordWriter   = (*AsyncWriter)(nil)
+)
+
+// asyncRecord 缓冲区中的一条记录
+type asyncRecord struct {
+	line   []byte
+	level  slog.Level
+	msg    string
+	record bool // 经 WriteRecord 写入，携带级别与消息
+}
+
+// NewAsyncWriter 创建异步写入器并启动后台刷新 goroutine
+// Close 只负责刷新缓冲区，不会关闭下游 w；未知的溢出策略返回 ErrInvalidOverflow
+func NewAsyncWriter(w io.Writer, opts AsyncOptions) (*AsyncWriter, error) {
+	if err := opts.validateOverflow(); err != nil {
+		return nil, err
+	}
+
+	size := opts.BufferSize
+	if size <= 0 {
+		size = DefaultAsyncBufferSize
+	}
+	timeout := opts.CloseTimeout
+	if timeout <= 0 {
+		timeout = DefaultAsyncCloseTimeout
+	}
+	overflow := opts.Overflow
+	if overflow == "" {
+		overflow = OverflowBlock
+	}
+
+	a := &AsyncWriter{
+		w:            w,
+		overflow:     overflow,
+		closeTimeout: timeout,
+		ring:         make([]asyncRecord, size),
+		done:         make(chan struct{}),
+	}
+	a.notEmpty = sync.NewCond(&a.mu)
+	a.notFull = sync.NewCond(&a.mu)
+
+	go a.run()
+	return a, nil
+}
+
+// validateOverflow 验证溢出策略，空值表示使用默认值 block
+func (o AsyncOptions) validateOverflow() error {
+	switch o.Overflow {
+	case "", OverflowBlock, OverflowDropOldest, OverflowDropNewest:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q (want block, drop_oldest or drop_newest)", ErrInvalidOverflow, o.Overflow)
+	}
+}
+
+// Write 将一条记录放入缓冲区
+// 调用方可能复用 p，因此这里会拷贝一份
+func (a *AsyncWriter) Write(p []byte) (int, error) {
+	return a.enqueue(asyncRecord{line: p})
+}
+
+// WriteRecord 将一条带级别与消息的记录放入缓冲区
+// 下游实现 RecordWriter 时原样转交元数据，否则按 Write 写出
+func (a *AsyncWriter) WriteRecord(level slog.Level, msg string, p []byte) (int, error) {
+	return a.enqueue(asyncRecord{line: p, level: level, msg: msg, record: true})
+}
+
+// enqueue 拷贝记录内容并按溢出策略放入缓冲区
+func (a *AsyncWriter) enqueue(rec asyncRecord) (int, error) {
+	p := rec.line
+	rec.line = make([]byte, len(p))
+	copy(rec.line, p)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.overflow == OverflowBlock {
+		for a.size == len(a.ring) && !a.closed {
+			a.notFull.Wait()
+		}
+	}
+	if a.closed {
+		return 0, ErrWriterClosed
+	}
+
+	if a.size == len(a.ring) {
+		a.dropped.Add(1)
+		if a.overflow == OverflowDropNewest {
+			return len(p), nil
+		}
+		// drop_oldest：覆盖队头
+		a.ring[a.head] = asyncRecord{}
+		a.head = (a.head + 1) % len(a.ring)
+		a.size--
+	}
+
+	a.ring[(a.head+a.size)%len(a.ring)] = rec
+	a.size++
+	a.notEmpty.Signal()
+	return len(p), nil
+}
+
+// Dropped 返回因缓冲区溢出而丢弃的记录数
+func (a *AsyncWriter) Dropped() uint64 {
+	return a.dropped.Load()
+}
+
+// Buffered 返回缓冲区中尚未写出的记录数
+func (a *AsyncWriter) Buffered() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.size
+}
+
+// Close 停止接收新记录并等待缓冲区刷新完毕
+// 超过 CloseTimeout 仍未刷新完成时返回 ErrFlushTimeout，后台 goroutine 会继续
+// 写出剩余记录，此时调用方不应立即关闭下游 w；
+// 刷新过程中下游写入失败时返回首个写入错误
+func (a *AsyncWriter) Close() error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		a.notEmpty.Broadcast()
+		a.notFull.Broadcast()
+	}
+	a.mu.Unlock()
+
+	timer := time.NewTimer(a.closeTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-a.done:
+	case <-timer.C:
+		return fmt.Errorf("%w: %s", ErrFlushTimeout, a.closeTimeout)
+	}
+
+	if err := a.err.Load(); err != nil {
+		return *err
+	}
+	return nil
+}
+
+// run 后台刷新循环
+func (a *AsyncWriter) run() {
+	defer close(a.done)
+
+	rw, _ := a.w.(RecordWriter)
+	batch := make([]asyncRecord, 0, len(a.ring))
+	for {
+		a.mu.Lock()
+		for a.size == 0 && !a.closed {
+			a.notEmpty.Wait()
+		}
+		if a.size == 0 && a.closed {
+			a.mu.Unlock()
+			return
+		}
+
+		batch = batch[:0]
+		for a.size > 0 {
+			batch = append(batch, a.ring[a.head])
+			a.ring[a.head] = asyncRecord{}
+			a.head = (a.head + 1) % len(a.ring)
+			a.size--
+		}
+		a.notFull.Broadcast()
+		a.mu.Unlock()
+
+		for _, rec := range batch {
+			if err := a.write(rw, rec); err != nil {
+				a.err.CompareAndSwap(nil, &err)
+			}
+		}
+	}
+}
+
+// write 将一条记录写入下游，rw 为实现 RecordWriter 的下游（可为 nil）
+func (a *AsyncWriter) write(rw RecordWriter, rec asyncRecord) error {
+	var err error
+	if rec.record && rw != nil {
+		_, err = rw.WriteRecord(rec.level, rec.msg, rec.line)
+	} else {
+		_, err = a.w.Write(rec.line)
+	}
+	return err
+}
@@ -0,0 +1,270 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// syncBuffer 并发安全的 bytes.Buffer
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// gateWriter 在 gate 关闭前阻塞写入，用于模拟慢速输出
+type gateWriter struct {
+	gate chan struct{}
+	out  syncBuffer
+}
+
+func (g *gateWriter) Write(p []byte) (int, error) {
+	<-g.gate
+	return g.out.Write(p)
+}
+
+// errWriter 总是返回写入错误
+type errWriter struct{}
+
+var errWrite = errors.New("write failed")
+
+func (errWriter) Write([]byte) (int, error) { return 0, errWrite }
+
+// newAsyncWriter 创建 AsyncWriter，失败时终止测试
+func newAsyncWriter(t *testing.T, w io.Writer, opts AsyncOptions) *AsyncWriter {
+	t.Helper()
+	aw, err := NewAsyncWriter(w, opts)
+	if err != nil {
+		t.Fatalf("NewAsyncWriter() error = %v", err)
+	}
+	return aw
+}
+
+func TestAsyncWriter_FlushOnClose(t *testing.T) {
+	var out syncBuffer
+	w := newAsyncWriter(t, &out, AsyncOptions{BufferSize: 8})
+
+	for range 100 {
+		if _, err := w.Write([]byte("line\n")); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if got := strings.Count(out.String(), "line\n"); got != 100 {
+		t.Errorf("flushed records = %d, want 100", got)
+	}
+	if got := w.Dropped(); got != 0 {
+		t.Errorf("Dropped() = %d, want 0 with block policy", got)
+	}
+	if _, err := w.Write([]byte("late\n")); !errors.Is(err, ErrWriterClosed) {
+		t.Errorf("Write() after Close error = %v, want ErrWriterClosed", err)
+	}
+}
+
+func TestAsyncWriter_CopiesInput(t *testing.T) {
+	var out syncBuffer
+	w := newAsyncWriter(t, &out, AsyncOptions{})
+
+	buf := []byte("first\n")
+	_, _ = w.Write(buf)
+	copy(buf, "XXXXX\n")
+	_ = w.Close()
+
+	if out.String() != "first\n" {
+		t.Errorf("output = %q, want %q", out.String(), "first\n")
+	}
+}
+
+func TestAsyncWriter_Overflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		overflow string
+		want     string
+	}{
+		{name: "drop newest", overflow: OverflowDropNewest, want: "0\n1\n2\n"},
+		{name: "drop oldest", overflow: OverflowDropOldest, want: "0\n3\n4\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gw := &gateWriter{gate: make(chan struct{})}
+			w := newAsyncWriter(t, gw, AsyncOptions{BufferSize: 2, Overflow: tt.overflow})
+
+			// 第一条被后台 goroutine 取走并阻塞在下游，缓冲区容量为 2
+			_, _ = w.Write([]byte("0\n"))
+			waitFor(t, func() bool {
+				w.mu.Lock()
+				defer w.mu.Unlock()
+				return w.size == 0
+			})
+			for _, s := range []string{"1\n", "2\n", "3\n", "4\n"} {
+				if _, err := w.Write([]byte(s)); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+
+			close(gw.gate)
+			if err := w.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if got := gw.out.String(); got != tt.want {
+				t.Errorf("output = %q, want %q", got, tt.want)
+			}
+			if got := w.Dropped(); got != 2 {
+				t.Errorf("Dropped() = %d, want 2", got)
+			}
+		})
+	}
+}
+
+func TestNewAsyncWriter_InvalidOverflow(t *testing.T) {
+	for _, overflow := range []string{"dropoldest", "Block", "drop-newest", "discard"} {
+		t.Run(overflow, func(t *testing.T) {
+			w, err := NewAsyncWriter(&syncBuffer{}, AsyncOptions{Overflow: overflow})
+			if !errors.Is(err, ErrInvalidOverflow) {
+				t.Errorf("NewAsyncWriter() error = %v, want ErrInvalidOverflow", err)
+			}
+			if w != nil {
+				t.Error("NewAsyncWriter() should return nil on error")
+			}
+		})
+	}
+}
+
+func TestNew_InvalidAsyncOverflow(t *testing.T) {
+	_, err := New(Options{Output: OutputStdout, Async: &AsyncOptions{Overflow: "dropoldest"}})
+	if !errors.Is(err, ErrInvalidOverflow) {
+		t.Errorf("New() error = %v, want ErrInvalidOverflow", err)
+	}
+}
+
+func TestAsyncWriter_CloseTimeout(t *testing.T) {
+	gw := &gateWriter{gate: make(chan struct{})}
+	defer close(gw.gate)
+
+	w := newAsyncWriter(t, gw, AsyncOptions{CloseTimeout: 10 * time.Millisecond})
+	_, _ = w.Write([]byte("stuck\n"))
+
+	if err := w.Close(); !errors.Is(err, ErrFlushTimeout) {
+		t.Errorf("Close() error = %v, want ErrFlushTimeout", err)
+	}
+}
+
+func TestAsyncCleanup_TimeoutDefersClose(t *testing.T) {
+	gw := &gateWriter{gate: make(chan struct{})}
+	w := newAsyncWriter(t, gw, AsyncOptions{CloseTimeout: 10 * time.Millisecond})
+	_, _ = w.Write([]byte("stuck\n"))
+
+	var closed atomic.Bool
+	cleanup := asyncCleanup(w, func() error {
+		closed.Store(true)
+		return nil
+	})
+
+	if err := cleanup(); !errors.Is(err, ErrFlushTimeout) {
+		t.Fatalf("cleanup() error = %v, want ErrFlushTimeout", err)
+	}
+	// 后台 goroutine 仍在写入，底层输出不能被关闭
+	if closed.Load() {
+		t.Fatal("output closed while the writer goroutine is still writing")
+	}
+
+	close(gw.gate)
+	waitFor(t, closed.Load)
+	if got := gw.out.String(); got != "stuck\n" {
+		t.Errorf("output = %q, want the pending record", got)
+	}
+}
+
+func TestLogger_AsyncStats(t *testing.T) {
+	nop := NewNop()
+	if got := nop.AsyncStats(); got != (AsyncStats{}) {
+		t.Errorf("AsyncStats() = %+v, want zero for synchronous logger", got)
+	}
+
+	gw := &gateWriter{gate: make(chan struct{})}
+	w := newAsyncWriter(t, gw, AsyncOptions{BufferSize: 1, Overflow: OverflowDropNewest})
+	l := NewWithCleanup(slog.NewTextHandler(w, nil), asyncCleanup(w, func() error { return nil }))
+	l.async = w
+
+	// 第一条被后台 goroutine 取走并阻塞，第二条占满缓冲区，之后的被丢弃
+	l.Info("first")
+	waitFor(t, func() bool { return w.Buffered() == 0 })
+	for range 3 {
+		l.Info("more")
+	}
+	if got, want := l.AsyncStats(), (AsyncStats{Dropped: 2, Buffered: 1}); got != want {
+		t.Errorf("AsyncStats() = %+v, want %+v", got, want)
+	}
+
+	close(gw.gate)
+	if err := l.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+}
+
+func TestAsyncWriter_WriteError(t *testing.T) {
+	w := newAsyncWriter(t, errWriter{}, AsyncOptions{})
+	_, _ = w.Write([]byte("line\n"))
+
+	if err := w.Close(); !errors.Is(err, errWrite) {
+		t.Errorf("Close() error = %v, want downstream write error", err)
+	}
+}
+
+func TestNew_WithAsync(t *testing.T) {
+	logFile := t.TempDir() + "/app.log"
+
+	logger, err := New(Options{
+		Level:  LevelInfo,
+		Format: FormatJSON,
+		Output: logFile,
+		Async:  &AsyncOptions{BufferSize: 16},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	for i := range 50 {
+		logger.Info("async message", "i", i)
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if got := strings.Count(readFile(t, logFile), "async message"); got != 50 {
+		t.Errorf("written records = %d, want 50", got)
+	}
+}
+
+// waitFor 轮询等待条件成立
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("condition not met within 1s")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,268 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"golang.org/x/term"
+)
+
+// DefaultConsoleTimeFormat 控制台默认时间格式
+const DefaultConsoleTimeFormat = "15:04:05.000"
+
+// ANSI 颜色
+const (
+	colorReset  = "\033[0m"
+	colorDim    = "\033[2m"
+	colorRed    = "\033[31m"
+	colorGreen  = "\033[32m"
+	colorYellow = "\033[33m"
+	colorBlue   = "\033[34m"
+	colorCyan   = "\033[36m"
+)
+
+// ConsoleHandlerOptions 控制台 Handler 配置
+type ConsoleHandlerOptions struct {
+	Level      slog.Leveler // 最低级别，默认 info
+	AddSource  bool         // 是否输出调用位置（相对工作目录）
+	NoColor    bool         // 强制关闭颜色；否则按 NO_COLOR、TERM=dumb、是否终端自动判断
+	TimeFormat string       // 时间格式，默认 DefaultConsoleTimeFormat
+}
+
+// ConsoleHandler 面向本地开发的彩色控制台 Handler
+//
+// 每条记录输出为一行：时间、对齐的级别、消息、属性、调用位置。
+// error 类型的属性以及包含换行的字符串（如堆栈）在主行之后缩进多行输出。
+type ConsoleHandler struct {
+	opts   ConsoleHandlerOptions
+	color  bool
+	cwd    string
+	prefix string      // WithGroup 累积的分组前缀
+	attrs  []slog.Attr // WithAttrs 预置的属性（key 已带分组前缀）
+	mu     *sync.Mutex
+	w      io.Writer
+}
+
+var _ slog.Handler = (*ConsoleHandler)(nil)
+
+// NewConsoleHandler 创建控制台 Handler
+func NewConsoleHandler(w io.Writer, opts *ConsoleHandlerOptions) *ConsoleHandler {
+	var o ConsoleHandlerOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Level == nil {
+		o.Level = slog.LevelInfo
+	}
+	if o.TimeFormat == "" {
+		o.TimeFormat = DefaultConsoleTimeFormat
+	}
+
+	cwd, _ := os.Getwd()
+	return &ConsoleHandler{
+		opts:  o,
+		color: !o.NoColor && supportsColor(w),
+		cwd:   cwd,
+		mu:    &sync.Mutex{},
+		w:     w,
+	}
+}
+
+// Enabled 实现 slog.Handler
+func (h *ConsoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.opts.Level.Level()
+}
+
+// Handle 实现 slog.Handler
+func (h *ConsoleHandler) Handle(_ context.Context, r slog.Record) error {
+	var line, blocks bytes.Buffer
+
+	if !r.Time.IsZero() {
+		h.paint(&line, colorDim, r.Time.Format(h.opts.TimeFormat))
+		line.WriteByte(' ')
+	}
+	h.paint(&line, levelColor(r.Level), fmt.Sprintf("%-5s", r.Level.String()))
+	line.WriteByte(' ')
+	line.WriteString(r.Message)
+
+	for _, a := range h.attrs {
+		h.appendAttr(&line, &blocks, "", a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		h.appendAttr(&line, &blocks, h.prefix, a)
+		return true
+	})
+
+	if h.opts.AddSource && r.PC != 0 {
+		line.WriteByte(' ')
+		h.paint(&line, colorDim, h.source(r.PC))
+	}
+	line.WriteByte('\n')
+	line.Write(blocks.Bytes())
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(line.Bytes())
+	return err
+}
+
+// WithAttrs 实现 slog.Handler
+func (h *ConsoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	clone.attrs = append(clone.attrs, h.attrs...)
+	for _, a := range attrs {
+		a.Key = h.prefix + a.Key
+		clone.attrs = append(clone.attrs, a)
+	}
+	return &clone
+}
+
+// WithGroup 实现 slog.Handler
+func (h *ConsoleHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+// appendAttr 输出属性：普通值追加到主行，error 与多行文本写入 blocks
+func (h *ConsoleHandler) appendAttr(line, blocks *bytes.Buffer, prefix string, a slog.Attr) {
+	if ev, ok := a.Value.Any().(errorValue); ok && a.Value.Kind() == slog.KindLogValuer {
+		h.appendBlock(blocks, prefix+a.Key, ev.text())
+		return
+	}
+
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			h.appendAttr(line, blocks, prefix, ga)
+		}
+		return
+	}
+
+	key := prefix + a.Key
+	if err, ok := a.Value.Any().(error); ok && a.Value.Kind() == slog.KindAny {
+		h.appendBlock(blocks, key, err.Error())
+		return
+	}
+	if a.Value.Kind() == slog.KindString && strings.Contains(a.Value.String(), "\n") {
+		h.appendBlock(blocks, key, a.Value.String())
+		return
+	}
+
+	line.WriteByte(' ')
+	h.paint(line, colorCyan, key+"=")
+	line.WriteString(quoteIfNeeded(a.Value.String()))
+}
+
+// appendBlock 在主行之后缩进输出多行内容
+func (h *ConsoleHandler) appendBlock(blocks *bytes.Buffer, key, text string) {
+	blocks.WriteString("    ")
+	h.paint(blocks, colorRed, key+":")
+	lines := strings.Split(strings.TrimRight(text, "\n"), "\n")
+	if len(lines) == 1 {
+		blocks.WriteByte(' ')
+		blocks.WriteString(lines[0])
+		blocks.WriteByte('\n')
+		return
+	}
+	blocks.WriteByte('\n')
+	for _, l := range lines {
+		blocks.WriteString("        ")
+		blocks.WriteString(l)
+		blocks.WriteByte('\n')
+	}
+}
+
+// source 返回相对工作目录的调用位置
+func (h *ConsoleHandler) source(pc uintptr) string {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	file := frame.File
+	if h.cwd != "" {
+		if rel, err := filepath.Rel(h.cwd, file); err == nil && !strings.HasPrefix(rel, "..") {
+			file = rel
+		} else {
+			file = filepath.Join(filepath.Base(filepath.Dir(file)), filepath.Base(file))
+		}
+	}
+	return file + ":" + strconv.Itoa(frame.Line)
+}
+
+// paint 按需为文本着色
+func (h *ConsoleHandler) paint(buf *bytes.Buffer, color, text string) {
+	if !h.color {
+		buf.WriteString(text)
+		return
+	}
+	buf.WriteString(color)
+	buf.WriteString(text)
+	buf.WriteString(colorReset)
+}
+
+// levelColor 级别对应的颜色
+func levelColor(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return colorRed
+	case level >= slog.LevelWarn:
+		return colorYellow
+	case level >= slog.LevelInfo:
+		return colorGreen
+	default:
+		return colorBlue
+	}
+}
+
+// quoteIfNeeded 值为空或包含空白、引号、等号时加引号
+func quoteIfNeeded(s string) string {
+	if s == "" {
+		return `""`
+	}
+	for _, r := range s {
+		if unicode.IsSpace(r) || r == '"' || r == '=' || !unicode.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+	}
+	return s
+}
+
+// supportsColor 判断输出是否支持颜色，规则与 cli 启动横幅一致：
+// NO_COLOR 非空、TERM=dumb 或输出不是终端时关闭颜色
+func supportsColor(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" {
+		return false
+	}
+	if os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	f, ok := underlyingWriter(w).(*os.File)
+	return ok && term.IsTerminal(int(f.Fd())) //nolint:gosec // G115: fd fits in int
+}
+
+// underlyingWriter 返回 AsyncWriter 包装的下游 writer，用于按真实输出判断终端
+func underlyingWriter(w io.Writer) io.Writer {
+	if a, ok := w.(*AsyncWriter); ok {
+		return a.w
+	}
+	return w
+}
@@ -0,0 +1,163 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConsoleHandler_Format(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewConsoleHandler(&buf, &ConsoleHandlerOptions{Level: slog.LevelDebug})
+
+	r := slog.NewRecord(time.Date(2025, 1, 2, 3, 4, 5, 6_000_000, time.UTC), slog.LevelInfo, "server started", 0)
+	r.AddAttrs(slog.Int("port", 8080), slog.String("name", "my app"))
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	want := "03:04:05.006 INFO  server started port=8080 name=\"my app\"\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestConsoleHandler_LevelAlignment(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewConsoleHandler(&buf, &ConsoleHandlerOptions{Level: slog.LevelDebug}))
+
+	logger.Debug("d")
+	logger.Info("i")
+	logger.Warn("w")
+	logger.Error("e")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %d: %q", len(lines), buf.String())
+	}
+	// 消息列对齐
+	col := strings.Index(lines[0], " d")
+	for i, msg := range []string{" d", " i", " w", " e"} {
+		if got := strings.Index(lines[i], msg); got != col {
+			t.Errorf("line %d message column = %d, want %d: %q", i, got, col, lines[i])
+		}
+	}
+}
+
+func TestConsoleHandler_GroupsAndAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewConsoleHandler(&buf, nil))
+
+	logger.With("svc", "api").WithGroup("req").With("id", 7).Info("handled",
+		slog.Group("resp", slog.Int("status", 200)),
+	)
+
+	out := buf.String()
+	for _, want := range []string{"svc=api", "req.id=7", "req.resp.status=200"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output should contain %q, got: %s", want, out)
+		}
+	}
+}
+
+func TestConsoleHandler_MultilineBlocks(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewConsoleHandler(&buf, nil))
+
+	err := errors.Join(errors.New("dial tcp: refused"), errors.New("retry exhausted"))
+	logger.Error("request failed", "err", err, "stack", "main.main()\n\tmain.go:10", "code", 500)
+
+	out := buf.String()
+	lines := strings.Split(strings.TrimRight(out, "\n"), "\n")
+	if !strings.HasSuffix(lines[0], "request failed code=500") {
+		t.Errorf("main line should keep inline attrs only, got: %q", lines[0])
+	}
+	want := []string{
+		"    err:",
+		"        dial tcp: refused",
+		"        retry exhausted",
+		"    stack:",
+		"        main.main()",
+		"        \tmain.go:10",
+	}
+	if strings.Join(lines[1:], "\n") != strings.Join(want, "\n") {
+		t.Errorf("blocks = %q, want %q", lines[1:], want)
+	}
+}
+
+func TestConsoleHandler_Source(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewConsoleHandler(&buf, &ConsoleHandlerOptions{AddSource: true}))
+
+	logger.Info("with source")
+
+	if !strings.Contains(buf.String(), " console_test.go:") {
+		t.Errorf("source should be relative to working directory, got: %s", buf.String())
+	}
+}
+
+func TestConsoleHandler_Color(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewConsoleHandler(&buf, nil)
+	if h.color {
+		t.Error("color should be disabled for non-terminal writers")
+	}
+
+	h.color = true
+	slog.New(h).Warn("colored")
+	if !strings.Contains(buf.String(), colorYellow+"WARN ") {
+		t.Errorf("warn level should be yellow, got: %q", buf.String())
+	}
+}
+
+func TestSupportsColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	if supportsColor(os.Stdout) {
+		t.Error("supportsColor() should be false when NO_COLOR is set")
+	}
+
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "dumb")
+	if supportsColor(os.Stdout) {
+		t.Error("supportsColor() should be false when TERM=dumb")
+	}
+
+	t.Setenv("TERM", "xterm")
+	if supportsColor(&bytes.Buffer{}) {
+		t.Error("supportsColor() should be false for non-file writers")
+	}
+}
+
+func TestUnderlyingWriter(t *testing.T) {
+	// 异步模式按被包装的文件判断是否为终端
+	aw := newAsyncWriter(t, os.Stdout, AsyncOptions{})
+	defer func() { _ = aw.Close() }()
+	if got := underlyingWriter(aw); got != os.Stdout {
+		t.Errorf("underlyingWriter(AsyncWriter) = %v, want os.Stdout", got)
+	}
+
+	var buf bytes.Buffer
+	if got := underlyingWriter(&buf); got != &buf {
+		t.Errorf("underlyingWriter() = %v, want the writer itself", got)
+	}
+}
+
+func TestNew_TextFormat(t *testing.T) {
+	logFile := t.TempDir() + "/app.log"
+
+	logger, err := New(Options{Level: LevelInfo, Format: FormatText, Output: logFile})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	logger.Info("logfmt", "key", "value")
+	_ = logger.Close()
+
+	if data := readFile(t, logFile); !strings.Contains(data, `msg=logfmt key=value`) {
+		t.Errorf("text format should emit logfmt, got: %s", data)
+	}
+}
@@ -0,0 +1,314 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 去重默认值
+const (
+	DefaultDedupWindow  = 10 * time.Second // 默认去重窗口
+	DefaultDedupMaxKeys = 10000            // 默认同时跟踪的记录种类上限
+)
+
+// DedupOptions 重复日志折叠配置
+//
+// 同一级别、同一消息且 Keys 指定属性值相同的记录视为重复：
+// 窗口内的第一条立即输出，其余记录仅计数，窗口结束时输出一条汇总，
+// 如 "connection refused (repeated 384 times in 10s)"。
+//
+// 窗口为固定窗口，从某类记录首次出现时开始计时，期间穿插的其他记录不会结束窗口：
+// A、B、A 中的第二个 A 同样被折叠。同时跟踪的记录种类达到 MaxKeys 后，
+// 新种类的记录不再折叠，直接写入下游，直到有窗口结束。
+type DedupOptions struct {
+	Window  time.Duration `mapstructure:"window" yaml:"window"`     // 去重窗口，默认 10s
+	Keys    []string      `mapstructure:"keys" yaml:"keys"`         // 参与比较的属性 key（分组以 . 连接），空表示仅比较级别与消息
+	MaxKeys int           `mapstructure:"max_keys" yaml:"max_keys"` // 同时跟踪的记录种类上限，默认 10000
+}
+
+// DedupHandler 重复日志折叠中间件
+//
+// 汇总记录沿用最后一条重复记录的级别、属性与调用位置，
+// 消息追加重复次数与时间跨度，并附加 repeated 属性。
+// 窗口结束时由后台 goroutine 输出汇总，Close 停止该 goroutine 并输出所有未结束窗口的汇总，
+// 因此不再使用时必须调用 Close。
+type DedupHandler struct {
+	next   slog.Handler
+	state  *dedupState
+	prefix string                // WithGroup 累积的分组前缀
+	bound  map[string]slog.Value // WithAttrs 附加的参与比较的属性
+}
+
+var (
+	_ slog.Handler = (*DedupHandler)(nil)
+	_ io.Closer    = (*DedupHandler)(nil)
+)
+
+// dedupState 在 WithAttrs/WithGroup 派生的 Handler 之间共享
+type dedupState struct {
+	window  time.Duration
+	maxKeys int
+	keys    map[string]struct{}
+	order   []string
+
+	mu      sync.Mutex
+	entries map[string]*dedupEntry
+	queue   []*dedupEntry // 按窗口结束时间排序，窗口长度固定，因此即按创建顺序
+	closed  bool
+
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// dedupEntry 一个窗口内某类记录的折叠状态
+type dedupEntry struct {
+	key   string
+	end   time.Time // 窗口结束时间
+	first time.Time // 首条记录的时间
+
+	count   int             // 被折叠的重复次数
+	last    slog.Record     // 最后一条重复记录
+	ctx     context.Context // 最后一条重复记录的 context
+	handler slog.Handler    // 最后一条重复记录所属的下游 Handler
+}
+
+// NewDedupHandler 创建重复日志折叠中间件
+func NewDedupHandler(next slog.Handler, opts DedupOptions) *DedupHandler {
+	window := opts.Window
+	if window <= 0 {
+		window = DefaultDedupWindow
+	}
+	maxKeys := opts.MaxKeys
+	if maxKeys <= 0 {
+		maxKeys = DefaultDedupMaxKeys
+	}
+
+	keys := make(map[string]struct{}, len(opts.Keys))
+	order := make([]string, 0, len(opts.Keys))
+	for _, k := range opts.Keys {
+		if _, ok := keys[k]; ok || k == "" {
+			continue
+		}
+		keys[k] = struct{}{}
+		order = append(order, k)
+	}
+
+	state := &dedupState{
+		window:  window,
+		maxKeys: maxKeys,
+		keys:    keys,
+		order:   order,
+		entries: make(map[string]*dedupEntry),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go state.run()
+
+	return &DedupHandler{next: next, state: state}
+}
+
+// Enabled 实现 slog.Handler
+func (h *DedupHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle 实现 slog.Handler
+func (h *DedupHandler) Handle(ctx context.Context, r slog.Record) error {
+	s := h.state
+	key := h.key(r)
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return h.next.Handle(ctx, r)
+	}
+	if e, ok := s.entries[key]; ok {
+		e.count++
+		e.last = r.Clone()
+		e.ctx = ctx
+		e.handler = h.next
+		s.mu.Unlock()
+		return nil
+	}
+	if len(s.entries) < s.maxKeys {
+		e := &dedupEntry{key: key, end: time.Now().Add(s.window), first: recordTime(r)}
+		s.entries[key] = e
+		s.queue = append(s.queue, e)
+	}
+	s.mu.Unlock()
+
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs 实现 slog.Handler
+func (h *DedupHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	bound := h.bound
+	if len(h.state.keys) > 0 {
+		bound = make(map[string]slog.Value, len(h.bound))
+		for k, v := range h.bound {
+			bound[k] = v
+		}
+		for _, a := range attrs {
+			h.state.collect(bound, h.prefix, a)
+		}
+	}
+	return &DedupHandler{next: h.next.WithAttrs(attrs), state: h.state, prefix: h.prefix, bound: bound}
+}
+
+// WithGroup 实现 slog.Handler
+func (h *DedupHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &DedupHandler{next: h.next.WithGroup(name), state: h.state, prefix: h.prefix + name + ".", bound: h.bound}
+}
+
+// Close 输出所有未结束窗口的汇总，并关闭下游 Handler
+// 之后到达的记录不再折叠，直接写入下游
+func (h *DedupHandler) Close() error {
+	s := h.state
+	s.stopOnce.Do(func() {
+		close(s.stop)
+		<-s.done
+
+		s.mu.Lock()
+		s.closed = true
+		entries := s.queue
+		s.queue = nil
+		clear(s.entries)
+		s.mu.Unlock()
+
+		for _, e := range entries {
+			e.flush()
+		}
+	})
+	return closeHandler(h.next)
+}
+
+// key 返回记录的去重 key：级别 + 消息 + 指定属性值
+func (h *DedupHandler) key(r slog.Record) string {
+	var b strings.Builder
+	b.WriteString(r.Level.String())
+	b.WriteByte(0)
+	b.WriteString(r.Message)
+
+	s := h.state
+	if len(s.keys) == 0 {
+		return b.String()
+	}
+
+	values := make(map[string]slog.Value, len(s.keys))
+	for k, v := range h.bound {
+		values[k] = v
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		s.collect(values, h.prefix, a)
+		return true
+	})
+	for _, k := range s.order {
+		b.WriteByte(0)
+		if v, ok := values[k]; ok {
+			b.WriteString(v.String())
+		}
+	}
+	return b.String()
+}
+
+// collect 展开分组，收集参与比较的属性值
+func (s *dedupState) collect(dst map[string]slog.Value, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			s.collect(dst, prefix, ga)
+		}
+		return
+	}
+	if _, ok := s.keys[prefix+a.Key]; ok {
+		dst[prefix+a.Key] = a.Value
+	}
+}
+
+// run 在窗口结束时输出汇总，直到 Close
+// 队列为空时休眠一个窗口长度，期间新建的窗口不会早于该时间结束
+func (s *dedupState) run() {
+	defer close(s.done)
+
+	timer := time.NewTimer(s.window)
+	defer timer.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-timer.C:
+			timer.Reset(s.expire(now))
+		}
+	}
+}
+
+// expire 移除并输出 now 之前结束的窗口，返回距下一个窗口结束的时间
+// 仅由 run 调用，Close 等待 run 退出后才处理剩余的窗口
+func (s *dedupState) expire(now time.Time) time.Duration {
+	s.mu.Lock()
+	n := 0
+	for n < len(s.queue) && !s.queue[n].end.After(now) {
+		n++
+	}
+	expired := make([]*dedupEntry, n)
+	copy(expired, s.queue[:n])
+	clear(s.queue[:n])
+	s.queue = s.queue[n:]
+	for _, e := range expired {
+		delete(s.entries, e.key)
+	}
+	next := s.window
+	if len(s.queue) > 0 {
+		next = s.queue[0].end.Sub(now)
+	}
+	s.mu.Unlock()
+
+	for _, e := range expired {
+		e.flush()
+	}
+	return next
+}
+
+// flush 输出折叠汇总，没有重复记录时不输出
+func (e *dedupEntry) flush() {
+	if e.count == 0 {
+		return
+	}
+	ctx := e.ctx
+	if !e.handler.Enabled(ctx, e.last.Level) {
+		return
+	}
+
+	span := recordTime(e.last).Sub(e.first).Round(time.Millisecond)
+	r := slog.NewRecord(time.Now(), e.last.Level,
+		fmt.Sprintf("%s (repeated %d times in %s)", e.last.Message, e.count, span), e.last.PC)
+	e.last.Attrs(func(a slog.Attr) bool {
+		r.AddAttrs(a)
+		return true
+	})
+	r.AddAttrs(slog.Int("repeated", e.count))
+	_ = e.handler.Handle(ctx, r)
+}
+
+// recordTime 返回记录时间，未设置时使用当前时间
+func recordTime(r slog.Record) time.Time {
+	if r.Time.IsZero() {
+		return time.Now()
+	}
+	return r.Time
+}
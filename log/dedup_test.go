@@ -0,0 +1,258 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDedupHandler_CollapseOnClose(t *testing.T) {
+	var buf syncBuffer
+	h := NewDedupHandler(slog.NewTextHandler(&buf, nil), DedupOptions{Window: time.Hour})
+	logger := slog.New(h)
+
+	for range 385 {
+		logger.Error("connection refused", "addr", "10.0.0.1:5432")
+	}
+	if got := strings.Count(buf.String(), "\n"); got != 1 {
+		t.Fatalf("written records before Close = %d, want 1", got)
+	}
+
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	out := buf.String()
+	if got := strings.Count(out, "\n"); got != 2 {
+		t.Errorf("written records after Close = %d, want 2", got)
+	}
+	for _, want := range []string{`msg="connection refused (repeated 384 times in `, "addr=10.0.0.1:5432 repeated=384"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("summary should contain %q, got: %s", want, out)
+		}
+	}
+}
+
+func TestDedupHandler_NoRepeatNoSummary(t *testing.T) {
+	var buf syncBuffer
+	h := NewDedupHandler(slog.NewTextHandler(&buf, nil), DedupOptions{Window: time.Hour})
+	logger := slog.New(h)
+
+	logger.Info("started")
+	logger.Warn("started") // 级别不同，不视为重复
+	logger.Info("ready")
+	_ = h.Close()
+
+	if got := strings.Count(buf.String(), "\n"); got != 3 {
+		t.Errorf("written records = %d, want 3", got)
+	}
+	if strings.Contains(buf.String(), "repeated") {
+		t.Errorf("no summary expected, got: %s", buf.String())
+	}
+}
+
+func TestDedupHandler_Keys(t *testing.T) {
+	var buf syncBuffer
+	h := NewDedupHandler(slog.NewTextHandler(&buf, nil), DedupOptions{
+		Window: time.Hour,
+		Keys:   []string{"host", "req.route"},
+	})
+	logger := slog.New(h)
+
+	// 不参与比较的属性（attempt）不同仍视为重复
+	for i := range 3 {
+		logger.Warn("retry", "host", "a", "attempt", i)
+	}
+	logger.Warn("retry", "host", "b", "attempt", 0)
+
+	// WithAttrs 附加的属性与分组内的属性同样参与比较
+	api := logger.With("host", "c").WithGroup("req")
+	api.Warn("retry", "route", "/users")
+	api.Warn("retry", "route", "/users")
+	api.Warn("retry", "route", "/orders")
+
+	if got := strings.Count(buf.String(), "\n"); got != 4 {
+		t.Errorf("written records before Close = %d, want 4: %s", got, buf.String())
+	}
+
+	_ = h.Close()
+	out := buf.String()
+	for _, want := range []string{
+		`msg="retry (repeated 2 times in `,
+		`host=a attempt=2 repeated=2`,
+		`msg="retry (repeated 1 times in `,
+		`host=c req.route=/users req.repeated=1`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output should contain %q, got: %s", want, out)
+		}
+	}
+}
+
+func TestDedupHandler_WindowExpiry(t *testing.T) {
+	var buf syncBuffer
+	h := NewDedupHandler(slog.NewTextHandler(&buf, nil), DedupOptions{Window: 20 * time.Millisecond})
+	logger := slog.New(h)
+
+	for range 5 {
+		logger.Info("tick")
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(buf.String(), "repeated=4") {
+		if time.Now().After(deadline) {
+			t.Fatalf("summary not flushed at window end, got: %s", buf.String())
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// 新窗口的第一条立即输出
+	logger.Info("tick")
+	if got := strings.Count(buf.String(), "msg=tick\n"); got != 2 {
+		t.Errorf("records with msg=tick = %d, want 2: %s", got, buf.String())
+	}
+	_ = h.Close()
+}
+
+func TestDedupHandler_SpanFromRecordTime(t *testing.T) {
+	var buf syncBuffer
+	h := NewDedupHandler(slog.NewTextHandler(&buf, nil), DedupOptions{Window: time.Hour})
+
+	base := time.Now()
+	for i := range 4 {
+		r := slog.NewRecord(base.Add(time.Duration(i)*time.Second), slog.LevelInfo, "poll", 0)
+		_ = h.Handle(context.Background(), r)
+	}
+	_ = h.Close()
+
+	if !strings.Contains(buf.String(), `msg="poll (repeated 3 times in 3s)"`) {
+		t.Errorf("summary should report span of 3s, got: %s", buf.String())
+	}
+}
+
+func TestDedupHandler_FixedWindow(t *testing.T) {
+	var buf syncBuffer
+	h := NewDedupHandler(slog.NewTextHandler(&buf, nil), DedupOptions{Window: time.Hour})
+	logger := slog.New(h)
+
+	// 固定窗口：穿插的其他记录不会结束窗口，第二个 A 被折叠
+	logger.Info("A")
+	logger.Info("B")
+	logger.Info("A")
+	if got := strings.Count(buf.String(), "\n"); got != 2 {
+		t.Errorf("written records before Close = %d, want 2: %s", got, buf.String())
+	}
+	_ = h.Close()
+	if !strings.Contains(buf.String(), `msg="A (repeated 1 times in `) {
+		t.Errorf("summary for A expected, got: %s", buf.String())
+	}
+}
+
+func TestDedupHandler_MaxKeys(t *testing.T) {
+	var buf syncBuffer
+	h := NewDedupHandler(slog.NewTextHandler(&buf, nil), DedupOptions{Window: time.Hour, MaxKeys: 2})
+	logger := slog.New(h)
+
+	// 达到上限后新种类的记录不再跟踪，每条都直接输出
+	for range 3 {
+		for _, msg := range []string{"a", "b", "c"} {
+			logger.Info(msg)
+		}
+	}
+	if got := len(h.state.entries); got != 2 {
+		t.Errorf("tracked keys = %d, want 2", got)
+	}
+	if got := strings.Count(buf.String(), "msg=c\n"); got != 3 {
+		t.Errorf("records with msg=c = %d, want 3: %s", got, buf.String())
+	}
+	_ = h.Close()
+	for _, want := range []string{`msg="a (repeated 2 times in `, `msg="b (repeated 2 times in `} {
+		if !strings.Contains(buf.String(), want) {
+			t.Errorf("output should contain %q, got: %s", want, buf.String())
+		}
+	}
+}
+
+// ctxKey 用于验证汇总记录沿用最后一条重复记录的 context
+type ctxKey struct{}
+
+// ctxHandler 记录 Handle 收到的 context 中 ctxKey 的值
+type ctxHandler struct {
+	mu  sync.Mutex
+	got []any
+}
+
+func (h *ctxHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *ctxHandler) Handle(ctx context.Context, _ slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.got = append(h.got, ctx.Value(ctxKey{}))
+	return nil
+}
+
+func (h *ctxHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *ctxHandler) WithGroup(string) slog.Handler      { return h }
+
+func TestDedupHandler_SummaryContext(t *testing.T) {
+	next := &ctxHandler{}
+	h := NewDedupHandler(next, DedupOptions{Window: time.Hour})
+	logger := slog.New(h)
+
+	for _, v := range []string{"first", "second", "last"} {
+		logger.InfoContext(context.WithValue(context.Background(), ctxKey{}, v), "poll")
+	}
+	_ = h.Close()
+
+	if len(next.got) != 2 || next.got[0] != "first" || next.got[1] != "last" {
+		t.Errorf("context values = %v, want [first last]", next.got)
+	}
+}
+
+func TestDedupHandler_AfterClose(t *testing.T) {
+	var buf syncBuffer
+	h := NewDedupHandler(slog.NewTextHandler(&buf, nil), DedupOptions{Window: time.Hour})
+	_ = h.Close()
+
+	logger := slog.New(h)
+	logger.Info("late")
+	logger.Info("late")
+	if got := strings.Count(buf.String(), "msg=late"); got != 2 {
+		t.Errorf("records after Close = %d, want 2 (pass-through)", got)
+	}
+}
+
+func TestNew_WithDedup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger, err := New(Options{
+		Level:  LevelInfo,
+		Format: FormatJSON,
+		Output: path,
+		Dedup:  &DedupOptions{Window: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for range 10 {
+		logger.Error("disk full")
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(data), "\n"); got != 2 {
+		t.Errorf("written records = %d, want 2", got)
+	}
+	if !strings.Contains(string(data), `"repeated":9`) {
+		t.Errorf("Close() should flush dedup summary, got: %s", data)
+	}
+}
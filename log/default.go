@@ -0,0 +1,208 @@
+package log
+
+import (
+	"context"
+	"io"
+	stdlog "log"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+// ========== 全局默认 Logger ==========
+
+// defaultEntry 当前默认 Logger 及其在途记录的读写锁
+// 处理记录时持有读锁，替换后取写锁等待在途记录写完再关闭旧 Logger
+type defaultEntry struct {
+	logger  *Logger
+	mu      sync.RWMutex
+	retired bool
+}
+
+var (
+	defaultLogger atomic.Pointer[defaultEntry]
+
+	// installed 是否已接管 slog.Default 与标准库 log
+	installed atomic.Bool
+	// stdDefault 接管前的 slog.Default 与标准库 log 配置，ResetDefault 时恢复
+	stdDefault atomic.Pointer[stdState]
+)
+
+// stdState 接管前的 slog.Default 与标准库 log 的输出、flags
+// slog.SetDefault 会把标准库 log 重定向到新的 Handler，恢复 slog.Default 不会撤销该重定向
+type stdState struct {
+	logger *slog.Logger
+	writer io.Writer
+	flags  int
+}
+
+// SetDefault 设置全局默认 Logger，并将 slog.Default() 与标准库 log 的输出重定向到它
+//
+// 可在运行期多次调用（如配置热加载），之后的记录立即写入新的 Logger；
+// 旧 Logger 不会被关闭，需要关闭时使用 ReplaceDefault。l 为 nil 时等同于 ResetDefault
+func SetDefault(l *Logger) {
+	swapDefault(l)
+}
+
+// ReplaceDefault 替换全局默认 Logger，等待旧 Logger 的在途记录写完后将其关闭
+// 返回旧 Logger 的关闭错误，未设置过默认 Logger 时返回 nil；
+// l 为 nil 时恢复接管前的 slog.Default 与标准库 log，并关闭旧 Logger
+func ReplaceDefault(l *Logger) error {
+	old := swapDefault(l)
+	if old == nil {
+		return nil
+	}
+	old.mu.Lock()
+	old.retired = true
+	old.mu.Unlock()
+	return old.logger.Close()
+}
+
+// swapDefault 替换默认 Logger 并返回旧的 entry，l 为 nil 时恢复接管前的状态
+func swapDefault(l *Logger) *defaultEntry {
+	if l == nil {
+		return resetDefault()
+	}
+	old := defaultLogger.Swap(&defaultEntry{logger: l})
+	if installed.CompareAndSwap(false, true) {
+		stdDefault.Store(&stdState{
+			logger: slog.Default(),
+			writer: stdlog.Writer(),
+			flags:  stdlog.Flags(),
+		})
+		slog.SetDefault(slog.New(&swapHandler{}))
+	}
+	return old
+}
+
+// Default 返回全局默认 Logger
+// 未调用 SetDefault 时返回 nil。跨配置热加载长期持有时，
+// 请使用 slog.Default()，它总是写入当前的默认 Logger
+func Default() *Logger {
+	if e := defaultLogger.Load(); e != nil {
+		return e.logger
+	}
+	return nil
+}
+
+// MustDefault 返回全局默认 Logger
+// 未调用 SetDefault 时 panic
+func MustDefault() *Logger {
+	l := Default()
+	if l == nil {
+		panic(ErrNotInitialized)
+	}
+	return l
+}
+
+// ResetDefault 清除全局默认 Logger，并恢复接管前的 slog.Default 与标准库 log
+// 不会关闭当前的默认 Logger，主要用于测试
+func ResetDefault() {
+	resetDefault()
+}
+
+// resetDefault 清除默认 Logger 并返回旧的 entry
+// 先恢复 slog.Default，再恢复标准库 log 的输出与 flags
+func resetDefault() *defaultEntry {
+	old := defaultLogger.Swap(nil)
+	if installed.CompareAndSwap(true, false) {
+		std := stdDefault.Load()
+		slog.SetDefault(std.logger)
+		stdlog.SetOutput(std.writer)
+		stdlog.SetFlags(std.flags)
+	}
+	return old
+}
+
+// swapHandler slog.Default() 使用的 Handler，每条记录转发给当前的默认 Logger
+// WithAttrs/WithGroup 的结果同样跟随默认 Logger 的替换
+type swapHandler struct {
+	goas  []groupOrAttrs
+	cache atomic.Pointer[swapCache]
+}
+
+// swapCache 针对某个默认 Logger 预先应用 goas 的 Handler
+type swapCache struct {
+	entry   *defaultEntry
+	handler slog.Handler
+}
+
+var _ slog.Handler = (*swapHandler)(nil)
+
+// Enabled 实现 slog.Handler
+func (h *swapHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	e := defaultLogger.Load()
+	if e == nil {
+		return false
+	}
+	return h.handler(e).Enabled(ctx, level)
+}
+
+// Handle 实现 slog.Handler
+func (h *swapHandler) Handle(ctx context.Context, r slog.Record) error {
+	for {
+		e := defaultLogger.Load()
+		if e == nil {
+			// ResetDefault 恢复 slog.Default 前的瞬间，直接丢弃；
+			// 不能转发给 stdDefault，其内置 Handler 经标准库 log 会回到本 Handler
+			return nil
+		}
+		e.mu.RLock()
+		if e.retired {
+			// 已被替换并关闭，改写入新的默认 Logger
+			e.mu.RUnlock()
+			continue
+		}
+		err := h.handler(e).Handle(ctx, r)
+		e.mu.RUnlock()
+		return err
+	}
+}
+
+// WithAttrs 实现 slog.Handler
+func (h *swapHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.with(groupOrAttrs{attrs: attrs})
+}
+
+// WithGroup 实现 slog.Handler
+func (h *swapHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(groupOrAttrs{group: name})
+}
+
+func (h *swapHandler) with(goa groupOrAttrs) *swapHandler {
+	goas := make([]groupOrAttrs, len(h.goas)+1)
+	copy(goas, h.goas)
+	goas[len(h.goas)] = goa
+	return &swapHandler{goas: goas}
+}
+
+// handler 返回应用了 goas 的当前默认 Logger 的 Handler
+func (h *swapHandler) handler(e *defaultEntry) slog.Handler {
+	if len(h.goas) == 0 {
+		return e.logger.Handler()
+	}
+	if c := h.cache.Load(); c != nil && c.entry == e {
+		return c.handler
+	}
+	handler := h.apply(e.logger.Handler())
+	h.cache.Store(&swapCache{entry: e, handler: handler})
+	return handler
+}
+
+// apply 依次应用 WithGroup/WithAttrs
+func (h *swapHandler) apply(handler slog.Handler) slog.Handler {
+	for _, goa := range h.goas {
+		if goa.group != "" {
+			handler = handler.WithGroup(goa.group)
+		} else {
+			handler = handler.WithAttrs(goa.attrs)
+		}
+	}
+	return handler
+}
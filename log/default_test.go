@@ -0,0 +1,285 @@
+package log
+
+import (
+	"context"
+	"errors"
+	stdlog "log"
+	"log/slog"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newBufferLogger 创建写入内存缓冲区的 Logger
+func newBufferLogger(buf *syncBuffer, cleanup func() error) *Logger {
+	return NewWithCleanup(slog.NewTextHandler(buf, &slog.HandlerOptions{Level: slog.LevelDebug}), cleanup)
+}
+
+func TestDefault_NotInitialized(t *testing.T) {
+	ResetDefault()
+
+	if got := Default(); got != nil {
+		t.Errorf("Default() = %v, want nil", got)
+	}
+
+	defer func() {
+		r := recover()
+		err, ok := r.(error)
+		if !ok || !errors.Is(err, ErrNotInitialized) {
+			t.Errorf("MustDefault() panic = %v, want %v", r, ErrNotInitialized)
+		}
+	}()
+	MustDefault()
+}
+
+func TestSetDefault_RedirectsSlogAndStdlog(t *testing.T) {
+	t.Cleanup(ResetDefault)
+
+	var buf syncBuffer
+	logger := newBufferLogger(&buf, nil)
+	SetDefault(logger)
+
+	if Default() != logger || MustDefault() != logger {
+		t.Error("Default() should return the logger passed to SetDefault")
+	}
+
+	slog.Info("via slog", "k", "v")
+	stdlog.Print("via stdlib")
+
+	out := buf.String()
+	for _, want := range []string{"msg=\"via slog\" k=v", "msg=\"via stdlib\""} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output should contain %q, got: %s", want, out)
+		}
+	}
+}
+
+func TestSetDefault_SwapKeepsDerivedLoggers(t *testing.T) {
+	t.Cleanup(ResetDefault)
+
+	var first, second syncBuffer
+	SetDefault(newBufferLogger(&first, nil))
+
+	// 替换前派生的 Logger 在替换后写入新的默认 Logger
+	derived := slog.Default().With("svc", "api").WithGroup("req")
+	derived.Info("before", "id", 1)
+
+	SetDefault(newBufferLogger(&second, nil))
+	derived.Info("after", "id", 2)
+
+	if !strings.Contains(first.String(), "svc=api req.id=1") {
+		t.Errorf("first logger output = %q, want derived attrs", first.String())
+	}
+	if strings.Contains(first.String(), "after") {
+		t.Errorf("first logger should not receive records after swap, got: %s", first.String())
+	}
+	if !strings.Contains(second.String(), "msg=after svc=api req.id=2") {
+		t.Errorf("second logger output = %q, want derived attrs", second.String())
+	}
+}
+
+func TestResetDefault_RestoresSlogDefault(t *testing.T) {
+	original := slog.Default()
+
+	var buf syncBuffer
+	SetDefault(newBufferLogger(&buf, nil))
+	if slog.Default() == original {
+		t.Fatal("SetDefault() should replace slog.Default()")
+	}
+
+	ResetDefault()
+	if slog.Default() != original {
+		t.Error("ResetDefault() should restore the previous slog.Default()")
+	}
+	if Default() != nil {
+		t.Error("Default() should be nil after ResetDefault()")
+	}
+}
+
+func TestResetDefault_RestoresStdlogOutput(t *testing.T) {
+	prevWriter, prevFlags := stdlog.Writer(), stdlog.Flags()
+	t.Cleanup(func() {
+		stdlog.SetOutput(prevWriter)
+		stdlog.SetFlags(prevFlags)
+	})
+
+	var std syncBuffer
+	stdlog.SetOutput(&std)
+	stdlog.SetFlags(stdlog.Lmsgprefix)
+
+	var buf syncBuffer
+	SetDefault(newBufferLogger(&buf, nil))
+	ResetDefault()
+
+	if stdlog.Writer() != &std || stdlog.Flags() != stdlog.Lmsgprefix {
+		t.Error("ResetDefault() should restore the stdlib log output and flags")
+	}
+
+	// 恢复后 slog 的默认 Handler 经标准库 log 写出，两者都不应丢失
+	stdlog.Print("via stdlib")
+	slog.Info("via slog")
+	for _, want := range []string{"via stdlib", "INFO via slog"} {
+		if !strings.Contains(std.String(), want) {
+			t.Errorf("output after reset should contain %q, got: %q", want, std.String())
+		}
+	}
+	if buf.String() != "" {
+		t.Errorf("reset logger should not receive records, got: %s", buf.String())
+	}
+}
+
+func TestSetDefault_NilResets(t *testing.T) {
+	original := slog.Default()
+
+	tests := []struct {
+		name string
+		set  func() error
+	}{
+		{"SetDefault", func() error { SetDefault(nil); return nil }},
+		{"ReplaceDefault", func() error { return ReplaceDefault(nil) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(ResetDefault)
+
+			var closed atomic.Bool
+			var buf syncBuffer
+			SetDefault(newBufferLogger(&buf, func() error {
+				closed.Store(true)
+				return nil
+			}))
+
+			if err := tt.set(); err != nil {
+				t.Fatalf("%s(nil) error = %v", tt.name, err)
+			}
+			if Default() != nil || slog.Default() != original {
+				t.Errorf("%s(nil) should reset the default logger", tt.name)
+			}
+			if got, want := closed.Load(), tt.name == "ReplaceDefault"; got != want {
+				t.Errorf("%s(nil) closed previous = %v, want %v", tt.name, got, want)
+			}
+
+			// 不应 panic
+			slog.Info("after nil")
+		})
+	}
+}
+
+func TestReplaceDefault_ClosesPrevious(t *testing.T) {
+	t.Cleanup(ResetDefault)
+
+	if err := ReplaceDefault(NewNop()); err != nil {
+		t.Fatalf("ReplaceDefault() without previous error = %v", err)
+	}
+
+	errClose := errors.New("close failed")
+	var closed atomic.Bool
+	var buf syncBuffer
+	SetDefault(newBufferLogger(&buf, func() error {
+		closed.Store(true)
+		return errClose
+	}))
+
+	if err := ReplaceDefault(NewNop()); !errors.Is(err, errClose) {
+		t.Errorf("ReplaceDefault() error = %v, want %v", err, errClose)
+	}
+	if !closed.Load() {
+		t.Error("ReplaceDefault() should close the previous logger")
+	}
+}
+
+// slowHandler 在 Handle 中阻塞，用于模拟在途记录
+type slowHandler struct {
+	started chan struct{}
+	release chan struct{}
+	closed  *atomic.Bool
+	lost    *atomic.Int64
+}
+
+func (h *slowHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *slowHandler) Handle(context.Context, slog.Record) error {
+	close(h.started)
+	<-h.release
+	if h.closed.Load() {
+		h.lost.Add(1)
+	}
+	return nil
+}
+
+func (h *slowHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *slowHandler) WithGroup(string) slog.Handler      { return h }
+
+func TestReplaceDefault_WaitsForInFlight(t *testing.T) {
+	t.Cleanup(ResetDefault)
+
+	var closed atomic.Bool
+	var lost atomic.Int64
+	h := &slowHandler{started: make(chan struct{}), release: make(chan struct{}), closed: &closed, lost: &lost}
+	SetDefault(NewWithCleanup(h, func() error {
+		closed.Store(true)
+		return nil
+	}))
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		slog.Info("in flight")
+	}()
+	<-h.started
+
+	replaced := make(chan error, 1)
+	go func() { replaced <- ReplaceDefault(NewNop()) }()
+
+	select {
+	case <-replaced:
+		t.Fatal("ReplaceDefault() should wait for in-flight records")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(h.release)
+	wg.Wait()
+	if err := <-replaced; err != nil {
+		t.Errorf("ReplaceDefault() error = %v", err)
+	}
+	if lost.Load() != 0 {
+		t.Error("in-flight record was written after the logger was closed")
+	}
+}
+
+func TestSetDefault_ConcurrentSwap(t *testing.T) {
+	t.Cleanup(ResetDefault)
+
+	var bufs [4]syncBuffer
+	SetDefault(newBufferLogger(&bufs[0], nil))
+
+	const writers, perWriter = 4, 200
+	var wg sync.WaitGroup
+	for range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range perWriter {
+				slog.Info("tick")
+			}
+		}()
+	}
+	for i := 1; i < len(bufs); i++ {
+		if err := ReplaceDefault(newBufferLogger(&bufs[i], nil)); err != nil {
+			t.Errorf("ReplaceDefault() error = %v", err)
+		}
+	}
+	wg.Wait()
+
+	total := 0
+	for i := range bufs {
+		total += strings.Count(bufs[i].String(), "msg=tick")
+	}
+	if total != writers*perWriter {
+		t.Errorf("records written = %d, want %d", total, writers*perWriter)
+	}
+}
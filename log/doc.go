@@ -38,9 +38,9 @@
 //		Output: log.OutputStdout,
 //	})
 //
-//	// Method 2: Using NewWithHandler (similar to cli.NewStartupWithAdapter)
-//	handler, err := zaplog.NewHandler(log.DefaultOptions())
-//	logger := log.NewWithHandler(handler)
+//	// Method 2: Using NewWithCleanup with a zap handler
+//	handler, cleanup, err := zaplog.NewHandlerWithCleanup(log.DefaultOptions())
+//	logger := log.NewWithCleanup(handler, cleanup)
 //
 // # Configuration Options
 //
@@ -52,12 +52,63 @@
 //
 // Format constants:
 //   - log.FormatJSON    - JSON format (K8s standard)
-//   - log.FormatConsole - Console format (human-readable)
+//   - log.FormatConsole - Colored console format for local development
+//   - log.FormatText    - logfmt (key=value) format
+//   - log.FormatOTel    - OpenTelemetry log data model (OTLP-JSON shaped)
 //
 // Output constants:
 //   - log.OutputStdout - Standard output (K8s standard)
 //   - log.OutputStderr - Standard error
 //   - "/path/to/file"  - File path
+//   - "syslog://", "syslog://host:514", "unix:///dev/log" - syslog (RFC 5424)
+//   - "journald"       - systemd-journald native protocol
+//   - "tcp://host:port", "udp://host:port" - Newline-delimited records to a collector
+//
+// Network outputs reconnect with exponential backoff; records written while
+// the collector is unreachable are dropped. Syslog outputs accept facility and
+// tag query parameters, e.g. "unix:///dev/log?facility=local0&tag=api".
+// The syslog severity and journald PRIORITY come from the record level, and
+// journald MESSAGE holds the record message, with the formatted record in a
+// RECORD field. Writers that need this metadata implement RecordWriter.
+//
+// # Configuration Files
+//
+// Options carries mapstructure/yaml (snake_case), default and validate tags,
+// so it can be embedded in a config struct or loaded from a sub-key:
+//
+//	# config.yaml
+//	log:
+//	  level: info
+//	  format: json
+//	  output: /var/log/app.log
+//	  sampling:
+//	    initial: 100
+//	    thereafter: 100
+//
+//	logger, err := log.NewFromConfig(loader, "log")
+//
+// Options implements config.Validatable: unknown levels and formats, and
+// outputs that cannot be written, are rejected when the config is loaded.
+// New also rejects unknown levels and formats instead of falling back to info.
+//
+// # Global Default Logger
+//
+// SetDefault installs a process-wide logger and routes slog.Default() and the
+// standard library log package to it. Loggers derived from slog.Default()
+// (With, WithGroup) follow later swaps. ReplaceDefault swaps at runtime, e.g.
+// on config reload, waits for in-flight records and then closes the old one.
+// ResetDefault, or passing nil to either function, restores the previous
+// slog.Default() and the standard library log output and flags:
+//
+//	logger, _ := log.New(opts)
+//	log.SetDefault(logger)
+//	slog.Info("started") // written by logger
+//
+//	// on reload
+//	next, _ := log.New(newOpts)
+//	if err := log.ReplaceDefault(next); err != nil {
+//		slog.Warn("close previous logger", log.Err(err))
+//	}
 //
 // # Kubernetes Deployment
 //
@@ -86,6 +137,171 @@
 //		Output: "/var/log/app.log",
 //	})
 //
+// File paths may contain {app}, {hostname}, {pid} and {date} placeholders,
+// expanded when the output is opened. FileMode and DirMode set the
+// permissions of the log file and of directories created for it
+// (defaults 0600 and 0750):
+//
+//	logger, _ := log.New(log.Options{
+//		Output:   "/var/log/{app}/{app}-{hostname}-{date}.log",
+//		AppName:  "billing",
+//		FileMode: 0o640,
+//	})
+//
+// Opening fails with ErrOpenFile and the reason when the path is a directory
+// or cannot be written, e.g. on a read-only file system.
+//
+// # OpenTelemetry Format
+//
+// FormatOTel writes one OTLP-JSON shaped record per line with severityNumber,
+// severityText, timeUnixNano, body, attributes and the trace/span IDs carried
+// by the context. AppName and AppVersion become the service.name and
+// service.version resource attributes; WithApp fills them from the same app
+// info passed to cli.NewStartup:
+//
+//	opts := log.Options{Level: log.LevelInfo, Format: log.FormatOTel, Output: log.OutputStdout}
+//	logger, _ := log.New(opts.WithApp(adapter))
+//
+//	ctx = log.ContextWithTrace(ctx, traceID, spanID)
+//	logger.InfoContext(ctx, "order created", "order_id", id)
+//
+// When the OpenTelemetry SDK is in use, register SetTraceExtractor to read the
+// active SpanContext instead. The zap adapter cannot produce this data model
+// and rejects FormatOTel with ErrUnsupportedFormat.
+//
+// # Console Format
+//
+// FormatConsole prints aligned, color-coded levels with short timestamps and
+// caller paths relative to the working directory. Errors and multi-line
+// values (such as stack traces) are rendered as indented blocks:
+//
+//	12:04:05.123 ERROR request failed code=500 handler/user.go:42
+//	    err: dial tcp 10.0.0.1:5432: connect: connection refused
+//
+// Colors are disabled automatically when NO_COLOR is set, TERM=dumb, or the
+// output is not a terminal.
+//
+// # Error Chains
+//
+// Err expands an error into msg, chain (each %w layer and errors.Join branch)
+// and the names of gox sentinel errors found in it. Errors wrapped with Wrap
+// or WithStack carry the stack trace captured at the wrap site:
+//
+//	if err := loader.Load(path, &cfg); err != nil {
+//		logger.Error("load config", log.Err(log.Wrap(err, "startup")))
+//	}
+//
+// Set Options.ExpandErrors to expand every error-valued attribute, including
+// slog.Any("err", err). Each gox package registers its own sentinels, and
+// custom ones can be added with RegisterSentinel. With Options.Redact, the
+// messages of every layer in the chain are redacted too.
+//
+// # Timing
+//
+// Timed measures an operation and logs it when the returned DoneFunc is
+// called. Fast operations are logged at debug, operations reaching
+// Options.SlowThreshold (default 1s) at warn, and failures at error with
+// the error attached:
+//
+//	done := logger.Timed(ctx, "db.query", "table", "users")
+//	rows, err := db.QueryContext(ctx, q)
+//	done(err) // msg=db.query table=users duration=12.3ms
+//
+// Duration attributes are formatted as time.Duration strings ("1.5s") by
+// every format and by the zap adapter.
+//
+// # Sensitive Field Redaction
+//
+// Attributes whose key contains a sensitive keyword (password, token, ...)
+// are masked, nested groups included. Regex patterns mask matching
+// fragments of string values, messages, and the text of error and
+// fmt.Stringer values:
+//
+//	logger, _ := log.New(log.Options{
+//		Level:  log.LevelInfo,
+//		Format: log.FormatJSON,
+//		Output: log.OutputStdout,
+//		Redact: &log.RedactOptions{
+//			Patterns: []string{log.PatternCreditCard, log.PatternEmail},
+//		},
+//	})
+//
+//	logger.Info("login", "user", "alice", "password", "hunter2") // password=******
+//
+// # Sampling
+//
+// Sampling protects hot paths from log floods. Within each Tick, the first
+// Initial records with the same level and message are written, then every
+// Thereafter-th. Dropped counts are reported as a warn record every
+// ReportInterval, by a background goroutine that Close stops:
+//
+//	logger, _ := log.New(log.Options{
+//		Level:    log.LevelInfo,
+//		Format:   log.FormatJSON,
+//		Output:   log.OutputStdout,
+//		Sampling: &log.SamplingOptions{Initial: 100, Thereafter: 100},
+//	})
+//
+// The zap adapter applies the same options via zapcore.NewSamplerWithOptions.
+// Without Sampling, it keeps zap's defaults: the JSON format samples like
+// zap.NewProductionConfig, other formats do not sample.
+//
+// # Deduplication
+//
+// Dedup collapses bursts of identical records. Records with the same level,
+// message and values for the configured Keys are written once per Window;
+// repeats are counted and summarized when the window ends or on Close:
+//
+//	logger, _ := log.New(log.Options{
+//		Level:  log.LevelInfo,
+//		Format: log.FormatJSON,
+//		Output: log.OutputStdout,
+//		Dedup:  &log.DedupOptions{Window: 10 * time.Second, Keys: []string{"addr"}},
+//	})
+//
+//	// msg="connection refused (repeated 384 times in 9.8s)" addr=10.0.0.1:5432 repeated=384
+//
+// Windows are fixed and start with the first record of a kind; other records
+// in between do not end them, so in A, B, A the second A is collapsed too.
+// At most MaxKeys kinds are tracked at once; further kinds pass through
+// uncollapsed until a window ends.
+//
+// # Asynchronous Output
+//
+// Async mode queues records in a bounded ring buffer that a background
+// goroutine flushes to the output. When the buffer is full, the Overflow
+// policy decides whether to block, drop the oldest or drop the newest record.
+// An unknown policy is rejected with ErrInvalidOverflow:
+//
+//	logger, _ := log.New(log.Options{
+//		Level:  log.LevelInfo,
+//		Format: log.FormatJSON,
+//		Output: "/var/log/app.log",
+//		Async: &log.AsyncOptions{
+//			BufferSize: 4096,
+//			Overflow:   log.OverflowDropOldest,
+//		},
+//	})
+//	defer logger.Close() // flushes within CloseTimeout
+//
+// Logger.AsyncStats reports the dropped and buffered record counts.
+//
+// The zap adapter maps async mode to zapcore.BufferedWriteSyncer, configured
+// by BufferBytes and FlushInterval. It ignores BufferSize, Overflow and
+// CloseTimeout: the syncer writes through when full and never drops records,
+// so AsyncStats stays zero.
+//
+// # Graceful Shutdown
+//
+// Logger.Close flushes buffered records and returns close errors from the
+// output. Exit flushes every logger created by this package (including the
+// zap adapter) before terminating the process:
+//
+//	if err := run(); err != nil {
+//		logger.Error("fatal", "error", err)
+//		log.Exit(1)
+//	}
+//
 // # Architecture
 //
 //	Default:  log.New() → slog.Handler (stdlib) → no dependencies
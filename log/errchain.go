@@ -0,0 +1,221 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/chinayin/gox/internal/sentinel"
+)
+
+// ErrorKey Err 使用的属性 key
+const ErrorKey = "error"
+
+// maxChainDepth 展开错误链的最大深度，防止异常实现导致死循环
+const maxChainDepth = 32
+
+func init() {
+	RegisterSentinel("log.ErrOpenFile", ErrOpenFile)
+	RegisterSentinel("log.ErrInvalidPattern", ErrInvalidPattern)
+	RegisterSentinel("log.ErrWriterClosed", ErrWriterClosed)
+	RegisterSentinel("log.ErrFlushTimeout", ErrFlushTimeout)
+	RegisterSentinel("log.ErrInvalidOutput", ErrInvalidOutput)
+	RegisterSentinel("log.ErrOutputUnavailable", ErrOutputUnavailable)
+	RegisterSentinel("log.ErrInvalidLevel", ErrInvalidLevel)
+	RegisterSentinel("log.ErrInvalidFormat", ErrInvalidFormat)
+	RegisterSentinel("log.ErrInvalidOverflow", ErrInvalidOverflow)
+	RegisterSentinel("log.ErrUnsupportedFormat", ErrUnsupportedFormat)
+	RegisterSentinel("log.ErrNotInitialized", ErrNotInitialized)
+}
+
+// RegisterSentinel 登记哨兵错误，展开错误链时会输出其名称
+// gox 各包在自身的 init 中登记哨兵错误，log 不依赖这些包；err 的动态类型必须可比较
+func RegisterSentinel(name string, err error) {
+	sentinel.Register(name, err)
+}
+
+// sentinelName 返回 err 对应的哨兵名称
+func sentinelName(err error) string {
+	return sentinel.Name(err)
+}
+
+// Err 返回展开错误链的属性，key 为 "error"
+//
+// 输出包含 msg、chain（逐层展开 %w 与 errors.Join）与 sentinels（命中的哨兵错误名称），
+// 经 Wrap/WithStack 包装的层级附带包装位置的堆栈
+func Err(err error) slog.Attr {
+	return ErrAttr(ErrorKey, err)
+}
+
+// ErrAttr 与 Err 相同，但使用指定的 key
+func ErrAttr(key string, err error) slog.Attr {
+	if err == nil {
+		return slog.Any(key, nil)
+	}
+	return slog.Any(key, errorValue{err: err})
+}
+
+// errorValue 延迟展开的错误值，实现 slog.LogValuer
+type errorValue struct {
+	err error
+}
+
+// errorEntry 错误链中的一层
+type errorEntry struct {
+	Msg      string         `json:"msg"`
+	Type     string         `json:"type"`
+	Sentinel string         `json:"sentinel,omitempty"`
+	Stack    []string       `json:"stack,omitempty"`
+	Causes   [][]errorEntry `json:"causes,omitempty"`
+}
+
+// LogValue 实现 slog.LogValuer
+func (v errorValue) LogValue() slog.Value {
+	chain := errorChain(v.err, 0)
+	attrs := []slog.Attr{
+		slog.String("msg", v.err.Error()),
+		slog.Any("chain", chain),
+	}
+	if names := collectSentinels(chain, nil); len(names) > 0 {
+		attrs = append(attrs, slog.Any("sentinels", names))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// text 多行文本形式，用于控制台输出
+func (v errorValue) text() string {
+	var b strings.Builder
+	b.WriteString(v.err.Error())
+	writeChainText(&b, errorChain(v.err, 0), "  ")
+	return b.String()
+}
+
+// errorChain 逐层展开错误链，errors.Join 等多错误节点展开为 Causes
+func errorChain(err error, depth int) []errorEntry {
+	var chain []errorEntry
+	for err != nil && depth < maxChainDepth {
+		depth++
+		entry := errorEntry{
+			Msg:      err.Error(),
+			Type:     fmt.Sprintf("%T", err),
+			Sentinel: sentinelName(err),
+		}
+		if se, ok := err.(*stackError); ok {
+			entry.Stack = se.StackTrace()
+		}
+
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, cause := range u.Unwrap() {
+				entry.Causes = append(entry.Causes, errorChain(cause, depth))
+			}
+			return append(chain, entry)
+		case interface{ Unwrap() error }:
+			chain = append(chain, entry)
+			err = u.Unwrap()
+		default:
+			return append(chain, entry)
+		}
+	}
+	return chain
+}
+
+// collectSentinels 收集错误树中命中的哨兵名称
+func collectSentinels(chain []errorEntry, names []string) []string {
+	for _, e := range chain {
+		if e.Sentinel != "" {
+			names = append(names, e.Sentinel)
+		}
+		for _, c := range e.Causes {
+			names = collectSentinels(c, names)
+		}
+	}
+	return names
+}
+
+// writeChainText 以缩进文本输出错误链
+func writeChainText(b *strings.Builder, chain []errorEntry, indent string) {
+	for _, e := range chain {
+		b.WriteString("\n" + indent + "[" + e.Type + "] " + e.Msg)
+		if e.Sentinel != "" {
+			b.WriteString(" (" + e.Sentinel + ")")
+		}
+		for _, frame := range e.Stack {
+			b.WriteString("\n" + indent + "    at " + frame)
+		}
+		for _, c := range e.Causes {
+			writeChainText(b, c, indent+"  ")
+		}
+	}
+}
+
+// ErrorHandler 错误展开中间件
+// 将值为 error 的属性（含嵌套分组）替换为 Err 的结构化错误链
+type ErrorHandler struct {
+	next slog.Handler
+}
+
+var (
+	_ slog.Handler = (*ErrorHandler)(nil)
+	_ io.Closer    = (*ErrorHandler)(nil)
+)
+
+// NewErrorHandler 创建错误展开中间件
+func NewErrorHandler(next slog.Handler) *ErrorHandler {
+	return &ErrorHandler{next: next}
+}
+
+// Enabled 实现 slog.Handler
+func (h *ErrorHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle 实现 slog.Handler
+func (h *ErrorHandler) Handle(ctx context.Context, r slog.Record) error {
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(expandErrorAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, nr)
+}
+
+// WithAttrs 实现 slog.Handler
+func (h *ErrorHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	expanded := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		expanded[i] = expandErrorAttr(a)
+	}
+	return &ErrorHandler{next: h.next.WithAttrs(expanded)}
+}
+
+// WithGroup 实现 slog.Handler
+func (h *ErrorHandler) WithGroup(name string) slog.Handler {
+	return &ErrorHandler{next: h.next.WithGroup(name)}
+}
+
+// Close 关闭下游 Handler
+func (h *ErrorHandler) Close() error {
+	return closeHandler(h.next)
+}
+
+// expandErrorAttr 将 error 值替换为 errorValue，分组递归处理
+func expandErrorAttr(a slog.Attr) slog.Attr {
+	switch a.Value.Kind() {
+	case slog.KindAny:
+		if err, ok := a.Value.Any().(error); ok && err != nil {
+			return slog.Any(a.Key, errorValue{err: err})
+		}
+	case slog.KindGroup:
+		group := a.Value.Group()
+		expanded := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			expanded[i] = expandErrorAttr(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(expanded...)}
+	default:
+	}
+	return a
+}
@@ -0,0 +1,197 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/chinayin/gox/config"
+)
+
+// decodeErrorAttr 解析 JSON 输出中的错误属性
+func decodeErrorAttr(t *testing.T, out []byte, key string) map[string]any {
+	t.Helper()
+	var rec map[string]any
+	if err := json.Unmarshal(out, &rec); err != nil {
+		t.Fatalf("invalid JSON output %s: %v", out, err)
+	}
+	attr, ok := rec[key].(map[string]any)
+	if !ok {
+		t.Fatalf("attr %q should be an object, got: %s", key, out)
+	}
+	return attr
+}
+
+func TestErr_Chain(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	base := fmt.Errorf("%w: app.yaml", config.ErrReadFailed)
+	err := Wrap(base, "load config")
+	logger.Error("startup failed", Err(err))
+
+	attr := decodeErrorAttr(t, buf.Bytes(), ErrorKey)
+	if attr["msg"] != "load config: gox/config: failed to read config: app.yaml" {
+		t.Errorf("msg = %v", attr["msg"])
+	}
+
+	chain, _ := attr["chain"].([]any)
+	if len(chain) != 3 {
+		t.Fatalf("chain length = %d, want 3: %v", len(chain), attr["chain"])
+	}
+	wrapped, _ := chain[0].(map[string]any)
+	stack, _ := wrapped["stack"].([]any)
+	if len(stack) == 0 || !strings.Contains(fmt.Sprint(stack[0]), "TestErr_Chain") {
+		t.Errorf("wrapped entry should carry the wrap-site stack, got: %v", wrapped["stack"])
+	}
+	root, _ := chain[2].(map[string]any)
+	if root["sentinel"] != "config.ErrReadFailed" {
+		t.Errorf("root sentinel = %v, want config.ErrReadFailed", root["sentinel"])
+	}
+
+	sentinels, _ := attr["sentinels"].([]any)
+	if len(sentinels) != 1 || sentinels[0] != "config.ErrReadFailed" {
+		t.Errorf("sentinels = %v, want [config.ErrReadFailed]", attr["sentinels"])
+	}
+}
+
+func TestErr_Join(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+
+	err := fmt.Errorf("shutdown: %w", errors.Join(
+		fmt.Errorf("db: %w", ErrFlushTimeout),
+		errors.New("cache: closed"),
+	))
+	logger.Error("failed", ErrAttr("err", err))
+
+	attr := decodeErrorAttr(t, buf.Bytes(), "err")
+	chain, _ := attr["chain"].([]any)
+	if len(chain) != 2 {
+		t.Fatalf("chain length = %d, want 2: %v", len(chain), attr["chain"])
+	}
+	joined, _ := chain[1].(map[string]any)
+	causes, _ := joined["causes"].([]any)
+	if len(causes) != 2 {
+		t.Fatalf("join causes = %d, want 2: %v", len(causes), joined)
+	}
+	if fmt.Sprint(attr["sentinels"]) != "[log.ErrFlushTimeout]" {
+		t.Errorf("sentinels = %v, want [log.ErrFlushTimeout]", attr["sentinels"])
+	}
+}
+
+func TestWrap_Nil(t *testing.T) {
+	if Wrap(nil, "msg") != nil || WithStack(nil) != nil {
+		t.Error("Wrap/WithStack should return nil for nil error")
+	}
+}
+
+func TestWrap_ErrorsIs(t *testing.T) {
+	err := WithStack(Wrap(config.ErrNotFound, "lookup"))
+	if !errors.Is(err, config.ErrNotFound) {
+		t.Error("wrapped error should match sentinel via errors.Is")
+	}
+	if err.Error() != "lookup: "+config.ErrNotFound.Error() {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
+
+func TestRegisterSentinel(t *testing.T) {
+	errCustom := errors.New("custom")
+	RegisterSentinel("app.ErrCustom", errCustom)
+
+	if got := sentinelName(errCustom); got != "app.ErrCustom" {
+		t.Errorf("sentinelName() = %q, want app.ErrCustom", got)
+	}
+	if got := sentinelName(errors.New("custom")); got != "" {
+		t.Errorf("sentinelName() should match identity only, got %q", got)
+	}
+}
+
+func TestErrorHandler_ExpandsAnyErrors(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewErrorHandler(slog.NewJSONHandler(&buf, nil)))
+
+	logger.With("cause", WithStack(config.ErrMergeFailed)).Error("failed",
+		slog.Group("db", slog.Any("err", config.ErrNotFound)),
+	)
+
+	out := buf.String()
+	for _, want := range []string{`"config.ErrMergeFailed"`, `"config.ErrNotFound"`, `"chain":[`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output should contain %s, got: %s", want, out)
+		}
+	}
+}
+
+func TestConsoleHandler_ErrorChain(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewConsoleHandler(&buf, nil))
+
+	logger.Error("failed", Err(Wrap(config.ErrNotFound, "load")))
+
+	out := buf.String()
+	for _, want := range []string{
+		"    error:\n",
+		"load: gox/config: config file not found",
+		"(config.ErrNotFound)",
+		"    at ",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("console output should contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func TestNew_ExpandErrors(t *testing.T) {
+	logFile := t.TempDir() + "/app.log"
+
+	logger, err := New(Options{
+		Level:        LevelInfo,
+		Format:       FormatJSON,
+		Output:       logFile,
+		ExpandErrors: true,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	logger.Error("failed", "err", Wrap(config.ErrValidationFailed, "check"))
+	_ = logger.Close()
+
+	if data := readFile(t, logFile); !strings.Contains(data, `"sentinels":["config.ErrValidationFailed"]`) {
+		t.Errorf("error attrs should be expanded, got: %s", data)
+	}
+}
+
+func TestNew_ExpandErrorsRedactsChain(t *testing.T) {
+	logFile := t.TempDir() + "/app.log"
+
+	logger, err := New(Options{
+		Level:        LevelInfo,
+		Format:       FormatJSON,
+		Output:       logFile,
+		ExpandErrors: true,
+		Redact:       &RedactOptions{Patterns: []string{`sk-[a-z0-9]+`}},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	// 密钥位于被包装的内层错误中，链上每一层的 msg 都包含它
+	inner := errors.New("api key sk-abc123 rejected")
+	joined := errors.Join(fmt.Errorf("retry: %w", inner), errors.New("other"))
+	logger.Error("call failed", "err", Wrap(fmt.Errorf("call api: %w", joined), "sync"))
+	_ = logger.Close()
+
+	data := readFile(t, logFile)
+	if strings.Contains(data, "sk-abc123") {
+		t.Errorf("secret in the error chain should be masked, got: %s", data)
+	}
+	if !strings.Contains(data, `"chain":[`) || !strings.Contains(data, "api key "+DefaultMask+" rejected") {
+		t.Errorf("chain should keep the masked messages, got: %s", data)
+	}
+}
@@ -6,4 +6,34 @@ import "errors"
 var (
 	// ErrOpenFile is returned when opening a log file fails.
 	ErrOpenFile = errors.New("gox/log: failed to open log file")
+
+	// ErrInvalidPattern is returned when a redaction pattern fails to compile.
+	ErrInvalidPattern = errors.New("gox/log: invalid redaction pattern")
+
+	// ErrWriterClosed is returned when writing to a closed async writer.
+	ErrWriterClosed = errors.New("gox/log: writer closed")
+
+	// ErrFlushTimeout is returned when flushing buffered records times out on close.
+	ErrFlushTimeout = errors.New("gox/log: flush timed out")
+
+	// ErrInvalidOutput is returned when a URL-style output cannot be parsed.
+	ErrInvalidOutput = errors.New("gox/log: invalid output")
+
+	// ErrInvalidLevel is returned when Options.Level is not a known level.
+	ErrInvalidLevel = errors.New("gox/log: invalid level")
+
+	// ErrInvalidFormat is returned when Options.Format is not a known format.
+	ErrInvalidFormat = errors.New("gox/log: invalid format")
+
+	// ErrInvalidOverflow is returned when AsyncOptions.Overflow is not a known policy.
+	ErrInvalidOverflow = errors.New("gox/log: invalid async overflow policy")
+
+	// ErrUnsupportedFormat is returned when an adapter cannot produce a known format.
+	ErrUnsupportedFormat = errors.New("gox/log: unsupported format")
+
+	// ErrNotInitialized is returned when the default logger has not been set.
+	ErrNotInitialized = errors.New("gox/log: default logger not initialized")
+
+	// ErrOutputUnavailable is returned when a network output cannot be reached.
+	ErrOutputUnavailable = errors.New("gox/log: output unavailable")
 )
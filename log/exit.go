@@ -0,0 +1,68 @@
+package log
+
+import (
+	"errors"
+	"os"
+	"runtime"
+	"sync"
+	"weak"
+)
+
+// registry 登记由本包创建、尚未关闭的 Logger
+// 使用弱引用：未 Close 即不再被引用的 Logger 可被回收，回收后自动移除登记，
+// 避免短生命周期的 Logger 长期驻留。回收不会释放 Logger 的资源，仍需调用 Close
+var registry = struct {
+	mu      sync.Mutex
+	loggers map[weak.Pointer[Logger]]struct{}
+}{loggers: make(map[weak.Pointer[Logger]]struct{})}
+
+// register 登记 Logger，并在其被回收时移除登记
+func register(l *Logger) {
+	p := weak.Make(l)
+	registry.mu.Lock()
+	registry.loggers[p] = struct{}{}
+	registry.mu.Unlock()
+	runtime.AddCleanup(l, unregisterPointer, p)
+}
+
+// unregister 注销 Logger
+func unregister(l *Logger) {
+	unregisterPointer(weak.Make(l))
+}
+
+// unregisterPointer 按弱引用注销 Logger
+func unregisterPointer(p weak.Pointer[Logger]) {
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	delete(registry.loggers, p)
+}
+
+// CloseAll 关闭所有由本包（含 zap 适配器）创建且尚未关闭的 Logger
+// 返回各 Logger 关闭错误的合并结果
+func CloseAll() error {
+	registry.mu.Lock()
+	loggers := make([]*Logger, 0, len(registry.loggers))
+	for p := range registry.loggers {
+		if l := p.Value(); l != nil {
+			loggers = append(loggers, l)
+		}
+	}
+	registry.mu.Unlock()
+
+	var errs []error
+	for _, l := range loggers {
+		if err := l.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Exit 刷新并关闭所有 Logger 后以 code 退出进程
+// 用于替代 os.Exit，避免缓冲中的日志丢失
+func Exit(code int) {
+	if err := CloseAll(); err != nil {
+		_, _ = os.Stderr.WriteString("gox/log: close loggers: " + err.Error() + "\n")
+	}
+	os.Exit(code)
+}
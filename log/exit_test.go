@@ -0,0 +1,90 @@
+package log
+
+import (
+	"errors"
+	"log/slog"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+	"weak"
+)
+
+func TestLogger_Close_PropagatesError(t *testing.T) {
+	errCleanup := errors.New("cleanup failed")
+	calls := 0
+
+	logger := NewWithCleanup(slog.DiscardHandler, func() error {
+		calls++
+		return errCleanup
+	})
+
+	if err := logger.Close(); !errors.Is(err, errCleanup) {
+		t.Errorf("Close() error = %v, want %v", err, errCleanup)
+	}
+	if err := logger.Close(); !errors.Is(err, errCleanup) {
+		t.Errorf("second Close() error = %v, want %v", err, errCleanup)
+	}
+	if calls != 1 {
+		t.Errorf("cleanup called %d times, want 1", calls)
+	}
+}
+
+func TestCloseAll(t *testing.T) {
+	logFile := t.TempDir() + "/app.log"
+
+	logger, err := New(Options{
+		Level:  LevelInfo,
+		Format: FormatJSON,
+		Output: logFile,
+		Async:  &AsyncOptions{},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	closed := false
+	NewWithCleanup(slog.DiscardHandler, func() error {
+		closed = true
+		return nil
+	})
+
+	logger.Info("before exit")
+	if err := CloseAll(); err != nil {
+		t.Fatalf("CloseAll() error = %v", err)
+	}
+
+	if !closed {
+		t.Error("CloseAll() should close loggers created by NewWithCleanup")
+	}
+	if !strings.Contains(readFile(t, logFile), "before exit") {
+		t.Error("CloseAll() should flush async loggers")
+	}
+
+	registry.mu.Lock()
+	remaining := len(registry.loggers)
+	registry.mu.Unlock()
+	if remaining != 0 {
+		t.Errorf("registry should be empty after CloseAll, got %d", remaining)
+	}
+}
+
+func TestRegistry_ReleasesUnreferencedLoggers(t *testing.T) {
+	p := weak.Make(NewWithCleanup(slog.DiscardHandler, nil))
+
+	// 未 Close 的 Logger 不再被引用后可被回收，登记随之移除
+	deadline := time.Now().Add(time.Second)
+	for {
+		runtime.GC()
+		registry.mu.Lock()
+		_, ok := registry.loggers[p]
+		registry.mu.Unlock()
+		if !ok && p.Value() == nil {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("unreferenced logger should be released from the registry")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
@@ -0,0 +1,37 @@
+package httplog
+
+import (
+	"context"
+	"log/slog"
+)
+
+// loggerKey context 中请求级 Logger 的 key
+type loggerKey struct{}
+
+// requestIDKey context 中请求 ID 的 key
+type requestIDKey struct{}
+
+// NewContext 返回携带 logger 的 context
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext 返回 context 中的请求级 Logger
+// 未经过 Middleware 时返回 slog.Default()
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok && logger != nil {
+		return logger
+	}
+	return slog.Default()
+}
+
+// contextWithRequestID 返回携带请求 ID 的 context
+func contextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID 返回 context 中的请求 ID，未经过 Middleware 时返回空字符串
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,62 @@
+// Package httplog provides net/http access-log middleware built on log/slog.
+//
+// Each request is logged once after the handler returns, with method, path,
+// status, bytes, latency, remote_ip, user_agent and request_id. 5xx responses
+// are logged at error level, 4xx at warn and everything else at info. When
+// the handler panics, the request is logged with a panic attribute (as 500 if
+// no status was written) before the panic continues.
+//
+// # Basic Usage
+//
+//	import "github.com/chinayin/gox/log/httplog"
+//
+//	logger, err := log.New(log.DefaultOptions())
+//	if err != nil {
+//		panic(err)
+//	}
+//	defer logger.Close()
+//
+//	gen := idgen.NewUUIDv7()
+//	mw, err := httplog.New(logger.Logger, httplog.Options{
+//		Headers:   []string{"Authorization", "Referer"},
+//		Generator: gen,
+//	})
+//	if err != nil {
+//		panic(err)
+//	}
+//	http.ListenAndServe(":8080", mw(mux))
+//
+// # Request ID
+//
+// The X-Request-ID request header is reused when it is at most 128 bytes of
+// letters, digits and "-_.:+/="; otherwise an ID is generated from
+// Options.Generator or idgen.Default(). New returns idgen.ErrNotInitialized
+// when neither is set, since a Snowflake with a fixed node ID would repeat IDs
+// across instances. The ID is set on both the request and the response header.
+//
+// # Request-scoped Logger
+//
+// Handlers retrieve a logger carrying request_id from the request context:
+//
+//	func handle(w http.ResponseWriter, r *http.Request) {
+//		httplog.FromContext(r.Context()).Info("order created", "order_id", 42)
+//	}
+//
+// # Headers and Redaction
+//
+// Only headers listed in Options.Headers are logged. Values are masked with the
+// same rules as log.RedactHandler; by default Authorization, Cookie and API-key
+// style headers are masked.
+//
+// # Client IP
+//
+// With Options.TrustProxy, the client IP is read from X-Forwarded-For only when
+// the peer is a trusted proxy. The header is walked from the right, skipping
+// trusted hops, and the first untrusted address is logged; the leftmost entry
+// is client-controlled. TrustedProxies defaults to loopback and private ranges.
+//
+// # Skip Paths
+//
+// Health-check paths in DefaultSkipPaths are not logged. They still receive
+// the request ID and the request-scoped logger.
+package httplog
@@ -0,0 +1,322 @@
+package httplog
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/chinayin/gox/idgen"
+	"github.com/chinayin/gox/log"
+)
+
+// HeaderRequestID 请求 ID 请求头/响应头
+const HeaderRequestID = "X-Request-ID"
+
+// maxRequestIDLen 接受的外部请求 ID 最大长度，超出时重新生成
+const maxRequestIDLen = 128
+
+// requestIDPunct 外部请求 ID 中除字母与数字外允许的字符，覆盖 UUID、ULID、Base64 等常见格式
+const requestIDPunct = "-_.:+/="
+
+// DefaultMessage 访问日志的默认消息
+const DefaultMessage = "http request"
+
+// DefaultSkipPaths 默认跳过的健康检查路径
+var DefaultSkipPaths = []string{"/healthz", "/livez", "/readyz", "/health", "/ping"}
+
+// DefaultSensitiveHeaders 在 log.DefaultSensitiveKeywords 之外默认遮蔽的请求头关键字
+// Authorization、X-Api-Key 等已被默认关键字覆盖
+var DefaultSensitiveHeaders = []string{"cookie"}
+
+// Options 访问日志中间件配置
+type Options struct {
+	Message    string             // 日志消息，默认 DefaultMessage
+	SkipPaths  []string           // 不记录的路径（精确匹配），nil 时使用 DefaultSkipPaths
+	Headers    []string           // 额外记录的请求头，按 Redact 规则遮蔽
+	Redact     *log.RedactOptions // 请求头遮蔽规则，nil 时使用默认关键字与 DefaultSensitiveHeaders
+	Generator  idgen.Generator    // 请求 ID 生成器，nil 时使用 idgen.Default()，两者必须设置其一
+	TrustProxy bool               // 是否从 X-Forwarded-For / X-Real-IP 读取客户端 IP（仅当对端为可信代理时）
+
+	// TrustedProxies 可信代理的网段，nil 时信任回环与私有地址
+	// X-Forwarded-For 从右向左跳过可信代理，取第一个不可信的地址作为客户端 IP
+	TrustedProxies []netip.Prefix
+}
+
+// New 创建访问日志中间件
+//
+// 每个请求记录 method、path、status、bytes、latency、remote_ip、user_agent 与 request_id，
+// 5xx 使用 error 级别，4xx 使用 warn 级别，其余为 info。
+// 处理函数 panic 时同样记录访问日志（附带 panic 属性，未写出状态码时按 500 记录），然后继续 panic。
+// 请求未携带 X-Request-ID，或其超过 128 字节、含有字母数字与 "-_.:+/=" 以外的字符时，
+// 生成新的 ID，并写入请求头与响应头；
+// 请求级 Logger（附带 request_id）通过 FromContext 获取。
+// 未设置 Options.Generator 且未调用 idgen.SetDefault 时返回 idgen.ErrNotInitialized，
+// 避免各实例使用相同节点 ID 的 Snowflake 生成重复的请求 ID。
+func New(logger *slog.Logger, opts Options) (func(http.Handler) http.Handler, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	if opts.Message == "" {
+		opts.Message = DefaultMessage
+	}
+	if opts.SkipPaths == nil {
+		opts.SkipPaths = DefaultSkipPaths
+	}
+
+	redactOpts := log.RedactOptions{Keywords: slices.Concat(log.DefaultSensitiveKeywords, DefaultSensitiveHeaders)}
+	if opts.Redact != nil {
+		redactOpts = *opts.Redact
+	}
+	redactor, err := log.NewRedactHandler(slog.DiscardHandler, redactOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	// 未指定生成器时使用 idgen.Default()，并保留创建时的值，以防之后被 ResetDefault 清除
+	var fallback idgen.Generator
+	if opts.Generator == nil {
+		if fallback = idgen.Default(); fallback == nil {
+			return nil, fmt.Errorf("%w: set Options.Generator or call idgen.SetDefault", idgen.ErrNotInitialized)
+		}
+	}
+
+	skip := make(map[string]struct{}, len(opts.SkipPaths))
+	for _, p := range opts.SkipPaths {
+		skip[p] = struct{}{}
+	}
+
+	m := &middleware{
+		logger:   logger,
+		opts:     opts,
+		skip:     skip,
+		redactor: redactor,
+		fallback: fallback,
+	}
+	return m.wrap, nil
+}
+
+// middleware 访问日志中间件
+type middleware struct {
+	logger   *slog.Logger
+	opts     Options
+	skip     map[string]struct{}
+	redactor *log.RedactHandler
+	fallback idgen.Generator
+}
+
+// wrap 包装 next
+func (m *middleware) wrap(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := r.Header.Get(HeaderRequestID)
+		if !validRequestID(requestID) {
+			requestID = m.generator().Generate().String()
+			r.Header.Set(HeaderRequestID, requestID)
+		}
+		w.Header().Set(HeaderRequestID, requestID)
+
+		reqLogger := m.logger.With(slog.String("request_id", requestID))
+		ctx := NewContext(r.Context(), reqLogger)
+		ctx = contextWithRequestID(ctx, requestID)
+		r = r.WithContext(ctx)
+
+		if _, ok := m.skip[r.URL.Path]; ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		rw := &responseWriter{ResponseWriter: w}
+		defer func() {
+			if v := recover(); v != nil {
+				status := rw.status
+				if status == 0 {
+					status = http.StatusInternalServerError
+				}
+				m.log(reqLogger, r, rw, start, status, slog.Any("panic", v))
+				panic(v)
+			}
+		}()
+		next.ServeHTTP(rw, r)
+
+		status := rw.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		m.log(reqLogger, r, rw, start, status)
+	})
+}
+
+// log 输出一条访问日志
+func (m *middleware) log(logger *slog.Logger, r *http.Request, rw *responseWriter, start time.Time, status int, extra ...slog.Attr) {
+	attrs := []slog.Attr{
+		slog.String("method", r.Method),
+		slog.String("path", r.URL.Path),
+		slog.Int("status", status),
+		slog.Int64("bytes", rw.bytes),
+		slog.Duration("latency", time.Since(start)),
+		slog.String("remote_ip", m.remoteIP(r)),
+		slog.String("user_agent", r.UserAgent()),
+	}
+	if headers := m.headerAttrs(r.Header); len(headers) > 0 {
+		attrs = append(attrs, slog.Attr{Key: "headers", Value: slog.GroupValue(headers...)})
+	}
+	attrs = append(attrs, extra...)
+
+	logger.LogAttrs(r.Context(), levelForStatus(status), m.opts.Message, attrs...)
+}
+
+// validRequestID 判断外部请求 ID 是否可以沿用：非空、不超过 maxRequestIDLen，
+// 且只含字母、数字与 requestIDPunct，避免换行、引号等字符进入日志与响应头
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for i := range len(id) {
+		c := id[i]
+		if ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9') {
+			continue
+		}
+		if !strings.ContainsRune(requestIDPunct, rune(c)) {
+			return false
+		}
+	}
+	return true
+}
+
+// generator 返回请求 ID 生成器
+func (m *middleware) generator() idgen.Generator {
+	if m.opts.Generator != nil {
+		return m.opts.Generator
+	}
+	if g := idgen.Default(); g != nil {
+		return g
+	}
+	return m.fallback
+}
+
+// headerAttrs 按配置提取请求头并遮蔽敏感值
+func (m *middleware) headerAttrs(h http.Header) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(m.opts.Headers))
+	for _, name := range m.opts.Headers {
+		values := h.Values(name)
+		if len(values) == 0 {
+			continue
+		}
+		a := slog.String(http.CanonicalHeaderKey(name), strings.Join(values, ", "))
+		attrs = append(attrs, m.redactor.RedactAttr(a))
+	}
+	return attrs
+}
+
+// remoteIP 返回客户端 IP
+// 启用 TrustProxy 且对端为可信代理时，从右向左遍历 X-Forwarded-For，
+// 跳过可信代理追加的地址，返回第一个不可信的地址；最左侧由客户端填写，不可直接信任
+func (m *middleware) remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	if !m.opts.TrustProxy || !m.trusted(host) {
+		return host
+	}
+
+	var hops []string
+	for _, v := range r.Header.Values("X-Forwarded-For") {
+		for hop := range strings.SplitSeq(v, ",") {
+			hops = append(hops, strings.TrimSpace(hop))
+		}
+	}
+	for i := len(hops) - 1; i >= 0; i-- {
+		if i == 0 || !m.trusted(hops[i]) {
+			return hops[i]
+		}
+	}
+
+	if ip := r.Header.Get("X-Real-IP"); ip != "" {
+		return ip
+	}
+	return host
+}
+
+// trusted 判断 ip 是否为可信代理
+func (m *middleware) trusted(ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	if m.opts.TrustedProxies == nil {
+		return addr.IsLoopback() || addr.IsPrivate()
+	}
+	for _, p := range m.opts.TrustedProxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// levelForStatus 按状态码选择日志级别
+func levelForStatus(status int) slog.Level {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return slog.LevelError
+	case status >= http.StatusBadRequest:
+		return slog.LevelWarn
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// responseWriter 记录状态码与写入字节数
+type responseWriter struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+// WriteHeader 实现 http.ResponseWriter
+// 1xx 信息响应（101 协议切换除外）之后还会有最终响应，不记录
+func (w *responseWriter) WriteHeader(code int) {
+	if w.status == 0 && (code >= http.StatusOK || code == http.StatusSwitchingProtocols) {
+		w.status = code
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+// Write 实现 http.ResponseWriter
+func (w *responseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Flush 实现 http.Flusher
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Hijack 实现 http.Hijacker，用于 WebSocket 等协议升级
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if h, ok := w.ResponseWriter.(http.Hijacker); ok {
+		return h.Hijack()
+	}
+	return nil, nil, http.ErrNotSupported
+}
+
+// Unwrap 供 http.ResponseController 访问底层 ResponseWriter
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
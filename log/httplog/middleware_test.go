@@ -0,0 +1,379 @@
+package httplog
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chinayin/gox/idgen"
+	"github.com/chinayin/gox/log"
+	"github.com/chinayin/gox/log/logtest"
+)
+
+// staticGenerator 返回固定 ID 的生成器
+type staticGenerator string
+
+func (g staticGenerator) Generate() idgen.ID {
+	return idgen.NewID(0, string(g), nil)
+}
+
+func newMiddleware(t *testing.T, opts Options) (func(http.Handler) http.Handler, *logtest.Recorder) {
+	t.Helper()
+	if opts.Generator == nil {
+		opts.Generator = staticGenerator("test-id")
+	}
+	logger, rec := logtest.New(t)
+	mw, err := New(logger.Logger, opts)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return mw, rec
+}
+
+func TestMiddleware_AccessLog(t *testing.T) {
+	mw, rec := newMiddleware(t, Options{Generator: staticGenerator("req-1")})
+
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("hello"))
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/users?token=abc", nil)
+	req.RemoteAddr = "10.0.0.1:4321"
+	req.Header.Set("User-Agent", "test-agent")
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, req)
+
+	rec.AssertLogged(t,
+		logtest.Level(slog.LevelInfo),
+		logtest.Message(DefaultMessage),
+		logtest.Attr("method", http.MethodPost),
+		logtest.Attr("path", "/users"),
+		logtest.Attr("status", http.StatusCreated),
+		logtest.Attr("bytes", 5),
+		logtest.Attr("remote_ip", "10.0.0.1"),
+		logtest.Attr("user_agent", "test-agent"),
+		logtest.Attr("request_id", "req-1"),
+		logtest.HasAttr("latency"),
+	)
+	if got := rr.Header().Get(HeaderRequestID); got != "req-1" {
+		t.Errorf("response %s = %q, want req-1", HeaderRequestID, got)
+	}
+}
+
+func TestMiddleware_StatusLevels(t *testing.T) {
+	tests := []struct {
+		status int
+		want   slog.Level
+	}{
+		{http.StatusOK, slog.LevelInfo},
+		{http.StatusFound, slog.LevelInfo},
+		{http.StatusNotFound, slog.LevelWarn},
+		{http.StatusServiceUnavailable, slog.LevelError},
+	}
+	for _, tt := range tests {
+		t.Run(http.StatusText(tt.status), func(t *testing.T) {
+			mw, rec := newMiddleware(t, Options{})
+			h := mw(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+			rec.AssertLogged(t, logtest.Level(tt.want), logtest.Attr("status", tt.status))
+		})
+	}
+}
+
+func TestMiddleware_DefaultStatus(t *testing.T) {
+	mw, rec := newMiddleware(t, Options{})
+	h := mw(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	rec.AssertLogged(t, logtest.Attr("status", http.StatusOK), logtest.Attr("bytes", 0))
+}
+
+func TestMiddleware_InformationalStatus(t *testing.T) {
+	mw, rec := newMiddleware(t, Options{})
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusEarlyHints)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	// 1xx 信息响应不是最终状态码
+	rec.AssertLogged(t, logtest.Level(slog.LevelWarn), logtest.Attr("status", http.StatusNotFound))
+}
+
+func TestMiddleware_Panic(t *testing.T) {
+	tests := []struct {
+		name   string
+		write  bool
+		status int
+	}{
+		{name: "before write", status: http.StatusInternalServerError},
+		{name: "after write", write: true, status: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mw, rec := newMiddleware(t, Options{})
+			h := mw(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				if tt.write {
+					_, _ = w.Write([]byte("partial"))
+				}
+				panic("boom")
+			}))
+
+			func() {
+				defer func() {
+					if r := recover(); r != "boom" {
+						t.Errorf("recover() = %v, want boom (re-panic)", r)
+					}
+				}()
+				h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+			}()
+
+			rec.AssertLogged(t,
+				logtest.Message(DefaultMessage),
+				logtest.Attr("status", tt.status),
+				logtest.Attr("panic", "boom"),
+				logtest.Attr("request_id", "test-id"),
+			)
+		})
+	}
+}
+
+func TestMiddleware_RequestID(t *testing.T) {
+	mw, rec := newMiddleware(t, Options{Generator: staticGenerator("generated")})
+
+	var seen string
+	h := mw(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		seen = RequestID(r.Context())
+	}))
+
+	// 沿用请求携带的 ID
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(HeaderRequestID, "upstream-id")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+	if seen != "upstream-id" {
+		t.Errorf("RequestID() = %q, want upstream-id", seen)
+	}
+
+	// 缺失、过长或含有不安全字符时重新生成
+	invalid := []string{"", strings.Repeat("x", maxRequestIDLen+1), "id with space", "id\u007f", `id"quoted`, "id<script>", "标识"}
+	for _, incoming := range invalid {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if incoming != "" {
+			req.Header.Set(HeaderRequestID, incoming)
+		}
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		if seen != "generated" {
+			t.Errorf("RequestID() = %q, want generated", seen)
+		}
+	}
+	rec.AssertCount(t, len(invalid), logtest.Attr("request_id", "generated"))
+
+	// 常见 ID 格式可以沿用
+	for _, incoming := range []string{"01h455vb4pex5vsknk084sn02q", "0189b1a4-3c5e-7d2a-9f1b-6c8d0e2f4a6b", "user_01h455vb", "aGVsbG8+/w==", "trace:span.1"} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(HeaderRequestID, incoming)
+		h.ServeHTTP(httptest.NewRecorder(), req)
+		if seen != incoming {
+			t.Errorf("RequestID() = %q, want %q", seen, incoming)
+		}
+	}
+}
+
+func TestMiddleware_GeneratorFallback(t *testing.T) {
+	idgen.ResetDefault()
+	t.Cleanup(idgen.ResetDefault)
+
+	// 未设置生成器时拒绝创建，避免回退到固定节点的 Snowflake
+	if _, err := New(nil, Options{}); !errors.Is(err, idgen.ErrNotInitialized) {
+		t.Fatalf("New() without generator error = %v, want ErrNotInitialized", err)
+	}
+
+	if err := idgen.SetDefault(staticGenerator("global")); err != nil {
+		t.Fatal(err)
+	}
+	mw, err := New(nil, Options{})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	var seen string
+	h := mw(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		seen = RequestID(r.Context())
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if seen != "global" {
+		t.Errorf("RequestID() = %q, want ID from idgen.Default()", seen)
+	}
+
+	// 之后清除全局生成器时继续使用创建时的生成器
+	idgen.ResetDefault()
+	seen = ""
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if seen != "global" {
+		t.Errorf("RequestID() after idgen.ResetDefault = %q, want global", seen)
+	}
+}
+
+func TestMiddleware_ContextLogger(t *testing.T) {
+	mw, rec := newMiddleware(t, Options{Generator: staticGenerator("ctx-id")})
+
+	h := mw(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		FromContext(r.Context()).Info("handling", "step", 1)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	rec.AssertLogged(t, logtest.Message("handling"), logtest.Attr("request_id", "ctx-id"), logtest.Attr("step", 1))
+}
+
+func TestFromContext_Default(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	if FromContext(req.Context()) != slog.Default() {
+		t.Error("FromContext() should return slog.Default() without middleware")
+	}
+	if RequestID(req.Context()) != "" {
+		t.Error("RequestID() should be empty without middleware")
+	}
+}
+
+func TestMiddleware_SkipPaths(t *testing.T) {
+	mw, rec := newMiddleware(t, Options{})
+
+	called := 0
+	h := mw(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		called++
+		FromContext(r.Context()).Debug("inside health check")
+	}))
+	for _, path := range DefaultSkipPaths {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	if called != len(DefaultSkipPaths) {
+		t.Errorf("handler called %d times, want %d", called, len(DefaultSkipPaths))
+	}
+	rec.AssertNotLogged(t, logtest.Message(DefaultMessage))
+	rec.AssertCount(t, len(DefaultSkipPaths), logtest.Message("inside health check"))
+
+	// 自定义跳过路径后，默认健康检查路径不再跳过
+	mw, rec = newMiddleware(t, Options{SkipPaths: []string{"/internal/status"}})
+	h = mw(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/internal/status", nil))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	rec.AssertCount(t, 1, logtest.Message(DefaultMessage), logtest.Attr("path", "/healthz"))
+}
+
+func TestMiddleware_RedactsHeaders(t *testing.T) {
+	mw, rec := newMiddleware(t, Options{
+		Headers: []string{"authorization", "Cookie", "X-Api-Key", "Accept", "X-Missing"},
+	})
+	h := mw(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer secret-token")
+	req.Header.Set("Cookie", "session=abc")
+	req.Header.Set("X-Api-Key", "k-123")
+	req.Header.Set("Accept", "application/json")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	rec.AssertLogged(t,
+		logtest.Attr("headers.Authorization", log.DefaultMask),
+		logtest.Attr("headers.Cookie", log.DefaultMask),
+		logtest.Attr("headers.X-Api-Key", log.DefaultMask),
+		logtest.Attr("headers.Accept", "application/json"),
+	)
+	rec.AssertNotLogged(t, logtest.HasAttr("headers.X-Missing"))
+}
+
+func TestMiddleware_CustomRedact(t *testing.T) {
+	mw, rec := newMiddleware(t, Options{
+		Headers: []string{"X-Tenant"},
+		Redact:  &log.RedactOptions{Keywords: []string{"tenant"}, Mask: "[hidden]"},
+	})
+	h := mw(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-Tenant", "acme")
+	h.ServeHTTP(httptest.NewRecorder(), req)
+
+	rec.AssertLogged(t, logtest.Attr("headers.X-Tenant", "[hidden]"))
+
+	if _, err := New(nil, Options{Redact: &log.RedactOptions{Patterns: []string{"("}}}); err == nil {
+		t.Error("New() should reject invalid redaction patterns")
+	}
+}
+
+func TestMiddleware_RemoteIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		trustProxy bool
+		trusted    []netip.Prefix
+		remote     string
+		headers    map[string]string
+		want       string
+	}{
+		{"remote addr", false, nil, "10.0.0.1:1234", map[string]string{"X-Forwarded-For": "1.1.1.1"}, "10.0.0.1"},
+		{"forwarded for", true, nil, "10.0.0.1:1234", map[string]string{"X-Forwarded-For": "1.1.1.1, 10.0.0.2"}, "1.1.1.1"},
+		// 客户端伪造的最左侧地址被忽略，取最右侧不可信的地址
+		{"spoofed leftmost", true, nil, "10.0.0.1:1234", map[string]string{"X-Forwarded-For": "9.9.9.9, 1.1.1.1"}, "1.1.1.1"},
+		{"all hops trusted", true, nil, "10.0.0.1:1234", map[string]string{"X-Forwarded-For": "10.0.0.3, 10.0.0.2"}, "10.0.0.3"},
+		{"untrusted peer", true, nil, "8.8.8.8:1234", map[string]string{"X-Forwarded-For": "1.1.1.1"}, "8.8.8.8"},
+		{"custom trusted", true, []netip.Prefix{netip.MustParsePrefix("8.8.8.0/24")}, "8.8.8.8:1234", map[string]string{"X-Forwarded-For": "1.1.1.1"}, "1.1.1.1"},
+		{"custom excludes private", true, []netip.Prefix{netip.MustParsePrefix("8.8.8.0/24")}, "10.0.0.1:1234", map[string]string{"X-Forwarded-For": "1.1.1.1"}, "10.0.0.1"},
+		{"real ip", true, nil, "10.0.0.1:1234", map[string]string{"X-Real-IP": "3.3.3.3"}, "3.3.3.3"},
+		{"no proxy headers", true, nil, "10.0.0.1:1234", nil, "10.0.0.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mw, rec := newMiddleware(t, Options{TrustProxy: tt.trustProxy, TrustedProxies: tt.trusted})
+			h := mw(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remote
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), req)
+
+			rec.AssertLogged(t, logtest.Attr("remote_ip", tt.want))
+		})
+	}
+}
+
+func TestMiddleware_Flush(t *testing.T) {
+	mw, _ := newMiddleware(t, Options{})
+
+	h := mw(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("chunk"))
+		if err := http.NewResponseController(w).Flush(); err != nil {
+			t.Errorf("Flush() error = %v", err)
+		}
+		if _, ok := w.(http.Flusher); !ok {
+			t.Error("wrapped writer should implement http.Flusher")
+		}
+	}))
+	rr := httptest.NewRecorder()
+	h.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if !rr.Flushed {
+		t.Error("response should be flushed")
+	}
+}
+
+func TestMiddleware_Latency(t *testing.T) {
+	mw, rec := newMiddleware(t, Options{})
+	h := mw(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		time.Sleep(5 * time.Millisecond)
+	}))
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+
+	rec.AssertLogged(t, logtest.Match("latency >= 5ms", func(r logtest.Record) bool {
+		v, ok := r.Attr("latency")
+		return ok && v.Duration() >= 5*time.Millisecond
+	}))
+}
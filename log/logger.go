@@ -1,34 +1,56 @@
 package log
 
 import (
-	"fmt"
+	"errors"
 	"io"
 	"log/slog"
 	"os"
 	"path/filepath"
+	"sync"
+	"sync/atomic"
 )
 
 // Logger 包装 slog.Logger 并管理资源
 // 实现 io.Closer 接口
 type Logger struct {
 	*slog.Logger
-	cleanup func()
+	cleanup func() error
+	async   *AsyncWriter // 异步模式下的写入器，同步模式为 nil
+
+	slowThreshold atomic.Int64 // Timed 的慢操作阈值（纳秒），0 表示默认值
+
+	closeOnce sync.Once
+	closeErr  error
 }
 
 // 确保 Logger 实现 io.Closer 接口
 var _ io.Closer = (*Logger)(nil)
 
 // Close 释放日志资源（如文件句柄）
+// 会先关闭 Handler 链中实现 io.Closer 的中间件，以便输出缓存中的汇总记录，
+// 异步模式下会在 CloseTimeout 内刷新缓冲区。
+// 多次调用只执行一次，返回首次关闭的结果
 func (l *Logger) Close() error {
-	if l.cleanup != nil {
-		l.cleanup()
-	}
-	return nil
+	l.closeOnce.Do(func() {
+		unregister(l)
+		err := closeHandler(l.Handler())
+		if l.cleanup != nil {
+			err = errors.Join(err, l.cleanup())
+		}
+		l.closeErr = err
+	})
+	return l.closeErr
 }
 
 // New 创建 Logger，使用标准库实现
+// 未知的级别或格式返回 ErrInvalidLevel/ErrInvalidFormat，空值使用默认值；
 // 返回的 Logger 需要在应用退出时调用 Close() 释放资源
 func New(opts Options) (*Logger, error) {
+	// 拒绝未知的级别与格式，避免拼写错误被静默忽略
+	if err := opts.validateLevelFormat(); err != nil {
+		return nil, err
+	}
+
 	// 解析级别
 	level := parseLevel(opts.Level)
 
@@ -39,23 +61,105 @@ func New(opts Options) (*Logger, error) {
 	}
 
 	// 选择输出
-	writer, cleanup, err := getWriter(opts.Output)
+	writer, cleanup, err := getWriter(opts)
 	if err != nil {
 		return nil, err
 	}
 
+	// 异步写入：先刷新缓冲区，再释放底层输出
+	var aw *AsyncWriter
+	if opts.Async != nil {
+		if aw, err = NewAsyncWriter(writer, *opts.Async); err != nil {
+			_ = cleanup()
+			return nil, err
+		}
+		writer = aw
+		cleanup = asyncCleanup(aw, cleanup)
+	}
+
 	// 选择格式
+	newFormatter := func(w io.Writer) slog.Handler {
+		switch opts.Format {
+		case FormatJSON:
+			// Duration 统一输出为 "1.5s"，与其他格式及 zap 适配器一致
+			return slog.NewJSONHandler(w, &slog.HandlerOptions{
+				Level:       level,
+				AddSource:   opts.AddCaller,
+				ReplaceAttr: replaceDuration,
+			})
+		case FormatText:
+			return slog.NewTextHandler(w, handlerOpts)
+		case FormatOTel:
+			return NewOTelHandler(w, &OTelHandlerOptions{
+				Level:          level,
+				AddSource:      opts.AddCaller,
+				ServiceName:    opts.AppName,
+				ServiceVersion: opts.AppVersion,
+			})
+		default:
+			return NewConsoleHandler(w, &ConsoleHandlerOptions{
+				Level:     level,
+				AddSource: opts.AddCaller,
+			})
+		}
+	}
+
+	// syslog/journald 等输出需要记录的级别与消息，由 recordHandler 从 slog.Record 传入
 	var handler slog.Handler
-	if opts.Format == FormatJSON {
-		handler = slog.NewJSONHandler(writer, handlerOpts)
+	if rw, ok := writer.(RecordWriter); ok && isRecordWriter(underlyingWriter(writer)) {
+		handler = newRecordHandler(rw, newFormatter)
 	} else {
-		handler = slog.NewTextHandler(writer, handlerOpts)
+		handler = newFormatter(writer)
 	}
 
-	return &Logger{
-		Logger:  slog.New(handler),
-		cleanup: cleanup,
-	}, nil
+	// 挂载中间件
+	handler, err = WrapHandler(handler, opts)
+	if err != nil {
+		_ = cleanup()
+		return nil, err
+	}
+
+	l := NewWithCleanup(handler, cleanup)
+	l.async = aw
+	l.SetSlowThreshold(opts.SlowThreshold)
+	return l, nil
+}
+
+// isRecordWriter 判断 w 是否实现 RecordWriter
+func isRecordWriter(w io.Writer) bool {
+	_, ok := w.(RecordWriter)
+	return ok
+}
+
+// asyncCleanup 返回先刷新 aw、再释放底层输出的 cleanup
+// 刷新超时时后台 goroutine 仍可能在写入，待其写完后再释放底层输出
+func asyncCleanup(aw *AsyncWriter, closeOutput func() error) func() error {
+	return func() error {
+		err := aw.Close()
+		if errors.Is(err, ErrFlushTimeout) {
+			go func() {
+				<-aw.done
+				_ = closeOutput()
+			}()
+			return err
+		}
+		return errors.Join(err, closeOutput())
+	}
+}
+
+// AsyncStats 异步写入的运行状态
+type AsyncStats struct {
+	Dropped  uint64 // 因缓冲区溢出而丢弃的记录数
+	Buffered int    // 缓冲区中尚未写出的记录数
+}
+
+// AsyncStats 返回异步写入的运行状态，可用于监控丢弃的日志
+// 同步模式，以及 zap 适配器（BufferedWriteSyncer 不会丢弃记录）返回零值
+func (l *Logger) AsyncStats() AsyncStats {
+	if l.async == nil {
+		return AsyncStats{}
+	}
+	return AsyncStats{Dropped: l.async.Dropped(), Buffered: l.async.Buffered()}
 }
 
 // NewWithHandler 使用自定义 Handler 创建 Logger
@@ -64,12 +168,60 @@ func NewWithHandler(handler slog.Handler) *slog.Logger {
 	return slog.New(handler)
 }
 
+// NewWithCleanup 使用自定义 Handler 与资源释放函数创建 Logger
+// 返回的 Logger 会被登记，可通过 CloseAll/Exit 统一关闭；
+// 登记只持有弱引用，不再使用的 Logger 仍需调用 Close 释放资源，
+// 未关闭即被回收的 Logger 只会移除登记，其 cleanup 不会执行。
+// zap 适配器通过该函数返回可关闭的 Logger
+func NewWithCleanup(handler slog.Handler, cleanup func() error) *Logger {
+	l := &Logger{
+		Logger:  slog.New(handler),
+		cleanup: cleanup,
+	}
+	register(l)
+	return l
+}
+
+// WrapHandler 按 Options 为 Handler 挂载中间件（如敏感字段遮蔽）
+// log.New 与 zap 适配器共用该函数，保证两条路径行为一致
+//
+// 挂载顺序（由外到内）：去重 → 采样 → 错误展开 → 遮蔽 → 格式化，
+// 被折叠的重复记录不计入采样，被采样丢弃的记录不再做后续处理，
+// 展开后的错误信息同样会被遮蔽
+func WrapHandler(handler slog.Handler, opts Options) (slog.Handler, error) {
+	if opts.Redact != nil {
+		h, err := NewRedactHandler(handler, *opts.Redact)
+		if err != nil {
+			return nil, err
+		}
+		handler = h
+	}
+	if opts.ExpandErrors {
+		handler = NewErrorHandler(handler)
+	}
+	if opts.Sampling != nil {
+		handler = NewSamplingHandler(handler, *opts.Sampling)
+	}
+	if opts.Dedup != nil {
+		handler = NewDedupHandler(handler, *opts.Dedup)
+	}
+	return handler, nil
+}
+
+// closeHandler 若 Handler 实现了 io.Closer 则关闭它
+func closeHandler(h slog.Handler) error {
+	if c, ok := h.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
 // NewNop 返回一个静默的 Logger，所有的日志输出都会被丢弃
 // 常用于单元测试或不想输出日志的场景
 func NewNop() *Logger {
 	return &Logger{
 		Logger:  slog.New(slog.DiscardHandler),
-		cleanup: func() {},
+		cleanup: func() error { return nil },
 	}
 }
 
@@ -81,33 +233,50 @@ var slogLevelMap = map[string]slog.Level{
 	LevelError: slog.LevelError,
 }
 
+// OpenOutput 根据 Output 打开输出目标，文件使用默认权限
+// 返回 writer 与释放资源的 cleanup，供 zap 适配器等复用相同的输出逻辑
+func OpenOutput(output string) (io.Writer, func() error, error) {
+	return getWriter(Options{Output: output})
+}
+
+// OpenOutputWithOptions 按 Options 打开输出目标
+// 展开 Output 中的路径模板，文件与目录使用 FileMode/DirMode
+func OpenOutputWithOptions(opts Options) (io.Writer, func() error, error) {
+	return getWriter(opts)
+}
+
 // getWriter 根据 Output 获取输出目标
+// 支持 stdout、stderr、文件路径（含模板）以及 URL 形式的网络/系统日志输出
 // 返回 writer, cleanup 函数, error
-func getWriter(output string) (io.Writer, func(), error) {
+func getWriter(opts Options) (io.Writer, func() error, error) {
+	output := opts.Output
 	switch output {
 	case OutputStdout, "":
-		return os.Stdout, func() {}, nil
+		return os.Stdout, func() error { return nil }, nil
 	case OutputStderr:
-		return os.Stderr, func() {}, nil
-	default:
-		// 确保目录存在
-		if err := EnsureOutputDir(output); err != nil {
-			return nil, nil, err
-		}
+		return os.Stderr, func() error { return nil }, nil
+	}
 
-		// 打开文件
-		// #nosec G304 -- output 来自配置文件，由用户控制
-		f, err := os.OpenFile(output, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	// URL 形式的网络/系统日志输出
+	if isNetworkOutput(output) {
+		w, err := openNetworkOutput(output)
 		if err != nil {
-			return nil, nil, fmt.Errorf("%w: %w", ErrOpenFile, err)
+			return nil, nil, err
 		}
+		return w, w.Close, nil
+	}
 
-		// 返回文件和 cleanup 函数
-		cleanup := func() {
-			_ = f.Close()
-		}
-		return f, cleanup, nil
+	// 展开路径模板，确保目录存在并打开文件
+	f, err := openFile(ExpandOutput(output, opts.AppName), opts.FileMode, opts.DirMode)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 返回文件和 cleanup 函数
+	cleanup := func() error {
+		return f.Close()
 	}
+	return f, cleanup, nil
 }
 
 // parseLevel 解析日志级别
@@ -120,7 +289,7 @@ func parseLevel(level string) slog.Level {
 
 // isFileOutput 判断是否为文件输出
 func isFileOutput(output string) bool {
-	return output != OutputStdout && output != OutputStderr && output != ""
+	return output != OutputStdout && output != OutputStderr && output != "" && !isNetworkOutput(output)
 }
 
 // EnsureOutputDir 确保日志文件的目录存在
@@ -128,5 +297,5 @@ func EnsureOutputDir(output string) error {
 	if !isFileOutput(output) {
 		return nil
 	}
-	return os.MkdirAll(filepath.Dir(output), 0o750)
+	return os.MkdirAll(filepath.Dir(output), DefaultDirMode)
 }
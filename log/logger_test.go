@@ -213,3 +213,13 @@ func TestEnsureOutputDir(t *testing.T) {
 		})
 	}
 }
+
+// readFile 读取日志文件内容
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read %s: %v", path, err)
+	}
+	return string(data)
+}
@@ -0,0 +1,48 @@
+// Package logtest provides an in-memory recording slog.Handler for tests.
+//
+// Records are captured with resolved attribute values and flattened group
+// keys ("req.id"), so assertions never have to parse formatted output.
+//
+// # Basic Usage
+//
+//	import "github.com/chinayin/gox/log/logtest"
+//
+//	func TestCreateOrder(t *testing.T) {
+//		logger, rec := logtest.New(t)
+//		svc := NewService(logger)
+//
+//		svc.CreateOrder(ctx, 42)
+//
+//		rec.AssertLogged(t, logtest.Level(slog.LevelInfo),
+//			logtest.Message("order created"), logtest.Attr("order_id", 42))
+//		rec.AssertNotLogged(t, logtest.MinLevel(slog.LevelWarn))
+//	}
+//
+// # Middleware
+//
+// NewWithOptions applies the level and middlewares of log.Options
+// (redaction, error expansion, sampling, dedup), so tests observe what the
+// production handler would receive:
+//
+//	logger, rec := logtest.NewWithOptions(t, log.Options{
+//		Level:  log.LevelDebug,
+//		Redact: &log.RedactOptions{},
+//	})
+//
+// # Concurrency
+//
+// The Recorder is safe for concurrent use. Assertions report with t.Errorf and
+// can be called from other goroutines; WaitFor blocks until a matching record
+// arrives, for code that logs asynchronously:
+//
+//	go worker.Run(ctx)
+//	rec.WaitFor(t, time.Second, logtest.Message("worker started"))
+//
+// # Snapshots
+//
+// Snapshot renders all records as logfmt lines with the time fixed to
+// FixedTime and the source reduced to the file name, so the output can be
+// compared against a golden file:
+//
+//	time=2000-01-01T00:00:00.000Z level=INFO msg="order created" source=service.go order_id=42
+package logtest
@@ -0,0 +1,101 @@
+package logtest
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+)
+
+// Matcher 记录匹配条件
+type Matcher struct {
+	desc  string
+	match func(Record) bool
+}
+
+// Match 使用自定义函数创建匹配条件，desc 用于断言失败时的描述
+func Match(desc string, fn func(Record) bool) Matcher {
+	return Matcher{desc: desc, match: fn}
+}
+
+// Level 匹配指定级别
+func Level(level slog.Level) Matcher {
+	return Match("level="+level.String(), func(r Record) bool {
+		return r.Level == level
+	})
+}
+
+// MinLevel 匹配不低于指定级别的记录
+func MinLevel(level slog.Level) Matcher {
+	return Match("level>="+level.String(), func(r Record) bool {
+		return r.Level >= level
+	})
+}
+
+// Message 匹配完整消息
+func Message(msg string) Matcher {
+	return Match(fmt.Sprintf("msg=%q", msg), func(r Record) bool {
+		return r.Message == msg
+	})
+}
+
+// MessageContains 匹配包含 substr 的消息
+func MessageContains(substr string) Matcher {
+	return Match(fmt.Sprintf("msg~%q", substr), func(r Record) bool {
+		return strings.Contains(r.Message, substr)
+	})
+}
+
+// Attr 匹配属性值，嵌套分组使用 "." 连接的完整 key
+// value 与属性值按 slog.AnyValue 规范化后比较，如 Attr("status", 200) 可匹配 slog.Int("status", 200)
+func Attr(key string, value any) Matcher {
+	want := slog.AnyValue(value).Resolve()
+	return Match(fmt.Sprintf("%s=%v", key, want), func(r Record) bool {
+		got, ok := r.Attr(key)
+		return ok && valueEqual(got, want)
+	})
+}
+
+// HasAttr 匹配存在指定 key 的记录
+func HasAttr(key string) Matcher {
+	return Match("has "+key, func(r Record) bool {
+		_, ok := r.Attr(key)
+		return ok
+	})
+}
+
+// valueEqual 比较两个属性值，整数类型忽略有无符号差异
+func valueEqual(a, b slog.Value) bool {
+	if a.Kind() == slog.KindInt64 && b.Kind() == slog.KindUint64 {
+		return a.Int64() >= 0 && uint64(a.Int64()) == b.Uint64()
+	}
+	if a.Kind() == slog.KindUint64 && b.Kind() == slog.KindInt64 {
+		return valueEqual(b, a)
+	}
+	if a.Kind() == slog.KindAny && b.Kind() == slog.KindAny {
+		return reflect.DeepEqual(a.Any(), b.Any())
+	}
+	return a.Equal(b)
+}
+
+// matchAll 是否满足全部条件
+func matchAll(r Record, matchers []Matcher) bool {
+	for _, m := range matchers {
+		if !m.match(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// describe 条件描述，用于断言失败信息
+func describe(matchers []Matcher) string {
+	if len(matchers) == 0 {
+		return "{any}"
+	}
+	descs := make([]string, len(matchers))
+	for i, m := range matchers {
+		descs[i] = m.desc
+	}
+	return "{" + strings.Join(descs, " ") + "}"
+}
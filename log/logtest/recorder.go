@@ -0,0 +1,294 @@
+package logtest
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chinayin/gox/log"
+)
+
+// allLevels 低于任何级别的阈值，用于记录全部级别
+const allLevels = slog.Level(math.MinInt)
+
+// FixedTime Snapshot 输出中使用的固定时间
+var FixedTime = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// Record 捕获的一条日志记录
+type Record struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   []slog.Attr // 已解析并展开分组，分组名以 "." 连接，如 "req.id"
+	PC      uintptr
+}
+
+// Attr 返回指定 key 的属性值，嵌套分组使用 "." 连接的完整 key
+func (r Record) Attr(key string) (slog.Value, bool) {
+	for _, a := range r.Attrs {
+		if a.Key == key {
+			return a.Value, true
+		}
+	}
+	return slog.Value{}, false
+}
+
+// Source 返回调用位置 "file.go:line"，未记录 PC 时返回空字符串
+func (r Record) Source() string {
+	if r.PC == 0 {
+		return ""
+	}
+	frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+	return filepath.Base(frame.File) + ":" + strconv.Itoa(frame.Line)
+}
+
+// Recorder 保存 Handler 捕获的记录，可并发读写
+type Recorder struct {
+	mu      sync.Mutex
+	records []Record
+	notify  chan struct{} // 每次追加记录后关闭并替换，用于 WaitFor
+}
+
+// NewRecorder 创建 Recorder
+func NewRecorder() *Recorder {
+	return &Recorder{notify: make(chan struct{})}
+}
+
+// add 追加记录并唤醒等待者
+func (r *Recorder) add(rec Record) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, rec)
+	close(r.notify)
+	r.notify = make(chan struct{})
+}
+
+// Records 返回已捕获记录的副本
+func (r *Recorder) Records() []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Record(nil), r.records...)
+}
+
+// Len 返回已捕获的记录数
+func (r *Recorder) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.records)
+}
+
+// Reset 清空已捕获的记录
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = nil
+}
+
+// Find 返回满足全部条件的记录
+func (r *Recorder) Find(matchers ...Matcher) []Record {
+	var found []Record
+	for _, rec := range r.Records() {
+		if matchAll(rec, matchers) {
+			found = append(found, rec)
+		}
+	}
+	return found
+}
+
+// Count 返回满足全部条件的记录数
+func (r *Recorder) Count(matchers ...Matcher) int {
+	return len(r.Find(matchers...))
+}
+
+// Has 是否存在满足全部条件的记录
+func (r *Recorder) Has(matchers ...Matcher) bool {
+	return r.Count(matchers...) > 0
+}
+
+// AssertLogged 断言存在满足全部条件的记录
+// 失败时使用 t.Errorf，可在子 goroutine 中调用
+func (r *Recorder) AssertLogged(t testing.TB, matchers ...Matcher) {
+	t.Helper()
+	if !r.Has(matchers...) {
+		t.Errorf("logtest: no record matches %s\nrecorded:\n%s", describe(matchers), r.Snapshot())
+	}
+}
+
+// AssertNotLogged 断言不存在满足全部条件的记录
+// 失败时使用 t.Errorf，可在子 goroutine 中调用
+func (r *Recorder) AssertNotLogged(t testing.TB, matchers ...Matcher) {
+	t.Helper()
+	if r.Has(matchers...) {
+		t.Errorf("logtest: unexpected record matches %s\nrecorded:\n%s", describe(matchers), r.Snapshot())
+	}
+}
+
+// AssertCount 断言满足全部条件的记录数为 want
+// 失败时使用 t.Errorf，可在子 goroutine 中调用
+func (r *Recorder) AssertCount(t testing.TB, want int, matchers ...Matcher) {
+	t.Helper()
+	if got := r.Count(matchers...); got != want {
+		t.Errorf("logtest: %d records match %s, want %d\nrecorded:\n%s", got, describe(matchers), want, r.Snapshot())
+	}
+}
+
+// WaitFor 等待满足全部条件的记录出现，用于断言异步代码的日志
+// 超时返回 false 并通过 t.Errorf 报告，可在子 goroutine 中调用
+func (r *Recorder) WaitFor(t testing.TB, timeout time.Duration, matchers ...Matcher) (Record, bool) {
+	t.Helper()
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		r.mu.Lock()
+		for _, rec := range r.records {
+			if matchAll(rec, matchers) {
+				r.mu.Unlock()
+				return rec, true
+			}
+		}
+		notify := r.notify
+		r.mu.Unlock()
+
+		select {
+		case <-notify:
+		case <-timer.C:
+			t.Errorf("logtest: no record matches %s within %v\nrecorded:\n%s", describe(matchers), timeout, r.Snapshot())
+			return Record{}, false
+		}
+	}
+}
+
+// Snapshot 以确定性的 logfmt 文本输出全部记录，每条一行
+// 时间固定为 FixedTime，调用位置仅保留文件名（不含行号），便于与黄金文件比对
+func (r *Recorder) Snapshot() string {
+	var b strings.Builder
+	h := slog.NewTextHandler(&b, &slog.HandlerOptions{Level: allLevels})
+	for _, rec := range r.Records() {
+		nr := slog.NewRecord(FixedTime, rec.Level, rec.Message, 0)
+		if src := rec.Source(); src != "" {
+			file, _, _ := strings.Cut(src, ":")
+			nr.AddAttrs(slog.String(slog.SourceKey, file))
+		}
+		nr.AddAttrs(rec.Attrs...)
+		_ = h.Handle(context.Background(), nr)
+	}
+	return b.String()
+}
+
+// Handler 将记录写入 Recorder 的 slog.Handler
+type Handler struct {
+	rec    *Recorder
+	level  slog.Leveler
+	prefix string      // 当前分组前缀，如 "req."
+	attrs  []slog.Attr // WithAttrs 预先展开的属性
+}
+
+var _ slog.Handler = (*Handler)(nil)
+
+// NewHandler 创建写入 rec 的 Handler，level 为 nil 时记录全部级别
+func NewHandler(rec *Recorder, level slog.Leveler) *Handler {
+	if level == nil {
+		level = allLevels
+	}
+	return &Handler{rec: rec, level: level}
+}
+
+// Enabled 实现 slog.Handler
+func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle 实现 slog.Handler
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	attrs := append([]slog.Attr(nil), h.attrs...)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = flatten(attrs, h.prefix, a)
+		return true
+	})
+	h.rec.add(Record{
+		Time:    r.Time,
+		Level:   r.Level,
+		Message: r.Message,
+		Attrs:   attrs,
+		PC:      r.PC,
+	})
+	return nil
+}
+
+// WithAttrs 实现 slog.Handler
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	clone := *h
+	clone.attrs = append([]slog.Attr(nil), h.attrs...)
+	for _, a := range attrs {
+		clone.attrs = flatten(clone.attrs, h.prefix, a)
+	}
+	return &clone
+}
+
+// WithGroup 实现 slog.Handler
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	clone := *h
+	clone.prefix = h.prefix + name + "."
+	return &clone
+}
+
+// flatten 解析属性值并将分组展开为带前缀的 key
+func flatten(dst []slog.Attr, prefix string, a slog.Attr) []slog.Attr {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return dst
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		p := prefix
+		if a.Key != "" {
+			p += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			dst = flatten(dst, p, ga)
+		}
+		return dst
+	}
+	return append(dst, slog.Attr{Key: prefix + a.Key, Value: a.Value})
+}
+
+// New 返回记录全部级别的 Logger 及其 Recorder
+// Logger 在测试结束时自动关闭
+func New(t testing.TB) (*log.Logger, *Recorder) {
+	t.Helper()
+	rec := NewRecorder()
+	logger := log.NewWithCleanup(NewHandler(rec, nil), nil)
+	t.Cleanup(func() { _ = logger.Close() })
+	return logger, rec
+}
+
+// NewWithOptions 按 opts 的级别与中间件（遮蔽、错误展开、采样）创建 Logger
+// 格式与输出配置被忽略，记录写入 Recorder；Logger 在测试结束时自动关闭
+func NewWithOptions(t testing.TB, opts log.Options) (*log.Logger, *Recorder) {
+	t.Helper()
+	level := slog.LevelInfo
+	if opts.Level != "" {
+		if err := level.UnmarshalText([]byte(opts.Level)); err != nil {
+			t.Fatalf("logtest: invalid level %q: %v", opts.Level, err)
+		}
+	}
+
+	rec := NewRecorder()
+	handler, err := log.WrapHandler(NewHandler(rec, level), opts)
+	if err != nil {
+		t.Fatalf("logtest: WrapHandler() error = %v", err)
+	}
+	logger := log.NewWithCleanup(handler, nil)
+	t.Cleanup(func() { _ = logger.Close() })
+	return logger, rec
+}
@@ -0,0 +1,226 @@
+package logtest
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chinayin/gox/log"
+)
+
+func TestNew_CapturesRecords(t *testing.T) {
+	logger, rec := New(t)
+
+	logger.Debug("debug message")
+	logger.Info("request handled", "status", 200, "path", "/users")
+	logger.Warn("slow request", slog.Duration("latency", 2*time.Second))
+
+	if got := rec.Len(); got != 3 {
+		t.Fatalf("Len() = %d, want 3", got)
+	}
+
+	rec.AssertLogged(t, Level(slog.LevelDebug), Message("debug message"))
+	rec.AssertLogged(t, Message("request handled"), Attr("status", 200), Attr("path", "/users"))
+	rec.AssertLogged(t, MessageContains("slow"), Attr("latency", 2*time.Second))
+	rec.AssertNotLogged(t, MinLevel(slog.LevelError))
+	rec.AssertCount(t, 2, MinLevel(slog.LevelInfo))
+}
+
+func TestHandler_GroupsAndAttrs(t *testing.T) {
+	logger, rec := New(t)
+
+	logger.With("svc", "api").WithGroup("req").With("id", 7).Info("handled",
+		slog.Group("resp", slog.Int("status", 200)),
+	)
+
+	records := rec.Records()
+	if len(records) != 1 {
+		t.Fatalf("Records() len = %d, want 1", len(records))
+	}
+	for key, want := range map[string]any{"svc": "api", "req.id": 7, "req.resp.status": 200} {
+		if !Attr(key, want).match(records[0]) {
+			t.Errorf("record should have %s=%v, got %v", key, want, records[0].Attrs)
+		}
+	}
+}
+
+func TestRecord_Attr(t *testing.T) {
+	r := Record{Attrs: []slog.Attr{slog.String("a", "1")}}
+
+	if v, ok := r.Attr("a"); !ok || v.String() != "1" {
+		t.Errorf("Attr(a) = %v, %v, want 1, true", v, ok)
+	}
+	if _, ok := r.Attr("b"); ok {
+		t.Error("Attr(b) should not be found")
+	}
+	if src := r.Source(); src != "" {
+		t.Errorf("Source() = %q, want empty without PC", src)
+	}
+}
+
+func TestRecorder_FindAndReset(t *testing.T) {
+	logger, rec := New(t)
+
+	for i := range 3 {
+		logger.Info("tick", "n", i)
+	}
+	logger.Error("boom")
+
+	if got := len(rec.Find(Message("tick"))); got != 3 {
+		t.Errorf("Find(tick) len = %d, want 3", got)
+	}
+	if !rec.Has(Level(slog.LevelError)) || rec.Has(Level(slog.LevelError), HasAttr("n")) {
+		t.Error("Has() should combine matchers with AND")
+	}
+	if got := rec.Count(Match("n is even", func(r Record) bool {
+		v, ok := r.Attr("n")
+		return ok && v.Int64()%2 == 0
+	})); got != 2 {
+		t.Errorf("Count(custom) = %d, want 2", got)
+	}
+
+	rec.Reset()
+	if got := rec.Len(); got != 0 {
+		t.Errorf("Len() after Reset = %d, want 0", got)
+	}
+}
+
+func TestRecorder_Concurrent(t *testing.T) {
+	logger, rec := New(t)
+
+	const workers, perWorker = 8, 50
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range perWorker {
+				logger.Info("work", "worker", w)
+			}
+			rec.AssertLogged(t, Attr("worker", w))
+		}()
+	}
+	wg.Wait()
+
+	rec.AssertCount(t, workers*perWorker, Message("work"))
+}
+
+func TestRecorder_WaitFor(t *testing.T) {
+	logger, rec := New(t)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		logger.Info("ready", "port", 8080)
+	}()
+
+	r, ok := rec.WaitFor(t, time.Second, Message("ready"))
+	if !ok {
+		t.Fatal("WaitFor() should find the record")
+	}
+	if v, _ := r.Attr("port"); v.Int64() != 8080 {
+		t.Errorf("port = %v, want 8080", v)
+	}
+}
+
+func TestRecorder_WaitForTimeout(t *testing.T) {
+	_, rec := New(t)
+
+	ft := &fakeTB{TB: t}
+	if _, ok := rec.WaitFor(ft, 10*time.Millisecond, Message("never")); ok {
+		t.Error("WaitFor() should time out")
+	}
+	if !ft.failed {
+		t.Error("WaitFor() should report the timeout")
+	}
+}
+
+func TestAssertions_ReportFailures(t *testing.T) {
+	logger, rec := New(t)
+	logger.Info("present")
+
+	tests := []struct {
+		name   string
+		assert func(tb testing.TB)
+	}{
+		{"AssertLogged", func(tb testing.TB) { rec.AssertLogged(tb, Message("missing")) }},
+		{"AssertNotLogged", func(tb testing.TB) { rec.AssertNotLogged(tb, Message("present")) }},
+		{"AssertCount", func(tb testing.TB) { rec.AssertCount(tb, 2, Message("present")) }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ft := &fakeTB{TB: t}
+			tt.assert(ft)
+			if !ft.failed {
+				t.Errorf("%s should report a failure", tt.name)
+			}
+			if !strings.Contains(ft.msg, "present") {
+				t.Errorf("failure message should include recorded output, got: %s", ft.msg)
+			}
+		})
+	}
+}
+
+func TestRecorder_Snapshot(t *testing.T) {
+	logger, rec := New(t)
+
+	logger.Info("user created", "id", 42, slog.Group("req", slog.String("method", "POST")))
+	logger.Warn("quota low", "remaining", 3)
+
+	want := "time=2000-01-01T00:00:00.000Z level=INFO msg=\"user created\" source=recorder_test.go id=42 req.method=POST\n" +
+		"time=2000-01-01T00:00:00.000Z level=WARN msg=\"quota low\" source=recorder_test.go remaining=3\n"
+	if got := rec.Snapshot(); got != want {
+		t.Errorf("Snapshot() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestNewWithOptions(t *testing.T) {
+	logger, rec := NewWithOptions(t, log.Options{
+		Level:        log.LevelWarn,
+		Redact:       &log.RedactOptions{},
+		ExpandErrors: true,
+	})
+
+	logger.Info("filtered")
+	logger.Warn("login", "password", "hunter2", "error", errors.New("denied"))
+
+	rec.AssertNotLogged(t, Message("filtered"))
+	rec.AssertLogged(t, Message("login"), Attr("password", log.DefaultMask))
+	rec.AssertLogged(t, Message("login"), Attr("error.msg", "denied"))
+}
+
+func TestNewWithOptions_SamplingSummaryOnClose(t *testing.T) {
+	logger, rec := NewWithOptions(t, log.Options{
+		Sampling: &log.SamplingOptions{Tick: time.Hour, Initial: 1, ReportInterval: time.Hour},
+	})
+
+	for range 5 {
+		logger.Info("repeated")
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	rec.AssertCount(t, 1, Message("repeated"))
+	rec.AssertLogged(t, Message(log.MsgSamplingDropped), Attr("dropped", 4))
+}
+
+// fakeTB 记录失败而不终止测试，用于验证断言本身
+type fakeTB struct {
+	testing.TB
+	mu     sync.Mutex
+	failed bool
+	msg    string
+}
+
+func (f *fakeTB) Helper() {}
+
+func (f *fakeTB) Errorf(format string, args ...any) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.failed = true
+	f.msg = fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,282 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// URL 形式的输出目标
+//
+//	syslog://                  本机 syslog（/dev/log），RFC 5424
+//	syslog://host:514          远程 syslog（UDP），RFC 5424
+//	unix:///dev/log            指定 unix socket 的 syslog，RFC 5424
+//	journald                   systemd-journald 原生协议
+//	journald:///path/socket    指定 journald socket
+//	tcp://host:port            按行分隔的日志流（建议配合 FormatJSON）
+//	udp://host:port            每条记录一个数据报
+//
+// syslog/unix 支持查询参数 facility（如 local0，默认 user）与 tag（默认进程名）。
+const (
+	SchemeSyslog   = "syslog"
+	SchemeUnix     = "unix"
+	SchemeJournald = "journald"
+	SchemeTCP      = "tcp"
+	SchemeUDP      = "udp"
+)
+
+// 默认 socket 路径
+const (
+	DefaultSyslogSocket   = "/dev/log"
+	DefaultJournaldSocket = "/run/systemd/journal/socket"
+)
+
+// 网络输出的重连参数
+const (
+	netDialTimeout  = 3 * time.Second
+	netWriteTimeout = 3 * time.Second
+	netBackoffMin   = 100 * time.Millisecond
+	netBackoffMax   = 30 * time.Second
+)
+
+// isNetworkOutput 判断是否为 URL 形式的网络/系统日志输出
+func isNetworkOutput(output string) bool {
+	if output == SchemeJournald {
+		return true
+	}
+	scheme, _, ok := strings.Cut(output, "://")
+	if !ok {
+		return false
+	}
+	switch scheme {
+	case SchemeSyslog, SchemeUnix, SchemeJournald, SchemeTCP, SchemeUDP:
+		return true
+	default:
+		return false
+	}
+}
+
+// openNetworkOutput 按 URL 打开网络/系统日志输出
+func openNetworkOutput(output string) (*netWriter, error) {
+	if output == SchemeJournald {
+		output = SchemeJournald + "://"
+	}
+	u, err := url.Parse(output)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %q (%w)", ErrInvalidOutput, output, err)
+	}
+
+	switch u.Scheme {
+	case SchemeSyslog:
+		frame, err := newSyslogFramer(u.Query())
+		if err != nil {
+			return nil, err
+		}
+		if u.Host == "" {
+			return newNetWriter("unixgram", DefaultSyslogSocket, frame), nil
+		}
+		return newNetWriter("udp", u.Host, frame), nil
+	case SchemeUnix:
+		if u.Path == "" {
+			return nil, fmt.Errorf("%w: %q (missing socket path)", ErrInvalidOutput, output)
+		}
+		frame, err := newSyslogFramer(u.Query())
+		if err != nil {
+			return nil, err
+		}
+		return newNetWriter("unixgram", u.Path, frame), nil
+	case SchemeJournald:
+		path := u.Path
+		if path == "" {
+			path = DefaultJournaldSocket
+		}
+		return newNetWriter("unixgram", path, newJournaldFramer(u.Query())), nil
+	case SchemeTCP, SchemeUDP:
+		if u.Host == "" {
+			return nil, fmt.Errorf("%w: %q (missing host)", ErrInvalidOutput, output)
+		}
+		return newNetWriter(u.Scheme, u.Host, frameLine), nil
+	default:
+		return nil, fmt.Errorf("%w: %q (unsupported scheme)", ErrInvalidOutput, output)
+	}
+}
+
+// frameLine 确保每条记录以换行结尾
+func frameLine(_ slog.Level, _ string, line []byte) []byte {
+	if bytes.HasSuffix(line, []byte{'\n'}) {
+		return line
+	}
+	return append(line[:len(line):len(line)], '\n')
+}
+
+// RecordWriter 需要记录元数据编码输出的 writer，如 syslog 与 journald
+//
+// log.New 与 zap 适配器通过 WriteRecord 传入 slog.Record 的级别与消息，
+// 不必再从格式化后的文本中解析；直接调用 Write 时按 info 级别、以整行作为消息处理
+type RecordWriter interface {
+	io.Writer
+	// WriteRecord 写入一条已格式化的记录 line，level 与 msg 取自原始记录
+	WriteRecord(level slog.Level, msg string, line []byte) (int, error)
+}
+
+// recordHandler 将格式化结果连同 slog.Record 的级别与消息交给 RecordWriter
+//
+// 格式化 Handler 写入共享的缓冲区，Handle 在锁内完成格式化与写出，
+// WithAttrs/WithGroup 派生的 Handler 共用同一缓冲区与锁
+type recordHandler struct {
+	next  slog.Handler
+	state *recordState
+}
+
+// recordState recordHandler 及其派生 Handler 共享的状态
+type recordState struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+	out RecordWriter
+}
+
+// newRecordHandler 创建 recordHandler
+// newFormatter 以共享缓冲区为输出创建格式化 Handler
+func newRecordHandler(out RecordWriter, newFormatter func(io.Writer) slog.Handler) *recordHandler {
+	state := &recordState{out: out}
+	return &recordHandler{next: newFormatter(&state.buf), state: state}
+}
+
+// Enabled 由格式化 Handler 决定
+func (h *recordHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle 格式化记录并按记录的级别与消息写出
+func (h *recordHandler) Handle(ctx context.Context, r slog.Record) error {
+	h.state.mu.Lock()
+	defer h.state.mu.Unlock()
+
+	h.state.buf.Reset()
+	if err := h.next.Handle(ctx, r); err != nil {
+		return err
+	}
+	_, err := h.state.out.WriteRecord(r.Level, r.Message, h.state.buf.Bytes())
+	return err
+}
+
+// WithAttrs 派生的 Handler 共享缓冲区与输出
+func (h *recordHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &recordHandler{next: h.next.WithAttrs(attrs), state: h.state}
+}
+
+// WithGroup 派生的 Handler 共享缓冲区与输出
+func (h *recordHandler) WithGroup(name string) slog.Handler {
+	return &recordHandler{next: h.next.WithGroup(name), state: h.state}
+}
+
+// netWriter 带断线重连与指数退避的网络输出
+//
+// 每次 Write/WriteRecord 视为一条记录，经 frame 编码后写入连接。写入失败时断开连接，
+// 并在退避时间到达后重连；退避期间的记录直接丢弃并返回 ErrOutputUnavailable。
+type netWriter struct {
+	network string
+	addr    string
+	frame   recordFramer
+
+	mu        sync.Mutex
+	conn      net.Conn
+	backoff   time.Duration
+	nextRetry time.Time
+	closed    bool
+}
+
+var (
+	_ io.WriteCloser = (*netWriter)(nil)
+	_ RecordWriter   = (*netWriter)(nil)
+)
+
+// newNetWriter 创建网络输出，连接在首次写入时建立
+func newNetWriter(network, addr string, frame recordFramer) *netWriter {
+	return &netWriter{network: network, addr: addr, frame: frame}
+}
+
+// Write 写入一条没有元数据的记录，按 info 级别、以整行作为消息编码
+func (w *netWriter) Write(p []byte) (int, error) {
+	return w.WriteRecord(slog.LevelInfo, string(bytes.TrimRight(p, "\n")), p)
+}
+
+// WriteRecord 按记录的级别与消息编码并写入一条记录，失败时重连一次
+func (w *netWriter) WriteRecord(level slog.Level, msg string, p []byte) (int, error) {
+	frame := w.frame(level, msg, p)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+
+	var err error
+	for range 2 {
+		if err = w.connect(); err != nil {
+			return 0, err
+		}
+		_ = w.conn.SetWriteDeadline(time.Now().Add(netWriteTimeout))
+		if _, err = w.conn.Write(frame); err == nil {
+			return len(p), nil
+		}
+		w.disconnect()
+	}
+	return 0, fmt.Errorf("%w: %s://%s (%w)", ErrOutputUnavailable, w.network, w.addr, err)
+}
+
+// Close 关闭连接
+func (w *netWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
+
+// connect 建立连接，退避期间直接返回错误
+func (w *netWriter) connect() error {
+	if w.conn != nil {
+		return nil
+	}
+	if time.Now().Before(w.nextRetry) {
+		return fmt.Errorf("%w: %s://%s (reconnecting)", ErrOutputUnavailable, w.network, w.addr)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), netDialTimeout)
+	defer cancel()
+
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, w.network, w.addr)
+	if err != nil {
+		w.backoff = min(max(w.backoff*2, netBackoffMin), netBackoffMax)
+		w.nextRetry = time.Now().Add(w.backoff)
+		return fmt.Errorf("%w: %s://%s (%w)", ErrOutputUnavailable, w.network, w.addr, err)
+	}
+
+	w.conn = conn
+	w.backoff = 0
+	w.nextRetry = time.Time{}
+	return nil
+}
+
+// disconnect 断开连接，下次写入时重连
+func (w *netWriter) disconnect() {
+	if w.conn != nil {
+		_ = w.conn.Close()
+		w.conn = nil
+	}
+}
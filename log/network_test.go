@@ -0,0 +1,353 @@
+package log
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// listenUnixgram 在临时目录创建 unixgram 监听
+func listenUnixgram(t *testing.T, name string) (string, *net.UnixConn) {
+	t.Helper()
+	path := t.TempDir() + "/" + name
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram not supported: %v", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return path, conn
+}
+
+// readPacket 读取一个数据报
+func readPacket(t *testing.T, conn net.PacketConn) []byte {
+	t.Helper()
+	buf := make([]byte, 64*1024)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom() error = %v", err)
+	}
+	return buf[:n]
+}
+
+func TestIsNetworkOutput(t *testing.T) {
+	tests := []struct {
+		output string
+		want   bool
+	}{
+		{"syslog://", true},
+		{"syslog://127.0.0.1:514", true},
+		{"unix:///dev/log", true},
+		{"journald", true},
+		{"tcp://collector:5170", true},
+		{"udp://collector:5170", true},
+		{"runtime/log/app.log", false},
+		{"/var/log/app.log", false},
+		{OutputStdout, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.output, func(t *testing.T) {
+			if got := isNetworkOutput(tt.output); got != tt.want {
+				t.Errorf("isNetworkOutput(%q) = %v, want %v", tt.output, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenNetworkOutput_Invalid(t *testing.T) {
+	for _, output := range []string{"tcp://", "unix://", "syslog://?facility=nope"} {
+		if _, err := openNetworkOutput(output); !errors.Is(err, ErrInvalidOutput) {
+			t.Errorf("openNetworkOutput(%q) error = %v, want ErrInvalidOutput", output, err)
+		}
+	}
+}
+
+func TestNew_SyslogUnix(t *testing.T) {
+	path, conn := listenUnixgram(t, "log.sock")
+
+	logger, err := New(Options{
+		Level:  LevelInfo,
+		Format: FormatJSON,
+		Output: "unix://" + path + "?facility=local0&tag=myapp",
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer logger.Close()
+
+	logger.Warn("disk almost full", "usage", 91)
+
+	msg := string(readPacket(t, conn))
+	// local0(16)*8 + warning(4) = 132
+	if !strings.HasPrefix(msg, "<132>1 ") {
+		t.Errorf("syslog message should start with PRI and version, got: %q", msg)
+	}
+	if !strings.Contains(msg, " myapp ") || !strings.Contains(msg, `"msg":"disk almost full"`) {
+		t.Errorf("syslog message should contain tag and record, got: %q", msg)
+	}
+	if strings.HasSuffix(msg, "\n") {
+		t.Errorf("syslog message should not end with newline, got: %q", msg)
+	}
+}
+
+func TestSyslogHeaderField(t *testing.T) {
+	tests := []struct {
+		in     string
+		maxLen int
+		want   string
+	}{
+		{"myapp", 48, "myapp"},
+		{"my app", 48, "my_app"},
+		{"app\x00\n", 48, "app__"},
+		{"服务", 48, "______"},
+		{"", 48, "-"},
+		{strings.Repeat("a", 60), 48, strings.Repeat("a", 48)},
+	}
+	for _, tt := range tests {
+		if got := syslogHeaderField(tt.in, tt.maxLen); got != tt.want {
+			t.Errorf("syslogHeaderField(%q, %d) = %q, want %q", tt.in, tt.maxLen, got, tt.want)
+		}
+	}
+}
+
+func TestNew_SyslogTagSanitized(t *testing.T) {
+	path, conn := listenUnixgram(t, "log.sock")
+
+	logger, err := New(Options{
+		Level:  LevelInfo,
+		Format: FormatJSON,
+		Output: "unix://" + path + "?tag=" + url.QueryEscape("my app"),
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("started")
+
+	// 头部字段以空格分隔：PRI+VERSION TIMESTAMP HOSTNAME APP-NAME PROCID MSGID SD
+	fields := strings.SplitN(string(readPacket(t, conn)), " ", 8)
+	if len(fields) < 8 || fields[3] != "my_app" {
+		t.Errorf("APP-NAME = %q, want my_app", fields)
+	}
+}
+
+func TestNew_Journald(t *testing.T) {
+	path, conn := listenUnixgram(t, "journal.sock")
+
+	logger, err := New(Options{
+		Level:  LevelInfo,
+		Format: FormatConsole,
+		Output: "journald://" + path + "?tag=myapp",
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer logger.Close()
+
+	logger.Error("request failed", "err", errors.New("line1\nline2"))
+
+	pkt := readPacket(t, conn)
+	prefix := "PRIORITY=3\nSYSLOG_IDENTIFIER=myapp\nMESSAGE=request failed\nRECORD\n"
+	if !bytes.HasPrefix(pkt, []byte(prefix)) {
+		t.Fatalf("unexpected journald fields: %q", pkt)
+	}
+	rest := pkt[len(prefix):]
+	size := binary.LittleEndian.Uint64(rest[:8])
+	value := rest[8 : 8+size]
+	if !bytes.Contains(value, []byte("request failed")) || !bytes.Contains(value, []byte("line2")) {
+		t.Errorf("multi-line RECORD should be binary encoded, got: %q", value)
+	}
+}
+
+func TestNew_SyslogSeverityFromRecord(t *testing.T) {
+	tests := []struct {
+		name   string
+		format string
+		log    func(l *Logger)
+		pri    string
+	}{
+		// 消息中的级别文本不影响 severity
+		{"text in message", FormatText, func(l *Logger) { l.Info("got level=ERROR from upstream") }, "<134>1 "},
+		{"otel error", FormatOTel, func(l *Logger) { l.Error("failed") }, "<131>1 "},
+		{"console debug", FormatConsole, func(l *Logger) { l.Debug("trace") }, "<135>1 "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, conn := listenUnixgram(t, "log.sock")
+			logger, err := New(Options{
+				Level:  LevelDebug,
+				Format: tt.format,
+				Output: "unix://" + path + "?facility=local0",
+			})
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			defer logger.Close()
+
+			tt.log(logger)
+			if msg := string(readPacket(t, conn)); !strings.HasPrefix(msg, tt.pri) {
+				t.Errorf("syslog message should start with %q, got: %q", tt.pri, msg)
+			}
+		})
+	}
+}
+
+func TestNew_JournaldAsync(t *testing.T) {
+	path, conn := listenUnixgram(t, "journal.sock")
+
+	logger, err := New(Options{
+		Level:  LevelInfo,
+		Format: FormatJSON,
+		Output: "journald://" + path + "?tag=myapp",
+		Async:  &AsyncOptions{},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer logger.Close()
+
+	logger.With("user", "alice").Warn("quota exceeded")
+
+	pkt := string(readPacket(t, conn))
+	if !strings.HasPrefix(pkt, "PRIORITY=4\nSYSLOG_IDENTIFIER=myapp\nMESSAGE=quota exceeded\nRECORD=") {
+		t.Errorf("async journald record should keep level and message, got: %q", pkt)
+	}
+	if !strings.Contains(pkt, `"user":"alice"`) {
+		t.Errorf("RECORD should contain the formatted attrs, got: %q", pkt)
+	}
+}
+
+func TestNetWriter_WriteWithoutRecord(t *testing.T) {
+	path, conn := listenUnixgram(t, "journal.sock")
+
+	w := newNetWriter("unixgram", path, newJournaldFramer(nil))
+	defer w.Close()
+
+	if _, err := w.Write([]byte("plain line\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if pkt := string(readPacket(t, conn)); !strings.HasPrefix(pkt, "PRIORITY=6\n") || !strings.Contains(pkt, "MESSAGE=plain line\n") {
+		t.Errorf("plain Write should be framed as info with the line as message, got: %q", pkt)
+	}
+}
+
+func TestNew_UDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket() error = %v", err)
+	}
+	defer conn.Close()
+
+	logger, err := New(Options{Level: LevelInfo, Format: FormatJSON, Output: "udp://" + conn.LocalAddr().String()})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("over udp")
+
+	if pkt := string(readPacket(t, conn)); !strings.Contains(pkt, `"msg":"over udp"`) || !strings.HasSuffix(pkt, "\n") {
+		t.Errorf("udp record should be newline-terminated JSON, got: %q", pkt)
+	}
+}
+
+func TestNew_TCPReconnect(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	addr := ln.Addr().String()
+
+	logger, err := New(Options{Level: LevelInfo, Format: FormatJSON, Output: "tcp://" + addr})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("first")
+	conn, err := ln.Accept()
+	if err != nil {
+		t.Fatalf("Accept() error = %v", err)
+	}
+	line, err := bufio.NewReader(conn).ReadString('\n')
+	if err != nil || !strings.Contains(line, `"msg":"first"`) {
+		t.Fatalf("first record = %q, err = %v", line, err)
+	}
+
+	// 模拟采集端重启
+	_ = conn.Close()
+	_ = ln.Close()
+	ln, err = net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("cannot rebind %s: %v", addr, err)
+	}
+	defer ln.Close()
+
+	received := make(chan string, 1)
+	go func() {
+		c, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer c.Close()
+		r := bufio.NewReader(c)
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if strings.Contains(line, `"msg":"after restart"`) {
+				received <- line
+				return
+			}
+		}
+	}()
+
+	deadline := time.After(3 * time.Second)
+	for {
+		logger.Info("after restart")
+		select {
+		case <-received:
+			return
+		case <-deadline:
+			t.Fatal("logger did not reconnect to restarted collector")
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+}
+
+func TestNetWriter_Backoff(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen() error = %v", err)
+	}
+	addr := ln.Addr().String()
+	_ = ln.Close()
+
+	w := newNetWriter("tcp", addr, frameLine)
+	defer w.Close()
+
+	if _, err := w.Write([]byte("x\n")); !errors.Is(err, ErrOutputUnavailable) {
+		t.Fatalf("Write() error = %v, want ErrOutputUnavailable", err)
+	}
+	if w.backoff != netBackoffMin {
+		t.Errorf("backoff = %v, want %v", w.backoff, netBackoffMin)
+	}
+
+	// 退避期间不再拨号
+	if _, err := w.Write([]byte("x\n")); !errors.Is(err, ErrOutputUnavailable) || !strings.Contains(err.Error(), "reconnecting") {
+		t.Errorf("Write() during backoff error = %v", err)
+	}
+	if w.backoff != netBackoffMin {
+		t.Errorf("backoff should not grow without dialing, got %v", w.backoff)
+	}
+}
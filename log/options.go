@@ -1,5 +1,10 @@
 package log
 
+import (
+	"os"
+	"time"
+)
+
 // Level 日志级别
 const (
 	LevelDebug = "debug"
@@ -11,7 +16,9 @@ const (
 // Format 日志格式
 const (
 	FormatJSON    = "json"
-	FormatConsole = "console"
+	FormatConsole = "console" // 彩色控制台格式，面向本地开发
+	FormatText    = "text"    // logfmt 格式（key=value）
+	FormatOTel    = "otel"    // OpenTelemetry 日志数据模型（OTLP-JSON 结构），便于 Collector 直接采集
 )
 
 // Output 输出目标
@@ -22,19 +29,46 @@ const (
 )
 
 // Options 日志配置选项
+// 可直接嵌入配置结构体，由 config.Loader 解析（snake_case key）
 type Options struct {
-	Level     string // 日志级别: debug, info, warn, error
-	Format    string // 日志格式: json, console
-	Output    string // 输出目标: stdout, stderr, /path/to/file
-	AddCaller bool   // 是否添加调用位置信息，默认 true
+	Level     string `default:"info" mapstructure:"level" validate:"omitempty,oneof=debug info warn error" yaml:"level"`       // 日志级别: debug, info, warn, error
+	Format    string `default:"console" mapstructure:"format" validate:"omitempty,oneof=json console text otel" yaml:"format"` // 日志格式: json, console, text, otel
+	Output    string `default:"stdout" mapstructure:"output" yaml:"output"`                                                    // 输出目标: stdout, stderr, /path/to/file, syslog://, journald, tcp://host:port 等，文件路径支持 {app} {hostname} {pid} {date} 模板
+	AddCaller bool   `default:"true" mapstructure:"add_caller" yaml:"add_caller"`                                              // 是否添加调用位置信息，默认 true
+
+	FileMode os.FileMode `default:"0600" mapstructure:"file_mode" yaml:"file_mode"` // 日志文件权限（受 umask 影响），默认 0600
+	DirMode  os.FileMode `default:"0750" mapstructure:"dir_mode" yaml:"dir_mode"`   // 自动创建的日志目录权限（受 umask 影响），默认 0750
+
+	Redact   *RedactOptions   `mapstructure:"redact" yaml:"redact"`     // 敏感字段遮蔽，nil 表示不启用
+	Sampling *SamplingOptions `mapstructure:"sampling" yaml:"sampling"` // 日志采样，nil 表示不启用
+	Dedup    *DedupOptions    `mapstructure:"dedup" yaml:"dedup"`       // 重复日志折叠，nil 表示不启用
+	Async    *AsyncOptions    `mapstructure:"async" yaml:"async"`       // 异步缓冲写入，nil 表示同步写入
+
+	ExpandErrors bool `mapstructure:"expand_errors" yaml:"expand_errors"` // 将 error 属性展开为结构化错误链（含包装堆栈与哨兵名称）
+
+	SlowThreshold time.Duration `default:"1s" mapstructure:"slow_threshold" yaml:"slow_threshold"` // Timed 的慢操作阈值，超过时以 warn 级别输出，默认 1s
+
+	AppName    string `mapstructure:"app_name" yaml:"app_name"`       // 应用名称，FormatOTel 输出为资源属性 service.name
+	AppVersion string `mapstructure:"app_version" yaml:"app_version"` // 应用版本，FormatOTel 输出为资源属性 service.version
+}
+
+// WithApp 返回填充了应用名称与版本的配置副本
+// 可直接传入 cli.CommandAdapter，与 cli.NewStartup 使用同一份应用信息
+func (o Options) WithApp(info AppInfo) Options {
+	o.AppName = info.GetName()
+	o.AppVersion = info.GetVersion()
+	return o
 }
 
 // DefaultOptions 返回默认配置
 func DefaultOptions() Options {
 	return Options{
-		Level:     LevelInfo,
-		Format:    FormatConsole,
-		Output:    OutputStdout,
-		AddCaller: true,
+		Level:         LevelInfo,
+		Format:        FormatConsole,
+		Output:        OutputStdout,
+		AddCaller:     true,
+		FileMode:      DefaultFileMode,
+		DirMode:       DefaultDirMode,
+		SlowThreshold: DefaultSlowThreshold,
 	}
 }
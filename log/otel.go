@@ -0,0 +1,345 @@
+package log
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// OpenTelemetry 资源属性 key（语义约定）
+const (
+	OTelServiceName    = "service.name"
+	OTelServiceVersion = "service.version"
+)
+
+// AppInfo 应用信息，cli.CommandAdapter 满足该接口
+type AppInfo interface {
+	GetName() string
+	GetVersion() string
+}
+
+// TraceExtractor 从 context 中提取 trace/span ID（十六进制字符串）
+type TraceExtractor func(ctx context.Context) (traceID, spanID string)
+
+// traceKey context 中 trace 信息的 key
+type traceKey struct{}
+
+// traceInfo context 中保存的 trace 信息
+type traceInfo struct {
+	traceID string
+	spanID  string
+}
+
+// defaultTraceExtractor 全局 trace 提取函数
+var defaultTraceExtractor atomic.Pointer[TraceExtractor]
+
+// ContextWithTrace 返回携带 trace/span ID 的 context
+// 未接入 OpenTelemetry SDK 时可直接使用；接入后推荐通过 SetTraceExtractor 读取 SDK 的 SpanContext
+func ContextWithTrace(ctx context.Context, traceID, spanID string) context.Context {
+	return context.WithValue(ctx, traceKey{}, traceInfo{traceID: traceID, spanID: spanID})
+}
+
+// TraceFromContext 读取 ContextWithTrace 写入的 trace/span ID
+func TraceFromContext(ctx context.Context) (traceID, spanID string) {
+	if ctx == nil {
+		return "", ""
+	}
+	if ti, ok := ctx.Value(traceKey{}).(traceInfo); ok {
+		return ti.traceID, ti.spanID
+	}
+	return "", ""
+}
+
+// SetTraceExtractor 设置全局 trace 提取函数，nil 表示恢复为 TraceFromContext
+//
+// 例如接入 OpenTelemetry SDK：
+//
+//	log.SetTraceExtractor(func(ctx context.Context) (string, string) {
+//		sc := trace.SpanContextFromContext(ctx)
+//		return sc.TraceID().String(), sc.SpanID().String()
+//	})
+func SetTraceExtractor(fn TraceExtractor) {
+	if fn == nil {
+		defaultTraceExtractor.Store(nil)
+		return
+	}
+	defaultTraceExtractor.Store(&fn)
+}
+
+// extractTrace 使用全局 trace 提取函数
+func extractTrace(ctx context.Context) (traceID, spanID string) {
+	if fn := defaultTraceExtractor.Load(); fn != nil {
+		return (*fn)(ctx)
+	}
+	return TraceFromContext(ctx)
+}
+
+// OTelHandlerOptions OTel Handler 配置
+type OTelHandlerOptions struct {
+	Level          slog.Leveler   // 最低级别，默认 info
+	AddSource      bool           // 是否输出 code.* 属性
+	ServiceName    string         // 资源属性 service.name
+	ServiceVersion string         // 资源属性 service.version
+	TraceExtractor TraceExtractor // trace 提取函数，默认使用 SetTraceExtractor 设置的全局函数
+}
+
+// OTelHandler 按 OpenTelemetry 日志数据模型输出 OTLP-JSON 结构的记录
+//
+// 每条记录一行，包含 resource、timeUnixNano、severityNumber、severityText、
+// body、attributes，以及 context 中的 traceId/spanId。
+type OTelHandler struct {
+	opts     OTelHandlerOptions
+	resource []byte
+	goas     []groupOrAttrs
+	mu       *sync.Mutex
+	w        io.Writer
+}
+
+// groupOrAttrs WithGroup/WithAttrs 的调用记录
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+var _ slog.Handler = (*OTelHandler)(nil)
+
+// NewOTelHandler 创建 OTel Handler
+func NewOTelHandler(w io.Writer, opts *OTelHandlerOptions) *OTelHandler {
+	var o OTelHandlerOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Level == nil {
+		o.Level = slog.LevelInfo
+	}
+	if o.TraceExtractor == nil {
+		o.TraceExtractor = extractTrace
+	}
+
+	var res []slog.Attr
+	if o.ServiceName != "" {
+		res = append(res, slog.String(OTelServiceName, o.ServiceName))
+	}
+	if o.ServiceVersion != "" {
+		res = append(res, slog.String(OTelServiceVersion, o.ServiceVersion))
+	}
+	resource := []byte(`{"attributes":`)
+	resource = appendOTelAttrs(resource, res)
+	resource = append(resource, '}')
+
+	return &OTelHandler{
+		opts:     o,
+		resource: resource,
+		mu:       &sync.Mutex{},
+		w:        w,
+	}
+}
+
+// Enabled 实现 slog.Handler
+func (h *OTelHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.opts.Level.Level()
+}
+
+// Handle 实现 slog.Handler
+func (h *OTelHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	attrs = h.nestAttrs(attrs)
+
+	if h.opts.AddSource && r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		attrs = append(attrs,
+			slog.String("code.filepath", frame.File),
+			slog.Int("code.lineno", frame.Line),
+			slog.String("code.function", frame.Function),
+		)
+	}
+
+	ts := r.Time
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	b := make([]byte, 0, 256)
+	b = append(b, `{"resource":`...)
+	b = append(b, h.resource...)
+	b = append(b, `,"timeUnixNano":"`...)
+	b = strconv.AppendInt(b, ts.UnixNano(), 10)
+	b = append(b, `","observedTimeUnixNano":"`...)
+	b = strconv.AppendInt(b, time.Now().UnixNano(), 10)
+	b = append(b, `","severityNumber":`...)
+	b = strconv.AppendInt(b, int64(otelSeverityNumber(r.Level)), 10)
+	b = append(b, `,"severityText":`...)
+	b = strconv.AppendQuote(b, r.Level.String())
+	b = append(b, `,"body":{"stringValue":`...)
+	b = appendJSONString(b, r.Message)
+	b = append(b, `},"attributes":`...)
+	b = appendOTelAttrs(b, attrs)
+
+	if traceID, spanID := h.opts.TraceExtractor(ctx); traceID != "" {
+		b = append(b, `,"traceId":`...)
+		b = appendJSONString(b, traceID)
+		if spanID != "" {
+			b = append(b, `,"spanId":`...)
+			b = appendJSONString(b, spanID)
+		}
+	}
+	b = append(b, "}\n"...)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(b)
+	return err
+}
+
+// WithAttrs 实现 slog.Handler
+func (h *OTelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.with(groupOrAttrs{attrs: attrs})
+}
+
+// WithGroup 实现 slog.Handler
+func (h *OTelHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(groupOrAttrs{group: name})
+}
+
+func (h *OTelHandler) with(goa groupOrAttrs) *OTelHandler {
+	clone := *h
+	clone.goas = make([]groupOrAttrs, len(h.goas)+1)
+	copy(clone.goas, h.goas)
+	clone.goas[len(h.goas)] = goa
+	return &clone
+}
+
+// nestAttrs 按 WithGroup/WithAttrs 的调用顺序，将记录属性嵌套到分组中
+func (h *OTelHandler) nestAttrs(attrs []slog.Attr) []slog.Attr {
+	for i := len(h.goas) - 1; i >= 0; i-- {
+		goa := h.goas[i]
+		if goa.group != "" {
+			if len(attrs) == 0 {
+				continue
+			}
+			attrs = []slog.Attr{{Key: goa.group, Value: slog.GroupValue(attrs...)}}
+			continue
+		}
+		attrs = append(append([]slog.Attr{}, goa.attrs...), attrs...)
+	}
+	return attrs
+}
+
+// otelSeverityNumber slog 级别映射为 OTel SeverityNumber
+// DEBUG=5, INFO=9, WARN=13, ERROR=17，中间级别按偏移量映射并限制在 1..24
+func otelSeverityNumber(level slog.Level) int {
+	return min(max(9+int(level), 1), 24)
+}
+
+// appendOTelAttrs 输出 OTLP KeyValue 数组
+func appendOTelAttrs(b []byte, attrs []slog.Attr) []byte {
+	b = append(b, '[')
+	first := true
+	for _, a := range attrs {
+		a.Value = a.Value.Resolve()
+		if a.Equal(slog.Attr{}) {
+			continue
+		}
+		// 空 key 的分组内联展开
+		if a.Key == "" && a.Value.Kind() == slog.KindGroup {
+			inner := appendOTelAttrs(nil, a.Value.Group())
+			if len(inner) > 2 {
+				if !first {
+					b = append(b, ',')
+				}
+				b = append(b, inner[1:len(inner)-1]...)
+				first = false
+			}
+			continue
+		}
+		if !first {
+			b = append(b, ',')
+		}
+		first = false
+		b = append(b, `{"key":`...)
+		b = appendJSONString(b, a.Key)
+		b = append(b, `,"value":`...)
+		b = appendOTelValue(b, a.Value)
+		b = append(b, '}')
+	}
+	return append(b, ']')
+}
+
+// appendOTelValue 输出 OTLP AnyValue
+func appendOTelValue(b []byte, v slog.Value) []byte {
+	switch v.Kind() {
+	case slog.KindString:
+		b = append(b, `{"stringValue":`...)
+		b = appendJSONString(b, v.String())
+	case slog.KindInt64:
+		b = append(b, `{"intValue":"`...)
+		b = strconv.AppendInt(b, v.Int64(), 10)
+		b = append(b, '"')
+	case slog.KindUint64:
+		b = append(b, `{"intValue":"`...)
+		b = strconv.AppendUint(b, v.Uint64(), 10)
+		b = append(b, '"')
+	case slog.KindFloat64:
+		b = append(b, `{"doubleValue":`...)
+		b = strconv.AppendFloat(b, v.Float64(), 'g', -1, 64)
+	case slog.KindBool:
+		b = append(b, `{"boolValue":`...)
+		b = strconv.AppendBool(b, v.Bool())
+	case slog.KindDuration:
+		b = append(b, `{"stringValue":`...)
+		b = appendJSONString(b, v.Duration().String())
+	case slog.KindTime:
+		b = append(b, `{"stringValue":`...)
+		b = appendJSONString(b, v.Time().Format(time.RFC3339Nano))
+	case slog.KindGroup:
+		b = append(b, `{"kvlistValue":{"values":`...)
+		b = appendOTelAttrs(b, v.Group())
+		b = append(b, '}')
+	default:
+		b = append(b, `{"stringValue":`...)
+		b = appendJSONString(b, anyString(v.Any()))
+	}
+	return append(b, '}')
+}
+
+// anyString 将任意值转换为字符串：error/Stringer 使用其文本，其余尝试 JSON 编码
+func anyString(v any) string {
+	switch x := v.(type) {
+	case nil:
+		return "<nil>"
+	case error:
+		return x.Error()
+	case fmt.Stringer:
+		return x.String()
+	}
+	if data, err := json.Marshal(v); err == nil {
+		return string(data)
+	}
+	return fmt.Sprint(v)
+}
+
+// appendJSONString 输出 JSON 字符串字面量
+func appendJSONString(b []byte, s string) []byte {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return strconv.AppendQuote(b, s)
+	}
+	return append(b, data...)
+}
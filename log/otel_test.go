@@ -0,0 +1,250 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+// otelRecord OTel 记录的测试解码结构（encoding/json 按字段名大小写不敏感匹配）
+type otelRecord struct {
+	Resource struct {
+		Attributes []otelKV
+	}
+	TimeUnixNano   string
+	SeverityNumber int
+	SeverityText   string
+	Body           otelAny
+	Attributes     []otelKV
+	TraceID        string
+	SpanID         string
+}
+
+type otelKV struct {
+	Key   string
+	Value otelAny
+}
+
+type otelAny struct {
+	StringValue *string
+	IntValue    *string
+	DoubleValue *float64
+	BoolValue   *bool
+	KvlistValue *struct {
+		Values []otelKV
+	}
+}
+
+func decodeOTel(t *testing.T, line string) otelRecord {
+	t.Helper()
+	var rec otelRecord
+	if err := json.Unmarshal([]byte(line), &rec); err != nil {
+		t.Fatalf("invalid OTel JSON %q: %v", line, err)
+	}
+	return rec
+}
+
+func findKV(kvs []otelKV, key string) (otelAny, bool) {
+	for _, kv := range kvs {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	return otelAny{}, false
+}
+
+func TestOTelHandler_Record(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewOTelHandler(&buf, &OTelHandlerOptions{ServiceName: "demo", ServiceVersion: "1.2.3"})
+
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC)
+	r := slog.NewRecord(ts, slog.LevelWarn, "disk low", 0)
+	r.AddAttrs(
+		slog.String("path", "/data"),
+		slog.Int("free", 42),
+		slog.Float64("ratio", 0.5),
+		slog.Bool("critical", true),
+		slog.Duration("elapsed", 1500*time.Millisecond),
+	)
+	if err := h.Handle(context.Background(), r); err != nil {
+		t.Fatalf("Handle() error = %v", err)
+	}
+
+	rec := decodeOTel(t, buf.String())
+	if rec.TimeUnixNano != "1735787045000000006" {
+		t.Errorf("timeUnixNano = %q, want %q", rec.TimeUnixNano, "1735787045000000006")
+	}
+	if rec.SeverityNumber != 13 || rec.SeverityText != "WARN" {
+		t.Errorf("severity = %d/%q, want 13/WARN", rec.SeverityNumber, rec.SeverityText)
+	}
+	if rec.Body.StringValue == nil || *rec.Body.StringValue != "disk low" {
+		t.Errorf("body = %+v, want stringValue %q", rec.Body, "disk low")
+	}
+
+	if v, _ := findKV(rec.Resource.Attributes, OTelServiceName); v.StringValue == nil || *v.StringValue != "demo" {
+		t.Errorf("resource %s = %+v, want demo", OTelServiceName, v)
+	}
+	if v, _ := findKV(rec.Resource.Attributes, OTelServiceVersion); v.StringValue == nil || *v.StringValue != "1.2.3" {
+		t.Errorf("resource %s = %+v, want 1.2.3", OTelServiceVersion, v)
+	}
+
+	if v, _ := findKV(rec.Attributes, "path"); v.StringValue == nil || *v.StringValue != "/data" {
+		t.Errorf("attribute path = %+v, want /data", v)
+	}
+	if v, _ := findKV(rec.Attributes, "free"); v.IntValue == nil || *v.IntValue != "42" {
+		t.Errorf("attribute free = %+v, want intValue 42", v)
+	}
+	if v, _ := findKV(rec.Attributes, "ratio"); v.DoubleValue == nil || *v.DoubleValue != 0.5 {
+		t.Errorf("attribute ratio = %+v, want doubleValue 0.5", v)
+	}
+	if v, _ := findKV(rec.Attributes, "critical"); v.BoolValue == nil || !*v.BoolValue {
+		t.Errorf("attribute critical = %+v, want boolValue true", v)
+	}
+	if v, _ := findKV(rec.Attributes, "elapsed"); v.StringValue == nil || *v.StringValue != "1.5s" {
+		t.Errorf("attribute elapsed = %+v, want 1.5s", v)
+	}
+	if rec.TraceID != "" || rec.SpanID != "" {
+		t.Errorf("trace = %q/%q, want empty without trace context", rec.TraceID, rec.SpanID)
+	}
+}
+
+func TestOTelSeverityNumber(t *testing.T) {
+	tests := []struct {
+		level slog.Level
+		want  int
+	}{
+		{slog.LevelDebug, 5},
+		{slog.LevelInfo, 9},
+		{slog.LevelInfo + 1, 10},
+		{slog.LevelWarn, 13},
+		{slog.LevelError, 17},
+		{slog.LevelDebug - 10, 1},
+		{slog.LevelError + 100, 24},
+	}
+	for _, tt := range tests {
+		if got := otelSeverityNumber(tt.level); got != tt.want {
+			t.Errorf("otelSeverityNumber(%v) = %d, want %d", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestOTelHandler_GroupsAndAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewOTelHandler(&buf, nil))
+
+	logger.With("svc", "api").WithGroup("req").With("id", 7).Info("handled",
+		slog.Group("resp", slog.Int("status", 200)),
+	)
+
+	rec := decodeOTel(t, buf.String())
+	if v, _ := findKV(rec.Attributes, "svc"); v.StringValue == nil || *v.StringValue != "api" {
+		t.Errorf("attribute svc = %+v, want api", v)
+	}
+	req, ok := findKV(rec.Attributes, "req")
+	if !ok || req.KvlistValue == nil {
+		t.Fatalf("attribute req should be a kvlist, got: %s", buf.String())
+	}
+	if v, _ := findKV(req.KvlistValue.Values, "id"); v.IntValue == nil || *v.IntValue != "7" {
+		t.Errorf("req.id = %+v, want 7", v)
+	}
+	resp, ok := findKV(req.KvlistValue.Values, "resp")
+	if !ok || resp.KvlistValue == nil {
+		t.Fatalf("req.resp should be a kvlist, got: %s", buf.String())
+	}
+	if v, _ := findKV(resp.KvlistValue.Values, "status"); v.IntValue == nil || *v.IntValue != "200" {
+		t.Errorf("req.resp.status = %+v, want 200", v)
+	}
+}
+
+func TestOTelHandler_TraceContext(t *testing.T) {
+	const (
+		traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+		spanID  = "00f067aa0ba902b7"
+	)
+
+	var buf bytes.Buffer
+	logger := slog.New(NewOTelHandler(&buf, nil))
+	logger.InfoContext(ContextWithTrace(context.Background(), traceID, spanID), "traced")
+
+	rec := decodeOTel(t, buf.String())
+	if rec.TraceID != traceID || rec.SpanID != spanID {
+		t.Errorf("trace = %q/%q, want %q/%q", rec.TraceID, rec.SpanID, traceID, spanID)
+	}
+}
+
+func TestSetTraceExtractor(t *testing.T) {
+	t.Cleanup(func() { SetTraceExtractor(nil) })
+
+	SetTraceExtractor(func(context.Context) (string, string) { return "abc", "def" })
+	if traceID, spanID := extractTrace(context.Background()); traceID != "abc" || spanID != "def" {
+		t.Errorf("extractTrace() = %q/%q, want abc/def", traceID, spanID)
+	}
+
+	SetTraceExtractor(nil)
+	ctx := ContextWithTrace(context.Background(), "t1", "s1")
+	if traceID, spanID := extractTrace(ctx); traceID != "t1" || spanID != "s1" {
+		t.Errorf("extractTrace() after reset = %q/%q, want t1/s1", traceID, spanID)
+	}
+}
+
+func TestOTelHandler_Source(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewOTelHandler(&buf, &OTelHandlerOptions{AddSource: true}))
+	logger.Info("with source")
+
+	rec := decodeOTel(t, buf.String())
+	if v, _ := findKV(rec.Attributes, "code.filepath"); v.StringValue == nil || !strings.HasSuffix(*v.StringValue, "otel_test.go") {
+		t.Errorf("code.filepath = %+v, want otel_test.go", v)
+	}
+	if _, ok := findKV(rec.Attributes, "code.lineno"); !ok {
+		t.Error("code.lineno should be present")
+	}
+}
+
+func TestOTelHandler_ErrorChain(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewErrorHandler(NewOTelHandler(&buf, nil)))
+	logger.Error("failed", "error", Wrap(ErrOpenFile, "load"))
+
+	rec := decodeOTel(t, buf.String())
+	ev, ok := findKV(rec.Attributes, ErrorKey)
+	if !ok || ev.KvlistValue == nil {
+		t.Fatalf("error attribute should be a kvlist, got: %s", buf.String())
+	}
+	if v, _ := findKV(ev.KvlistValue.Values, "msg"); v.StringValue == nil || !strings.Contains(*v.StringValue, "load") {
+		t.Errorf("error.msg = %+v, want wrapped message", v)
+	}
+}
+
+func TestNew_OTelFormat(t *testing.T) {
+	logFile := t.TempDir() + "/app.log"
+
+	opts := Options{Level: LevelInfo, Format: FormatOTel, Output: logFile}
+	logger, err := New(opts.WithApp(appInfo{name: "svc", version: "v1"}))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	logger.Info("hello", "err", errors.New("boom"))
+	_ = logger.Close()
+
+	rec := decodeOTel(t, strings.TrimSpace(readFile(t, logFile)))
+	if v, _ := findKV(rec.Resource.Attributes, OTelServiceName); v.StringValue == nil || *v.StringValue != "svc" {
+		t.Errorf("resource %s = %+v, want svc", OTelServiceName, v)
+	}
+	if v, _ := findKV(rec.Attributes, "err"); v.StringValue == nil || *v.StringValue != "boom" {
+		t.Errorf("attribute err = %+v, want boom", v)
+	}
+}
+
+type appInfo struct {
+	name    string
+	version string
+}
+
+func (a appInfo) GetName() string    { return a.name }
+func (a appInfo) GetVersion() string { return a.version }
@@ -0,0 +1,88 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 日志文件与目录的默认权限
+const (
+	DefaultFileMode os.FileMode = 0o600
+	DefaultDirMode  os.FileMode = 0o750
+)
+
+// 文件输出路径模板占位符，在打开输出时展开
+const (
+	PlaceholderApp      = "{app}"      // Options.AppName，未设置时为可执行文件名
+	PlaceholderHostname = "{hostname}" // 主机名，K8s 中为 Pod 名称
+	PlaceholderPID      = "{pid}"      // 进程 ID
+	PlaceholderDate     = "{date}"     // 打开时的本地日期，格式 2006-01-02
+)
+
+// ExpandOutput 展开文件输出路径中的模板占位符
+// 非文件输出与不含占位符的路径原样返回；展开值中的路径分隔符替换为 _
+//
+//	log.ExpandOutput("runtime/log/{app}-{hostname}-{pid}-{date}.log", "api")
+//	// runtime/log/api-web-0-4242-2024-05-01.log
+func ExpandOutput(output, app string) string {
+	if !isFileOutput(output) || !strings.Contains(output, "{") {
+		return output
+	}
+	if app == "" {
+		app = strings.TrimSuffix(filepath.Base(os.Args[0]), filepath.Ext(os.Args[0]))
+	}
+	hostname, err := os.Hostname()
+	if err != nil || hostname == "" {
+		hostname = "localhost"
+	}
+
+	r := strings.NewReplacer(
+		PlaceholderApp, sanitizePathElem(app),
+		PlaceholderHostname, sanitizePathElem(hostname),
+		PlaceholderPID, strconv.Itoa(os.Getpid()),
+		PlaceholderDate, time.Now().Format(time.DateOnly),
+	)
+	return r.Replace(output)
+}
+
+// sanitizePathElem 替换路径分隔符，避免展开值改变目录层级
+func sanitizePathElem(s string) string {
+	return strings.NewReplacer("/", "_", `\`, "_").Replace(s)
+}
+
+// openFile 创建目录并以追加模式打开日志文件
+// 失败时返回 ErrOpenFile，并附带原因（如 is a directory、read-only file system）
+func openFile(path string, fileMode, dirMode os.FileMode) (*os.File, error) {
+	if fileMode == 0 {
+		fileMode = DefaultFileMode
+	}
+	if dirMode == 0 {
+		dirMode = DefaultDirMode
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), dirMode); err != nil {
+		return nil, openFileError(path, err)
+	}
+
+	// #nosec G304 -- path 来自配置文件，由用户控制
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, fileMode)
+	if err != nil {
+		return nil, openFileError(path, err)
+	}
+	return f, nil
+}
+
+// openFileError 包装文件打开错误，错误路径与 path 相同时只保留原因
+func openFileError(path string, err error) error {
+	var pe *fs.PathError
+	if errors.As(err, &pe) && pe.Path == path {
+		err = pe.Err
+	}
+	return fmt.Errorf("%w: %s (%w)", ErrOpenFile, path, err)
+}
@@ -0,0 +1,150 @@
+package log
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpandOutput(t *testing.T) {
+	hostname, _ := os.Hostname()
+	pid := strconv.Itoa(os.Getpid())
+	date := time.Now().Format(time.DateOnly)
+
+	tests := []struct {
+		name   string
+		output string
+		app    string
+		want   string
+	}{
+		{"stdout", OutputStdout, "api", OutputStdout},
+		{"network", "tcp://127.0.0.1:5140", "api", "tcp://127.0.0.1:5140"},
+		{"literal", "runtime/log/app.log", "api", "runtime/log/app.log"},
+		{"all", "runtime/log/{app}-{hostname}-{pid}-{date}.log", "api", "runtime/log/api-" + hostname + "-" + pid + "-" + date + ".log"},
+		{"sanitized app", "/var/log/{app}.log", "team/api", "/var/log/team_api.log"},
+		{"unknown placeholder", "/var/log/{app}-{shard}.log", "api", "/var/log/api-{shard}.log"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExpandOutput(tt.output, tt.app); got != tt.want {
+				t.Errorf("ExpandOutput() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// 未设置应用名称时使用可执行文件名
+	exe := strings.TrimSuffix(filepath.Base(os.Args[0]), filepath.Ext(os.Args[0]))
+	if got := ExpandOutput("{app}.log", ""); got != exe+".log" {
+		t.Errorf("ExpandOutput() without app = %q, want %q", got, exe+".log")
+	}
+}
+
+func TestNew_TemplatedOutput(t *testing.T) {
+	dir := t.TempDir()
+	logger, err := New(Options{
+		Format:  FormatText,
+		Output:  filepath.Join(dir, "{app}", "{app}-{pid}.log"),
+		AppName: "billing",
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	logger.Info("templated")
+	_ = logger.Close()
+
+	path := filepath.Join(dir, "billing", "billing-"+strconv.Itoa(os.Getpid())+".log")
+	if data := readFile(t, path); !strings.Contains(data, "msg=templated") {
+		t.Errorf("expanded file should contain the record, got: %q", data)
+	}
+}
+
+func TestNew_FileAndDirMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not supported on windows")
+	}
+
+	// 选用不受常见 umask（022）影响的权限
+	path := filepath.Join(t.TempDir(), "logs", "app.log")
+	logger, err := New(Options{Output: path, FileMode: 0o640, DirMode: 0o700})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	_ = logger.Close()
+
+	assertMode(t, path, 0o640)
+	assertMode(t, filepath.Dir(path), 0o700)
+
+	// 未设置时使用默认权限
+	path = filepath.Join(t.TempDir(), "default", "app.log")
+	logger, err = New(Options{Output: path})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	_ = logger.Close()
+
+	assertMode(t, path, DefaultFileMode)
+	assertMode(t, filepath.Dir(path), DefaultDirMode)
+}
+
+func assertMode(t *testing.T, path string, want os.FileMode) {
+	t.Helper()
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := info.Mode().Perm(); got != want {
+		t.Errorf("mode of %s = %o, want %o", path, got, want)
+	}
+}
+
+func TestNew_OpenFileErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := New(Options{Output: dir})
+	if !errors.Is(err, ErrOpenFile) {
+		t.Fatalf("New() error = %v, want %v", err, ErrOpenFile)
+	}
+	if !strings.Contains(err.Error(), dir+" (is a directory)") {
+		t.Errorf("error should name the path and reason, got: %v", err)
+	}
+
+	// 上级路径是文件时无法创建目录
+	file := filepath.Join(dir, "file")
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = OpenOutputWithOptions(Options{Output: filepath.Join(file, "app.log")})
+	if !errors.Is(err, ErrOpenFile) || !strings.Contains(err.Error(), "not a directory") {
+		t.Errorf("OpenOutputWithOptions() error = %v, want %v with reason", err, ErrOpenFile)
+	}
+}
+
+func TestLoadOptions_FileModes(t *testing.T) {
+	loader := writeConfig(t, `
+log:
+  file_mode: "0640"
+  dir_mode: 0o700
+`)
+
+	opts, err := LoadOptions(loader, "log")
+	if err != nil {
+		t.Fatalf("LoadOptions() error = %v", err)
+	}
+	if opts.FileMode != 0o640 || opts.DirMode != 0o700 {
+		t.Errorf("LoadOptions() modes = %o/%o, want 640/700", opts.FileMode, opts.DirMode)
+	}
+
+	// 未配置时使用默认权限
+	opts, err = LoadOptions(writeConfig(t, "log:\n  level: info\n"), "log")
+	if err != nil {
+		t.Fatalf("LoadOptions() error = %v", err)
+	}
+	if opts.FileMode != DefaultFileMode || opts.DirMode != DefaultDirMode {
+		t.Errorf("LoadOptions() modes = %o/%o, want defaults", opts.FileMode, opts.DirMode)
+	}
+}
@@ -0,0 +1,227 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// DefaultSensitiveKeywords 默认敏感字段关键字（不区分大小写，包含匹配）
+// 与 cli 启动横幅的遮蔽规则保持一致
+var DefaultSensitiveKeywords = []string{
+	"token", "password", "passwd", "secret", "key", "auth", "credential", "dsn",
+}
+
+// 常用敏感值正则，可直接用于 RedactOptions.Patterns
+const (
+	PatternCreditCard = `\b(?:\d[ -]?){12,18}\d\b`
+	PatternEmail      = `[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`
+)
+
+// DefaultMask 默认遮蔽占位符
+const DefaultMask = "******"
+
+// RedactOptions 敏感字段遮蔽配置
+type RedactOptions struct {
+	Keywords []string `mapstructure:"keywords" yaml:"keywords"` // 敏感 key 关键字（不区分大小写），nil 时使用 DefaultSensitiveKeywords
+	Patterns []string `mapstructure:"patterns" yaml:"patterns"` // 敏感值正则，字符串值与消息中匹配的片段替换为 Mask
+	Mask     string   `mapstructure:"mask" yaml:"mask"`         // 遮蔽占位符，默认 DefaultMask
+}
+
+// RedactHandler 敏感字段遮蔽中间件
+//
+// key 命中关键字的属性（包括分组）整体替换为占位符；
+// 其余字符串值按正则替换命中的片段。嵌套分组会被逐层遍历，
+// 实现 slog.LogValuer 的值先解析再遮蔽。
+type RedactHandler struct {
+	next     slog.Handler
+	keywords []string
+	patterns []*regexp.Regexp
+	mask     string
+}
+
+var (
+	_ slog.Handler = (*RedactHandler)(nil)
+	_ io.Closer    = (*RedactHandler)(nil)
+)
+
+// NewRedactHandler 创建敏感字段遮蔽中间件
+func NewRedactHandler(next slog.Handler, opts RedactOptions) (*RedactHandler, error) {
+	keywords := opts.Keywords
+	if keywords == nil {
+		keywords = DefaultSensitiveKeywords
+	}
+	lowered := make([]string, 0, len(keywords))
+	for _, kw := range keywords {
+		if kw != "" {
+			lowered = append(lowered, strings.ToLower(kw))
+		}
+	}
+
+	patterns, err := compilePatterns(opts.Patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	mask := opts.Mask
+	if mask == "" {
+		mask = DefaultMask
+	}
+
+	return &RedactHandler{
+		next:     next,
+		keywords: lowered,
+		patterns: patterns,
+		mask:     mask,
+	}, nil
+}
+
+// compilePatterns 编译敏感值正则，失败时返回 ErrInvalidPattern
+func compilePatterns(exprs []string) ([]*regexp.Regexp, error) {
+	patterns := make([]*regexp.Regexp, 0, len(exprs))
+	for _, p := range exprs {
+		re, err := regexp.Compile(p)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q (%w)", ErrInvalidPattern, p, err)
+		}
+		patterns = append(patterns, re)
+	}
+	return patterns, nil
+}
+
+// Enabled 实现 slog.Handler
+func (h *RedactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle 实现 slog.Handler
+func (h *RedactHandler) Handle(ctx context.Context, r slog.Record) error {
+	nr := slog.NewRecord(r.Time, r.Level, h.redactString(r.Message), r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(h.redactAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, nr)
+}
+
+// WithAttrs 实现 slog.Handler
+func (h *RedactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	redacted := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		redacted[i] = h.redactAttr(a)
+	}
+	clone := *h
+	clone.next = h.next.WithAttrs(redacted)
+	return &clone
+}
+
+// WithGroup 实现 slog.Handler
+func (h *RedactHandler) WithGroup(name string) slog.Handler {
+	clone := *h
+	clone.next = h.next.WithGroup(name)
+	return &clone
+}
+
+// Close 关闭下游 Handler
+func (h *RedactHandler) Close() error {
+	return closeHandler(h.next)
+}
+
+// RedactAttr 按遮蔽规则处理单个属性
+// 供不经过 Handler 链的场景复用同一套规则，如 httplog 记录请求头
+func (h *RedactHandler) RedactAttr(a slog.Attr) slog.Attr {
+	return h.redactAttr(a)
+}
+
+// redactAttr 遮蔽单个属性，分组递归处理
+func (h *RedactHandler) redactAttr(a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+
+	if h.isSensitive(a.Key) {
+		return slog.String(a.Key, h.mask)
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindGroup:
+		group := a.Value.Group()
+		redacted := make([]slog.Attr, len(group))
+		for i, ga := range group {
+			redacted[i] = h.redactAttr(ga)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(redacted...)}
+	case slog.KindString:
+		return slog.String(a.Key, h.redactString(a.Value.String()))
+	case slog.KindAny:
+		// Err 展开的错误链不是分组，逐层遮蔽其中的消息
+		if chain, ok := a.Value.Any().([]errorEntry); ok {
+			return slog.Any(a.Key, h.redactChain(chain))
+		}
+		return h.redactAny(a)
+	default:
+		return a
+	}
+}
+
+// redactAny 遮蔽 error 与 fmt.Stringer 的文本
+// 格式化 Handler 会输出它们的 Error()/String()，命中正则时以遮蔽后的字符串替换，
+// 未命中时保留原值，以便下游按错误类型输出
+func (h *RedactHandler) redactAny(a slog.Attr) slog.Attr {
+	if len(h.patterns) == 0 {
+		return a
+	}
+	var text string
+	switch v := a.Value.Any().(type) {
+	case error:
+		text = v.Error()
+	case fmt.Stringer:
+		text = v.String()
+	default:
+		return a
+	}
+	if redacted := h.redactString(text); redacted != text {
+		return slog.String(a.Key, redacted)
+	}
+	return a
+}
+
+// redactChain 返回消息已遮蔽的错误链副本
+func (h *RedactHandler) redactChain(chain []errorEntry) []errorEntry {
+	redacted := make([]errorEntry, len(chain))
+	for i, e := range chain {
+		e.Msg = h.redactString(e.Msg)
+		if e.Causes != nil {
+			causes := make([][]errorEntry, len(e.Causes))
+			for j, c := range e.Causes {
+				causes[j] = h.redactChain(c)
+			}
+			e.Causes = causes
+		}
+		redacted[i] = e
+	}
+	return redacted
+}
+
+// isSensitive 判断 key 是否包含敏感关键字
+func (h *RedactHandler) isSensitive(key string) bool {
+	if key == "" {
+		return false
+	}
+	lower := strings.ToLower(key)
+	for _, kw := range h.keywords {
+		if strings.Contains(lower, kw) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactString 按正则替换字符串中的敏感片段
+func (h *RedactHandler) redactString(s string) string {
+	for _, re := range h.patterns {
+		s = re.ReplaceAllString(s, h.mask)
+	}
+	return s
+}
@@ -0,0 +1,168 @@
+package log
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// secretToken 用于验证 LogValuer 在遮蔽前被解析
+type secretToken struct{ value string }
+
+func (s secretToken) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("id", "tok-1"),
+		slog.String("token", s.value),
+	)
+}
+
+func newRedactLogger(t *testing.T, opts RedactOptions) (*slog.Logger, *bytes.Buffer) {
+	t.Helper()
+	var buf bytes.Buffer
+	h, err := NewRedactHandler(slog.NewJSONHandler(&buf, nil), opts)
+	if err != nil {
+		t.Fatalf("NewRedactHandler() error = %v", err)
+	}
+	return slog.New(h), &buf
+}
+
+func TestRedactHandler_Keywords(t *testing.T) {
+	logger, buf := newRedactLogger(t, RedactOptions{})
+
+	logger.Info("login", "user", "alice", "password", "hunter2", "API_KEY", "abc")
+
+	out := buf.String()
+	if strings.Contains(out, "hunter2") || strings.Contains(out, "abc") {
+		t.Errorf("sensitive values should be masked, got: %s", out)
+	}
+	if !strings.Contains(out, `"password":"******"`) {
+		t.Errorf("expected masked password, got: %s", out)
+	}
+	if !strings.Contains(out, `"user":"alice"`) {
+		t.Errorf("non-sensitive values should be kept, got: %s", out)
+	}
+}
+
+func TestRedactHandler_NestedGroups(t *testing.T) {
+	logger, buf := newRedactLogger(t, RedactOptions{})
+
+	logger.Info("connect",
+		slog.Group("db",
+			slog.String("host", "localhost"),
+			slog.Group("auth", slog.String("user", "root")),
+			slog.Group("opts", slog.String("secret", "s3cr3t")),
+		),
+	)
+
+	out := buf.String()
+	if strings.Contains(out, "s3cr3t") {
+		t.Errorf("nested secret should be masked, got: %s", out)
+	}
+	if strings.Contains(out, "root") {
+		t.Errorf("sensitive group should be masked as a whole, got: %s", out)
+	}
+	if !strings.Contains(out, `"host":"localhost"`) {
+		t.Errorf("non-sensitive nested value should be kept, got: %s", out)
+	}
+}
+
+func TestRedactHandler_WithAttrsAndGroup(t *testing.T) {
+	logger, buf := newRedactLogger(t, RedactOptions{})
+
+	logger.With("token", "t-123").WithGroup("req").Info("call", "passwd", "p")
+
+	out := buf.String()
+	if strings.Contains(out, "t-123") || strings.Contains(out, `"passwd":"p"`) {
+		t.Errorf("attrs added via With/WithGroup should be masked, got: %s", out)
+	}
+}
+
+func TestRedactHandler_LogValuer(t *testing.T) {
+	logger, buf := newRedactLogger(t, RedactOptions{})
+
+	logger.Info("issued", "credential_info", "x", "issued", secretToken{value: "raw-token"})
+
+	out := buf.String()
+	if strings.Contains(out, "raw-token") {
+		t.Errorf("LogValuer output should be masked, got: %s", out)
+	}
+	if !strings.Contains(out, `"id":"tok-1"`) {
+		t.Errorf("LogValuer non-sensitive fields should be kept, got: %s", out)
+	}
+}
+
+func TestRedactHandler_Patterns(t *testing.T) {
+	logger, buf := newRedactLogger(t, RedactOptions{
+		Keywords: []string{},
+		Patterns: []string{PatternCreditCard, PatternEmail},
+		Mask:     "[REDACTED]",
+	})
+
+	logger.Info("paid by alice@example.com", "card", "4111 1111 1111 1111", "password", "kept")
+
+	out := buf.String()
+	if strings.Contains(out, "alice@example.com") || strings.Contains(out, "4111") {
+		t.Errorf("pattern matches should be masked, got: %s", out)
+	}
+	if !strings.Contains(out, `"password":"kept"`) {
+		t.Errorf("empty keyword list should disable key matching, got: %s", out)
+	}
+}
+
+// userRef 通过 String 输出邮箱的 fmt.Stringer
+type userRef struct{ email string }
+
+func (u userRef) String() string { return "user<" + u.email + ">" }
+
+func TestRedactHandler_PatternsInErrorsAndStringers(t *testing.T) {
+	logger, buf := newRedactLogger(t, RedactOptions{
+		Patterns: []string{PatternCreditCard, PatternEmail},
+	})
+
+	errPlain := errors.New("timeout")
+	logger.Error("payment failed",
+		"err", errors.New("card 4111111111111111 declined"),
+		"user", userRef{email: "alice@example.com"},
+		"cause", errPlain,
+	)
+
+	out := buf.String()
+	if strings.Contains(out, "4111111111111111") || strings.Contains(out, "alice@example.com") {
+		t.Errorf("error and Stringer text should be masked, got: %s", out)
+	}
+	for _, want := range []string{`"err":"card ` + DefaultMask + ` declined"`, `"user":"user<` + DefaultMask + `>"`, `"cause":"timeout"`} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output should contain %s, got: %s", want, out)
+		}
+	}
+}
+
+func TestNewRedactHandler_InvalidPattern(t *testing.T) {
+	_, err := NewRedactHandler(slog.DiscardHandler, RedactOptions{Patterns: []string{"("}})
+	if !errors.Is(err, ErrInvalidPattern) {
+		t.Errorf("NewRedactHandler() error = %v, want ErrInvalidPattern", err)
+	}
+}
+
+func TestNew_WithRedact(t *testing.T) {
+	logFile := t.TempDir() + "/app.log"
+
+	logger, err := New(Options{
+		Level:  LevelInfo,
+		Format: FormatJSON,
+		Output: logFile,
+		Redact: &RedactOptions{},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	logger.Info("login", "password", "hunter2")
+	_ = logger.Close()
+
+	data := readFile(t, logFile)
+	if strings.Contains(data, "hunter2") {
+		t.Errorf("password should be masked, got: %s", data)
+	}
+}
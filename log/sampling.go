@@ -0,0 +1,199 @@
+package log
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 采样默认值
+const (
+	DefaultSamplingTick           = time.Second      // 默认采样窗口
+	DefaultSamplingReportInterval = 10 * time.Second // 默认丢弃汇总间隔
+)
+
+// MsgSamplingDropped 采样丢弃汇总记录的消息内容
+const MsgSamplingDropped = "log records dropped by sampling"
+
+// SamplingOptions 日志采样配置
+//
+// 语义与 zapcore.NewSamplerWithOptions 一致：每个窗口内同一级别、同一消息
+// 的前 Initial 条全部输出，之后每 Thereafter 条输出 1 条（0 表示全部丢弃）。
+type SamplingOptions struct {
+	Tick           time.Duration `mapstructure:"tick" yaml:"tick"`                       // 采样窗口，默认 1s
+	Initial        int           `mapstructure:"initial" yaml:"initial"`                 // 每个窗口内每条消息的前 N 条全部输出
+	Thereafter     int           `mapstructure:"thereafter" yaml:"thereafter"`           // 超过 Initial 后每 M 条输出 1 条，0 表示全部丢弃
+	ReportInterval time.Duration `mapstructure:"report_interval" yaml:"report_interval"` // 丢弃汇总的输出间隔，默认 10s
+}
+
+// SamplingHandler 日志采样中间件
+//
+// 被丢弃的记录数由后台 goroutine 每隔 ReportInterval 汇总为一条 warn 记录输出，
+// 没有新记录到达时同样会输出。Close 停止该 goroutine 并输出剩余的汇总，
+// 因此不再使用时必须调用 Close。
+type SamplingHandler struct {
+	next  slog.Handler
+	state *samplingState
+}
+
+var (
+	_ slog.Handler = (*SamplingHandler)(nil)
+	_ io.Closer    = (*SamplingHandler)(nil)
+)
+
+// samplingState 在 WithAttrs/WithGroup 派生的 Handler 之间共享
+type samplingState struct {
+	root       slog.Handler // 汇总记录写入未附加属性/分组的根 Handler
+	tick       time.Duration
+	initial    uint64
+	thereafter uint64
+	interval   time.Duration
+
+	mu     sync.Mutex
+	window int64
+	counts map[samplingKey]uint64
+
+	total      atomic.Uint64
+	pending    atomic.Uint64
+	lastReport atomic.Int64
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// samplingKey 采样计数的 key：级别 + 消息
+type samplingKey struct {
+	level slog.Level
+	msg   string
+}
+
+// NewSamplingHandler 创建日志采样中间件，并启动定期输出丢弃汇总的 goroutine
+func NewSamplingHandler(next slog.Handler, opts SamplingOptions) *SamplingHandler {
+	tick := opts.Tick
+	if tick <= 0 {
+		tick = DefaultSamplingTick
+	}
+	interval := opts.ReportInterval
+	if interval <= 0 {
+		interval = DefaultSamplingReportInterval
+	}
+
+	state := &samplingState{
+		root:       next,
+		tick:       tick,
+		initial:    uint64(max(opts.Initial, 0)),
+		thereafter: uint64(max(opts.Thereafter, 0)),
+		interval:   interval,
+		counts:     make(map[samplingKey]uint64),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	state.lastReport.Store(time.Now().UnixNano())
+	go state.run()
+
+	return &SamplingHandler{next: next, state: state}
+}
+
+// Enabled 实现 slog.Handler
+func (h *SamplingHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle 实现 slog.Handler
+func (h *SamplingHandler) Handle(ctx context.Context, r slog.Record) error {
+	s := h.state
+	now := r.Time
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	if !s.sample(r.Level, r.Message, now) {
+		s.total.Add(1)
+		s.pending.Add(1)
+		return nil
+	}
+	return h.next.Handle(ctx, r)
+}
+
+// WithAttrs 实现 slog.Handler
+func (h *SamplingHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &SamplingHandler{next: h.next.WithAttrs(attrs), state: h.state}
+}
+
+// WithGroup 实现 slog.Handler
+func (h *SamplingHandler) WithGroup(name string) slog.Handler {
+	return &SamplingHandler{next: h.next.WithGroup(name), state: h.state}
+}
+
+// Dropped 返回累计丢弃的记录数
+func (h *SamplingHandler) Dropped() uint64 {
+	return h.state.total.Load()
+}
+
+// Close 停止汇总 goroutine，输出剩余的丢弃汇总，并关闭下游 Handler
+func (h *SamplingHandler) Close() error {
+	s := h.state
+	s.stopOnce.Do(func() {
+		close(s.stop)
+		<-s.done
+		s.report(context.Background(), time.Now())
+	})
+	return closeHandler(h.next)
+}
+
+// sample 判断记录是否应该输出
+func (s *samplingState) sample(level slog.Level, msg string, now time.Time) bool {
+	window := now.UnixNano() / int64(s.tick)
+	key := samplingKey{level: level, msg: msg}
+
+	s.mu.Lock()
+	if window != s.window {
+		s.window = window
+		clear(s.counts)
+	}
+	s.counts[key]++
+	n := s.counts[key]
+	s.mu.Unlock()
+
+	if n <= s.initial {
+		return true
+	}
+	return s.thereafter != 0 && (n-s.initial)%s.thereafter == 0
+}
+
+// run 每隔汇总间隔输出一次丢弃汇总，直到 Close
+func (s *samplingState) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			s.report(context.Background(), now)
+		}
+	}
+}
+
+// report 输出上次汇总以来的丢弃汇总，没有丢弃时不输出
+// 仅由 run 与 Close 调用，二者不会并发执行
+func (s *samplingState) report(ctx context.Context, now time.Time) {
+	last := s.lastReport.Swap(now.UnixNano())
+	dropped := s.pending.Swap(0)
+	if dropped == 0 || !s.root.Enabled(ctx, slog.LevelWarn) {
+		return
+	}
+
+	r := slog.NewRecord(now, slog.LevelWarn, MsgSamplingDropped, 0)
+	r.AddAttrs(
+		slog.Uint64("dropped", dropped),
+		slog.Duration("interval", time.Duration(now.UnixNano()-last)),
+	)
+	_ = s.root.Handle(ctx, r)
+}
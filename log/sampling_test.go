@@ -0,0 +1,174 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSamplingHandler_InitialThereafter(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewSamplingHandler(slog.NewJSONHandler(&buf, nil), SamplingOptions{
+		Tick:           time.Hour,
+		Initial:        3,
+		Thereafter:     10,
+		ReportInterval: time.Hour,
+	})
+	logger := slog.New(h)
+
+	for range 100 {
+		logger.Error("connection refused")
+	}
+	logger.Info("other message")
+
+	// 前 3 条 + 之后每 10 条 1 条（第 13、23...93 条）+ 另一条消息
+	if got, want := strings.Count(buf.String(), "\n"), 3+9+1; got != want {
+		t.Errorf("written records = %d, want %d", got, want)
+	}
+	if got, want := h.Dropped(), uint64(100-3-9); got != want {
+		t.Errorf("Dropped() = %d, want %d", got, want)
+	}
+}
+
+func TestSamplingHandler_ThereafterZero(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewSamplingHandler(slog.NewJSONHandler(&buf, nil), SamplingOptions{
+		Tick:           time.Hour,
+		Initial:        1,
+		ReportInterval: time.Hour,
+	})
+	logger := slog.New(h).With("component", "db")
+
+	for range 5 {
+		logger.Warn("retry")
+	}
+
+	if got := strings.Count(buf.String(), "\n"); got != 1 {
+		t.Errorf("written records = %d, want 1", got)
+	}
+	if got := h.Dropped(); got != 4 {
+		t.Errorf("Dropped() = %d, want 4 (shared across With)", got)
+	}
+}
+
+func TestSamplingHandler_WindowReset(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewSamplingHandler(slog.NewJSONHandler(&buf, nil), SamplingOptions{
+		Tick:           time.Second,
+		Initial:        1,
+		ReportInterval: time.Hour,
+	})
+
+	base := time.Now().Truncate(time.Second)
+	for i := range 3 {
+		for range 2 {
+			r := slog.NewRecord(base.Add(time.Duration(i)*time.Second), slog.LevelInfo, "tick", 0)
+			_ = h.Handle(context.Background(), r)
+		}
+	}
+
+	if got := strings.Count(buf.String(), "\n"); got != 3 {
+		t.Errorf("written records = %d, want 3 (one per window)", got)
+	}
+}
+
+func TestSamplingHandler_Report(t *testing.T) {
+	var buf syncBuffer
+	h := NewSamplingHandler(slog.NewJSONHandler(&buf, nil), SamplingOptions{
+		Tick:           time.Hour,
+		Initial:        1,
+		ReportInterval: 50 * time.Millisecond,
+	})
+	t.Cleanup(func() { _ = h.Close() })
+	logger := slog.New(h).WithGroup("req")
+
+	for range 5 {
+		logger.Info("spam")
+	}
+	waitFor(t, func() bool { return strings.Contains(buf.String(), MsgSamplingDropped) })
+
+	out := buf.String()
+	if !strings.Contains(out, `"dropped":4`) {
+		t.Errorf("expected drop summary with dropped=4, got: %s", out)
+	}
+	if strings.Contains(out, `"req":{"dropped"`) {
+		t.Errorf("summary should be written to the root handler, got: %s", out)
+	}
+}
+
+func TestSamplingHandler_ReportWithoutTraffic(t *testing.T) {
+	var buf syncBuffer
+	h := NewSamplingHandler(slog.NewJSONHandler(&buf, nil), SamplingOptions{
+		Tick:           time.Hour,
+		Initial:        1,
+		ReportInterval: 10 * time.Millisecond,
+	})
+	t.Cleanup(func() { _ = h.Close() })
+
+	// 突发后不再有新记录，汇总仍应按间隔输出
+	for range 3 {
+		slog.New(h).Info("burst")
+	}
+	waitFor(t, func() bool { return strings.Contains(buf.String(), `"dropped":2`) })
+
+	// 没有新的丢弃时不输出空汇总
+	time.Sleep(50 * time.Millisecond)
+	if got := strings.Count(buf.String(), MsgSamplingDropped); got != 1 {
+		t.Errorf("summaries = %d, want 1", got)
+	}
+}
+
+func TestSamplingHandler_CloseFlushesReport(t *testing.T) {
+	var buf bytes.Buffer
+	h := NewSamplingHandler(slog.NewJSONHandler(&buf, nil), SamplingOptions{
+		Tick:           time.Hour,
+		Initial:        1,
+		ReportInterval: time.Hour,
+	})
+	logger := slog.New(h)
+
+	for range 3 {
+		logger.Info("spam")
+	}
+	if strings.Contains(buf.String(), MsgSamplingDropped) {
+		t.Fatal("summary should not be written before interval elapses")
+	}
+
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), `"dropped":2`) {
+		t.Errorf("Close() should flush drop summary, got: %s", buf.String())
+	}
+}
+
+func TestNew_WithSampling(t *testing.T) {
+	logFile := t.TempDir() + "/app.log"
+
+	logger, err := New(Options{
+		Level:    LevelInfo,
+		Format:   FormatJSON,
+		Output:   logFile,
+		Sampling: &SamplingOptions{Tick: time.Hour, Initial: 2, ReportInterval: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	for range 10 {
+		logger.Info("hot path")
+	}
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	data := readFile(t, logFile)
+	if got := strings.Count(data, `"msg":"hot path"`); got != 2 {
+		t.Errorf("written records = %d, want 2", got)
+	}
+	if !strings.Contains(data, `"dropped":8`) {
+		t.Errorf("Close() should flush drop summary, got: %s", data)
+	}
+}
@@ -0,0 +1,69 @@
+package log
+
+import (
+	"runtime"
+	"strconv"
+)
+
+// maxStackDepth 捕获堆栈的最大深度
+const maxStackDepth = 32
+
+// stackError 携带包装位置堆栈的错误
+type stackError struct {
+	err error
+	msg string
+	pcs []uintptr
+}
+
+// Wrap 使用 msg 包装 err，并捕获调用 Wrap 处的堆栈
+// err 为 nil 时返回 nil。包装后的错误可被 errors.Is/As 穿透，
+// 通过 Err 或 Options.ExpandErrors 记录时会输出该堆栈
+func Wrap(err error, msg string) error {
+	if err == nil {
+		return nil
+	}
+	return &stackError{err: err, msg: msg, pcs: callers()}
+}
+
+// WithStack 为 err 捕获调用处的堆栈，不改变错误信息
+// err 为 nil 时返回 nil
+func WithStack(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &stackError{err: err, pcs: callers()}
+}
+
+// Error 实现 error
+func (e *stackError) Error() string {
+	if e.msg == "" {
+		return e.err.Error()
+	}
+	return e.msg + ": " + e.err.Error()
+}
+
+// Unwrap 支持 errors.Is/As
+func (e *stackError) Unwrap() error {
+	return e.err
+}
+
+// StackTrace 返回包装位置的堆栈，每帧格式为 "function file:line"
+func (e *stackError) StackTrace() []string {
+	frames := runtime.CallersFrames(e.pcs)
+	stack := make([]string, 0, len(e.pcs))
+	for {
+		f, more := frames.Next()
+		stack = append(stack, f.Function+" "+f.File+":"+strconv.Itoa(f.Line))
+		if !more {
+			break
+		}
+	}
+	return stack
+}
+
+// callers 捕获 Wrap/WithStack 调用方的堆栈
+func callers() []uintptr {
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(3, pcs[:])
+	return pcs[:n]
+}
@@ -0,0 +1,154 @@
+package log
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// syslogFacilities syslog facility 名称映射（RFC 5424）
+var syslogFacilities = map[string]int{
+	"kern": 0, "user": 1, "mail": 2, "daemon": 3, "auth": 4, "syslog": 5,
+	"lpr": 6, "news": 7, "uucp": 8, "cron": 9, "authpriv": 10, "ftp": 11,
+	"local0": 16, "local1": 17, "local2": 18, "local3": 19,
+	"local4": 20, "local5": 21, "local6": 22, "local7": 23,
+}
+
+// defaultSyslogFacility 默认 facility: user
+const defaultSyslogFacility = 1
+
+// RFC 5424 头部字段的最大长度
+const (
+	syslogMaxHostname = 255
+	syslogMaxAppName  = 48
+)
+
+// recordFramer 按记录的级别与消息编码一条已格式化的记录
+type recordFramer func(level slog.Level, msg string, line []byte) []byte
+
+// newSyslogFramer 创建 RFC 5424 编码函数
+// 查询参数 facility 指定 facility，tag 指定 APP-NAME；
+// severity 取自记录的级别，MSG 为格式化后的整行
+func newSyslogFramer(query url.Values) (recordFramer, error) {
+	facility := defaultSyslogFacility
+	if name := query.Get("facility"); name != "" {
+		f, ok := syslogFacilities[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown syslog facility %q", ErrInvalidOutput, name)
+		}
+		facility = f
+	}
+
+	tag := query.Get("tag")
+	if tag == "" {
+		tag = processName()
+	}
+	tag = syslogHeaderField(tag, syslogMaxAppName)
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = ""
+	}
+	hostname = syslogHeaderField(hostname, syslogMaxHostname)
+	pid := strconv.Itoa(os.Getpid())
+
+	return func(level slog.Level, _ string, line []byte) []byte {
+		pri := facility*8 + syslogSeverity(level)
+		msg := bytes.TrimRight(line, "\n")
+
+		b := make([]byte, 0, len(msg)+96)
+		b = append(b, '<')
+		b = strconv.AppendInt(b, int64(pri), 10)
+		b = append(b, ">1 "...)
+		b = time.Now().UTC().AppendFormat(b, "2006-01-02T15:04:05.000000Z07:00")
+		b = append(b, ' ')
+		b = append(b, hostname...)
+		b = append(b, ' ')
+		b = append(b, tag...)
+		b = append(b, ' ')
+		b = append(b, pid...)
+		b = append(b, " - - "...)
+		return append(b, msg...)
+	}, nil
+}
+
+// syslogHeaderField 将值转换为合法的 RFC 5424 头部字段
+// 非 PRINTUSASCII（33-126）的字节替换为 '_'，超出 maxLen 的部分截断，空值使用 NILVALUE "-"
+func syslogHeaderField(v string, maxLen int) string {
+	if len(v) > maxLen {
+		v = v[:maxLen]
+	}
+	if v == "" {
+		return "-"
+	}
+	b := []byte(v)
+	for i, c := range b {
+		if c < 33 || c > 126 {
+			b[i] = '_'
+		}
+	}
+	return string(b)
+}
+
+// newJournaldFramer 创建 journald 原生协议编码函数
+// 查询参数 tag 指定 SYSLOG_IDENTIFIER；PRIORITY 取自记录的级别，
+// MESSAGE 为记录的消息，格式化后的整行（含属性）放在 RECORD 字段
+func newJournaldFramer(query url.Values) recordFramer {
+	tag := query.Get("tag")
+	if tag == "" {
+		tag = processName()
+	}
+
+	return func(level slog.Level, msg string, line []byte) []byte {
+		record := bytes.TrimRight(line, "\n")
+		priority := strconv.Itoa(syslogSeverity(level))
+
+		b := make([]byte, 0, len(msg)+len(record)+64)
+		b = appendJournaldField(b, "PRIORITY", []byte(priority))
+		b = appendJournaldField(b, "SYSLOG_IDENTIFIER", []byte(tag))
+		b = appendJournaldField(b, "MESSAGE", []byte(msg))
+		return appendJournaldField(b, "RECORD", record)
+	}
+}
+
+// appendJournaldField 追加一个 journald 字段
+// 值包含换行时使用二进制格式：KEY\n<uint64 LE 长度><值>\n
+func appendJournaldField(b []byte, key string, value []byte) []byte {
+	b = append(b, key...)
+	if !bytes.ContainsRune(value, '\n') {
+		b = append(b, '=')
+		b = append(b, value...)
+		return append(b, '\n')
+	}
+	b = append(b, '\n')
+	b = binary.LittleEndian.AppendUint64(b, uint64(len(value)))
+	b = append(b, value...)
+	return append(b, '\n')
+}
+
+// syslogSeverity slog 级别映射为 syslog severity
+func syslogSeverity(level slog.Level) int {
+	switch {
+	case level >= slog.LevelError:
+		return 3 // err
+	case level >= slog.LevelWarn:
+		return 4 // warning
+	case level >= slog.LevelInfo:
+		return 6 // info
+	default:
+		return 7 // debug
+	}
+}
+
+// processName 当前进程名
+func processName() string {
+	if len(os.Args) > 0 && os.Args[0] != "" {
+		return filepath.Base(os.Args[0])
+	}
+	return "-"
+}
@@ -0,0 +1,83 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+	"time"
+)
+
+// DefaultSlowThreshold 默认慢操作阈值
+const DefaultSlowThreshold = time.Second
+
+// 计时记录的属性 key
+const (
+	KeyDuration  = "duration"  // 操作耗时
+	KeyThreshold = "threshold" // 慢操作阈值，仅在超过阈值时输出
+)
+
+// DoneFunc 结束计时并输出记录，由 Timed 返回
+type DoneFunc func(err error)
+
+// Timed 开始计时，返回的 DoneFunc 在操作结束时调用
+//
+// 记录以 op 为消息，附带 attrs 与 duration：
+// err 非 nil 时使用 error 级别并附带 error 属性，耗时达到慢操作阈值时使用 warn 级别，
+// 其余使用 debug 级别。调用位置为 DoneFunc 的调用处
+//
+//	done := logger.Timed(ctx, "db.query", "table", "users")
+//	rows, err := db.QueryContext(ctx, q)
+//	done(err)
+func (l *Logger) Timed(ctx context.Context, op string, attrs ...any) DoneFunc {
+	start := time.Now()
+	return func(err error) {
+		elapsed := time.Since(start)
+		threshold := l.SlowThreshold()
+
+		level := slog.LevelDebug
+		switch {
+		case err != nil:
+			level = slog.LevelError
+		case elapsed >= threshold:
+			level = slog.LevelWarn
+		}
+		if !l.Enabled(ctx, level) {
+			return
+		}
+
+		var pcs [1]uintptr
+		runtime.Callers(2, pcs[:]) // 跳过 runtime.Callers 与当前函数
+		r := slog.NewRecord(time.Now(), level, op, pcs[0])
+		r.Add(attrs...)
+		r.AddAttrs(slog.Duration(KeyDuration, elapsed))
+		if elapsed >= threshold {
+			r.AddAttrs(slog.Duration(KeyThreshold, threshold))
+		}
+		if err != nil {
+			r.AddAttrs(slog.Any(ErrorKey, err))
+		}
+		_ = l.Handler().Handle(ctx, r)
+	}
+}
+
+// SlowThreshold 返回 Timed 使用的慢操作阈值
+func (l *Logger) SlowThreshold() time.Duration {
+	if d := time.Duration(l.slowThreshold.Load()); d > 0 {
+		return d
+	}
+	return DefaultSlowThreshold
+}
+
+// SetSlowThreshold 设置 Timed 使用的慢操作阈值，d <= 0 时恢复 DefaultSlowThreshold
+func (l *Logger) SetSlowThreshold(d time.Duration) {
+	l.slowThreshold.Store(int64(max(d, 0)))
+}
+
+// replaceDuration 将 Duration 属性格式化为 time.Duration.String()（如 "1.5s"）
+// slog.JSONHandler 默认输出纳秒整数，与 text、console、otel 及 zap 适配器不一致
+func replaceDuration(_ []string, a slog.Attr) slog.Attr {
+	if a.Value.Kind() == slog.KindDuration {
+		a.Value = slog.StringValue(a.Value.Duration().String())
+	}
+	return a
+}
@@ -0,0 +1,146 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTimedLogger 创建输出 JSON 到缓冲区的 Logger
+func newTimedLogger(buf *bytes.Buffer, level slog.Level) *Logger {
+	return NewWithCleanup(slog.NewJSONHandler(buf, &slog.HandlerOptions{
+		Level:       level,
+		AddSource:   true,
+		ReplaceAttr: replaceDuration,
+	}), nil)
+}
+
+func TestTimed_Levels(t *testing.T) {
+	errQuery := errors.New("connection reset")
+
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		err       error
+		wantLevel string
+		wantKeys  []string
+	}{
+		{"fast", time.Hour, nil, "DEBUG", []string{KeyDuration}},
+		{"slow", time.Nanosecond, nil, "WARN", []string{KeyDuration, KeyThreshold}},
+		{"failed", time.Hour, errQuery, "ERROR", []string{KeyDuration, ErrorKey}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := newTimedLogger(&buf, slog.LevelDebug)
+			logger.SetSlowThreshold(tt.threshold)
+
+			done := logger.Timed(context.Background(), "db.query", "table", "users")
+			time.Sleep(time.Millisecond)
+			done(tt.err)
+
+			var m map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+				t.Fatalf("output is not JSON: %v (%s)", err, buf.String())
+			}
+			if m["level"] != tt.wantLevel {
+				t.Errorf("level = %v, want %s", m["level"], tt.wantLevel)
+			}
+			if m["msg"] != "db.query" || m["table"] != "users" {
+				t.Errorf("record = %v, want msg=db.query table=users", m)
+			}
+			for _, k := range tt.wantKeys {
+				if _, ok := m[k]; !ok {
+					t.Errorf("record should contain %q: %v", k, m)
+				}
+			}
+			if _, err := time.ParseDuration(m[KeyDuration].(string)); err != nil {
+				t.Errorf("duration = %v, want Duration string: %v", m[KeyDuration], err)
+			}
+		})
+	}
+}
+
+func TestTimed_SourceIsDoneCaller(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newTimedLogger(&buf, slog.LevelDebug)
+
+	logger.Timed(context.Background(), "op")(nil)
+
+	var m struct {
+		Source struct {
+			File string
+		}
+	}
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(m.Source.File) != "timed_test.go" {
+		t.Errorf("source file = %q, want timed_test.go", m.Source.File)
+	}
+}
+
+func TestTimed_Disabled(t *testing.T) {
+	var buf bytes.Buffer
+	logger := newTimedLogger(&buf, slog.LevelInfo)
+
+	logger.Timed(context.Background(), "fast op")(nil)
+	if buf.Len() != 0 {
+		t.Errorf("debug record should be filtered at info level, got: %s", buf.String())
+	}
+
+	logger.SetSlowThreshold(time.Nanosecond)
+	logger.Timed(context.Background(), "slow op")(nil)
+	if !strings.Contains(buf.String(), `"msg":"slow op"`) {
+		t.Errorf("slow op should be logged at warn, got: %s", buf.String())
+	}
+}
+
+func TestSlowThreshold_Default(t *testing.T) {
+	logger := NewNop()
+	if got := logger.SlowThreshold(); got != DefaultSlowThreshold {
+		t.Errorf("SlowThreshold() = %v, want %v", got, DefaultSlowThreshold)
+	}
+	logger.SetSlowThreshold(200 * time.Millisecond)
+	if got := logger.SlowThreshold(); got != 200*time.Millisecond {
+		t.Errorf("SlowThreshold() = %v, want 200ms", got)
+	}
+	logger.SetSlowThreshold(-1)
+	if got := logger.SlowThreshold(); got != DefaultSlowThreshold {
+		t.Errorf("SlowThreshold() after negative = %v, want %v", got, DefaultSlowThreshold)
+	}
+}
+
+func TestNew_SlowThresholdAndDurationFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	logger, err := New(Options{
+		Level:         LevelInfo,
+		Format:        FormatJSON,
+		Output:        path,
+		SlowThreshold: 250 * time.Millisecond,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if got := logger.SlowThreshold(); got != 250*time.Millisecond {
+		t.Errorf("SlowThreshold() = %v, want 250ms", got)
+	}
+
+	logger.Info("request", slog.Duration("latency", 1500*time.Millisecond))
+	_ = logger.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"latency":"1.5s"`) {
+		t.Errorf("JSON duration should be formatted as string, got: %s", data)
+	}
+}
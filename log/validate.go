@@ -0,0 +1,142 @@
+package log
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/chinayin/gox/config"
+)
+
+// 确保 Options 实现 config.Validatable，嵌入配置结构体后由 config.Loader 自动验证
+var _ config.Validatable = Options{}
+
+// Validate 验证配置，实现 config.Validatable
+// 拒绝未知的级别与格式，验证嵌套的 Async/Redact 配置，并检查输出目标可写：
+// 文件输出（展开路径模板后）要求文件可追加写入，文件不存在时要求最近的已存在上级目录可写；
+// URL 形式的输出只检查格式，不建立连接
+func (o Options) Validate() error {
+	if err := o.validateLevelFormat(); err != nil {
+		return err
+	}
+	if err := o.validateNested(); err != nil {
+		return err
+	}
+	return validateOutput(ExpandOutput(o.Output, o.AppName))
+}
+
+// validateLevelFormat 验证级别与格式，空值表示使用默认值
+func (o Options) validateLevelFormat() error {
+	if _, ok := slogLevelMap[o.Level]; !ok && o.Level != "" {
+		return fmt.Errorf("%w: %q (want debug, info, warn or error)", ErrInvalidLevel, o.Level)
+	}
+	switch o.Format {
+	case "", FormatJSON, FormatConsole, FormatText, FormatOTel:
+		return nil
+	default:
+		return fmt.Errorf("%w: %q (want json, console, text or otel)", ErrInvalidFormat, o.Format)
+	}
+}
+
+// validateNested 验证嵌套配置：异步溢出策略与遮蔽正则
+// 采样与去重配置的零值和负值均回退为默认值，无需验证
+func (o Options) validateNested() error {
+	if o.Async != nil {
+		if err := o.Async.validateOverflow(); err != nil {
+			return err
+		}
+	}
+	if o.Redact != nil {
+		if _, err := compilePatterns(o.Redact.Patterns); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateOutput 检查输出目标是否可写，不创建文件与目录
+func validateOutput(output string) error {
+	if isNetworkOutput(output) {
+		w, err := openNetworkOutput(output)
+		if err != nil {
+			return err
+		}
+		return w.Close()
+	}
+	if !isFileOutput(output) {
+		return nil
+	}
+
+	info, err := os.Stat(output)
+	switch {
+	case err == nil:
+		if info.IsDir() {
+			return fmt.Errorf("%w: %s (is a directory)", ErrOpenFile, output)
+		}
+		// #nosec G304 -- output 来自配置文件，由用户控制
+		f, err := os.OpenFile(output, os.O_APPEND|os.O_WRONLY, 0)
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrOpenFile, err)
+		}
+		return f.Close()
+	case !errors.Is(err, fs.ErrNotExist):
+		return fmt.Errorf("%w: %w", ErrOpenFile, err)
+	}
+
+	// 文件不存在：向上查找最近的已存在目录
+	dir := filepath.Dir(output)
+	for {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("%w: %s (%s is not a directory)", ErrOpenFile, output, dir)
+			}
+			break
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return fmt.Errorf("%w: %w", ErrOpenFile, err)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+
+	// 通过创建临时文件检查目录可写，兼容只读文件系统与权限不足
+	f, err := os.CreateTemp(dir, ".gox-log-*")
+	if err != nil {
+		return fmt.Errorf("%w: %s (directory %s is not writable: %w)", ErrOpenFile, output, dir, err)
+	}
+	return errors.Join(f.Close(), os.Remove(f.Name()))
+}
+
+// LoadOptions 从 config.Loader 的子 key 解析日志配置
+// 未配置的字段使用 DefaultOptions 的值，解析后执行 Validate
+func LoadOptions(loader *config.Loader, key string) (Options, error) {
+	opts := DefaultOptions()
+	if err := loader.GetViper().UnmarshalKey(key, &opts); err != nil {
+		return Options{}, fmt.Errorf("%w: %s (%w)", config.ErrUnmarshalFailed, key, err)
+	}
+	if err := opts.Validate(); err != nil {
+		return Options{}, fmt.Errorf("%w: %s (%w)", config.ErrValidationFailed, key, err)
+	}
+	return opts, nil
+}
+
+// NewFromConfig 从 config.Loader 的子 key 创建 Logger
+//
+//	loader := config.NewLoader()
+//	if err := loader.Load("config.yaml", &cfg); err != nil {
+//		return err
+//	}
+//	logger, err := log.NewFromConfig(loader, "log")
+func NewFromConfig(loader *config.Loader, key string) (*Logger, error) {
+	opts, err := LoadOptions(loader, key)
+	if err != nil {
+		return nil, err
+	}
+	return New(opts)
+}
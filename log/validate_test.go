@@ -0,0 +1,215 @@
+package log
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chinayin/gox/config"
+)
+
+func TestOptions_Validate(t *testing.T) {
+	dir := t.TempDir()
+	existing := filepath.Join(dir, "existing.log")
+	if err := os.WriteFile(existing, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    Options
+		wantErr error
+	}{
+		{"defaults", DefaultOptions(), nil},
+		{"empty", Options{}, nil},
+		{"all formats", Options{Level: LevelDebug, Format: FormatOTel, Output: OutputStderr}, nil},
+		{"unknown level", Options{Level: "inf"}, ErrInvalidLevel},
+		{"uppercase level", Options{Level: "INFO"}, ErrInvalidLevel},
+		{"unknown format", Options{Format: "yaml"}, ErrInvalidFormat},
+		{"async overflow", Options{Async: &AsyncOptions{Overflow: OverflowDropNewest}}, nil},
+		{"unknown async overflow", Options{Async: &AsyncOptions{Overflow: "dropoldest"}}, ErrInvalidOverflow},
+		{"redact patterns", Options{Redact: &RedactOptions{Patterns: []string{PatternEmail}}}, nil},
+		{"invalid redact pattern", Options{Redact: &RedactOptions{Patterns: []string{"("}}}, ErrInvalidPattern},
+		{"existing file", Options{Output: existing}, nil},
+		{"new file in new dir", Options{Output: filepath.Join(dir, "a", "b", "app.log")}, nil},
+		{"output is directory", Options{Output: dir}, ErrOpenFile},
+		{"parent is file", Options{Output: filepath.Join(existing, "app.log")}, ErrOpenFile},
+		{"network output", Options{Output: "udp://127.0.0.1:514"}, nil},
+		{"invalid network output", Options{Output: "tcp://"}, ErrInvalidOutput},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.opts.Validate()
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Errorf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Validate 不应创建文件或目录
+	if _, err := os.Stat(filepath.Join(dir, "a")); !os.IsNotExist(err) {
+		t.Errorf("Validate() should not create directories, stat error = %v", err)
+	}
+}
+
+func TestOptions_ValidateReadOnlyDir(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root ignores directory permissions")
+	}
+	dir := filepath.Join(t.TempDir(), "ro")
+	if err := os.Mkdir(dir, 0o500); err != nil {
+		t.Fatal(err)
+	}
+
+	err := Options{Output: filepath.Join(dir, "app.log")}.Validate()
+	if !errors.Is(err, ErrOpenFile) || !strings.Contains(err.Error(), "not writable") {
+		t.Errorf("Validate() error = %v, want %v with reason", err, ErrOpenFile)
+	}
+}
+
+func TestNew_RejectsUnknownLevelAndFormat(t *testing.T) {
+	if _, err := New(Options{Level: "dbug", Output: OutputStdout}); !errors.Is(err, ErrInvalidLevel) {
+		t.Errorf("New() error = %v, want %v", err, ErrInvalidLevel)
+	}
+	if _, err := New(Options{Format: "xml", Output: OutputStdout}); !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("New() error = %v, want %v", err, ErrInvalidFormat)
+	}
+}
+
+// writeConfig 写入临时配置文件并加载
+func writeConfig(t *testing.T, content string) *config.Loader {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	var cfg struct{}
+	loader := config.NewLoader(config.WithoutEnv())
+	if err := loader.Load(path, &cfg); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	return loader
+}
+
+func TestLoadOptions(t *testing.T) {
+	loader := writeConfig(t, `
+log:
+  level: debug
+  format: json
+  add_caller: false
+  expand_errors: true
+  app_name: demo
+  redact:
+    patterns: ["\\d{16}"]
+    mask: "***"
+  sampling:
+    tick: 2s
+    initial: 10
+    thereafter: 100
+  async:
+    buffer_size: 64
+    overflow: drop_oldest
+    close_timeout: 1s
+`)
+
+	opts, err := LoadOptions(loader, "log")
+	if err != nil {
+		t.Fatalf("LoadOptions() error = %v", err)
+	}
+	if opts.Level != LevelDebug || opts.Format != FormatJSON || opts.AddCaller || !opts.ExpandErrors {
+		t.Errorf("LoadOptions() = %+v, want level=debug format=json add_caller=false expand_errors=true", opts)
+	}
+	if opts.Output != OutputStdout {
+		t.Errorf("Output = %q, want default %q", opts.Output, OutputStdout)
+	}
+	if opts.AppName != "demo" {
+		t.Errorf("AppName = %q, want demo", opts.AppName)
+	}
+	if opts.Redact == nil || opts.Redact.Mask != "***" || len(opts.Redact.Patterns) != 1 {
+		t.Errorf("Redact = %+v, want mask *** with one pattern", opts.Redact)
+	}
+	if opts.Sampling == nil || opts.Sampling.Tick != 2*time.Second || opts.Sampling.Thereafter != 100 {
+		t.Errorf("Sampling = %+v, want tick 2s thereafter 100", opts.Sampling)
+	}
+	if opts.Async == nil || opts.Async.BufferSize != 64 || opts.Async.Overflow != OverflowDropOldest || opts.Async.CloseTimeout != time.Second {
+		t.Errorf("Async = %+v, want buffer_size 64 drop_oldest 1s", opts.Async)
+	}
+}
+
+func TestLoadOptions_Defaults(t *testing.T) {
+	loader := writeConfig(t, "app:\n  name: demo\n")
+
+	opts, err := LoadOptions(loader, "log")
+	if err != nil {
+		t.Fatalf("LoadOptions() error = %v", err)
+	}
+	want := DefaultOptions()
+	if opts.Level != want.Level || opts.Format != want.Format || opts.Output != want.Output || opts.AddCaller != want.AddCaller {
+		t.Errorf("LoadOptions() = %+v, want defaults %+v", opts, want)
+	}
+	if opts.Redact != nil || opts.Sampling != nil || opts.Async != nil {
+		t.Errorf("LoadOptions() should leave middlewares disabled, got %+v", opts)
+	}
+}
+
+func TestLoadOptions_Invalid(t *testing.T) {
+	loader := writeConfig(t, "log:\n  level: verbose\n")
+
+	_, err := LoadOptions(loader, "log")
+	if !errors.Is(err, config.ErrValidationFailed) || !errors.Is(err, ErrInvalidLevel) {
+		t.Errorf("LoadOptions() error = %v, want %v and %v", err, config.ErrValidationFailed, ErrInvalidLevel)
+	}
+}
+
+func TestNewFromConfig(t *testing.T) {
+	logFile := filepath.Join(t.TempDir(), "app.log")
+	loader := writeConfig(t, "log:\n  format: text\n  output: "+logFile+"\n")
+
+	logger, err := NewFromConfig(loader, "log")
+	if err != nil {
+		t.Fatalf("NewFromConfig() error = %v", err)
+	}
+	logger.Info("from config")
+	_ = logger.Close()
+
+	if data := readFile(t, logFile); !strings.Contains(data, "msg=\"from config\"") {
+		t.Errorf("output should contain the record, got: %s", data)
+	}
+}
+
+func TestOptions_LoadWithTags(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	// struct tag 默认值与 snake_case key
+	var opts Options
+	if err := config.NewLoader(config.WithoutEnv()).Load(write("log.yaml", "format: json\nexpand_errors: true\n"), &opts); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if opts.Level != LevelInfo || opts.Format != FormatJSON || opts.Output != OutputStdout || !opts.AddCaller || !opts.ExpandErrors {
+		t.Errorf("Load() = %+v, want tag defaults with format=json expand_errors=true", opts)
+	}
+
+	// Loader 自动调用 Validate
+	var invalid Options
+	err := config.NewLoader(config.WithoutEnv()).Load(write("bad.yaml", "format: jsn\n"), &invalid)
+	if !errors.Is(err, config.ErrValidationFailed) || !errors.Is(err, ErrInvalidFormat) {
+		t.Errorf("Load() error = %v, want %v and %v", err, config.ErrValidationFailed, ErrInvalidFormat)
+	}
+}
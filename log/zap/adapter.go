@@ -2,8 +2,12 @@
 package zap
 
 import (
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 
+	"github.com/chinayin/gox/config"
 	"github.com/chinayin/gox/log"
 	"go.uber.org/zap"
 	"go.uber.org/zap/exp/zapslog"
@@ -12,56 +16,177 @@ import (
 
 // NewHandler 创建基于 zap 的 slog.Handler
 // 这是适配器，将 Options 转换为 zap Handler
+//
+// Deprecated: 返回的 Handler 无法释放输出资源（文件句柄不会关闭，
+// 异步模式下缓冲的记录在退出时丢失），请使用 NewHandlerWithCleanup 或 New
 func NewHandler(opts log.Options) (slog.Handler, error) {
-	// 1. 使用官方配置
-	var zapConfig zap.Config
-	if opts.Format == log.FormatJSON {
-		zapConfig = zap.NewProductionConfig()
-	} else {
-		zapConfig = zap.NewDevelopmentConfig()
+	handler, _, err := newHandler(opts)
+	return handler, err
+}
+
+// NewHandlerWithCleanup 创建基于 zap 的 slog.Handler 及其资源释放函数
+// cleanup 输出剩余的采样汇总、刷新 zap 缓冲并关闭输出，应在应用退出时调用：
+//
+//	handler, cleanup, err := zaplog.NewHandlerWithCleanup(opts)
+//	logger := log.NewWithCleanup(handler, cleanup)
+func NewHandlerWithCleanup(opts log.Options) (slog.Handler, func() error, error) {
+	return newHandler(opts)
+}
+
+// New 便捷函数：创建使用 zap 的 Logger
+// 返回的 Logger 需要在应用退出时调用 Close()，以同步 zap 缓冲并关闭输出
+func New(opts log.Options) (*log.Logger, error) {
+	handler, cleanup, err := newHandler(opts)
+	if err != nil {
+		return nil, err
+	}
+	l := log.NewWithCleanup(handler, cleanup)
+	l.SetSlowThreshold(opts.SlowThreshold)
+	return l, nil
+}
+
+// NewFromConfig 从 config.Loader 的子 key 创建使用 zap 的 Logger
+// 配置解析与验证规则同 log.LoadOptions
+func NewFromConfig(loader *config.Loader, key string) (*log.Logger, error) {
+	opts, err := log.LoadOptions(loader, key)
+	if err != nil {
+		return nil, err
+	}
+	return New(opts)
+}
+
+// newHandler 创建 Handler 及其资源释放函数
+func newHandler(opts log.Options) (slog.Handler, func() error, error) {
+	// 与 log.New 一致，拒绝未知的级别、格式与不可写的输出
+	if err := opts.Validate(); err != nil {
+		return nil, nil, err
+	}
+	// zap 编码器无法输出 OpenTelemetry 日志数据模型（resource、trace 上下文、
+	// severityNumber 与嵌套 attributes），需要该格式时请使用 log.New
+	if opts.Format == log.FormatOTel {
+		return nil, nil, fmt.Errorf("%w: %q (use log.New)", log.ErrUnsupportedFormat, opts.Format)
+	}
+
+	core, cleanup, err := newCore(opts)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// 2. 设置级别
-	zapConfig.Level = zap.NewAtomicLevelAt(parseLevel(opts.Level))
+	// 使用官方 zapslog 适配器，根据 AddCaller 配置启用 caller 信息，
+	// error 及以上级别附带堆栈，与原先 zap.AddStacktrace(zapcore.ErrorLevel) 一致。
+	// zap.Config 的 ErrorOutputPaths 与 Development 只作用于 *zap.Logger，
+	// 适配器仅使用其 Core，因此直接构建 Core 不改变输出
+	handler := zapslog.NewHandler(core,
+		zapslog.WithCaller(opts.AddCaller),
+		zapslog.AddStacktraceAt(slog.LevelError),
+	)
 
-	// 3. 设置输出
+	// 挂载与 log.New 一致的中间件（采样已由 zap core 完成）
+	opts.Sampling = nil
+	wrapped, err := log.WrapHandler(handler, opts)
+	if err != nil {
+		_ = cleanup()
+		return nil, nil, err
+	}
+	return wrapped, cleanup, nil
+}
+
+// newCore 根据 Options 构建 zapcore.Core
+// 返回的 cleanup 用于刷新缓冲并释放输出资源
+func newCore(opts log.Options) (zapcore.Core, func() error, error) {
+	// 1. 使用官方编码配置
+	var encoder zapcore.Encoder
+	switch opts.Format {
+	case log.FormatJSON:
+		encoder = zapcore.NewJSONEncoder(jsonEncoderConfig())
+	default:
+		encoder = zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig())
+	}
+
+	// 2. 设置输出
 	output := opts.Output
 	if output == "" {
 		output = log.OutputStdout
 	}
 
-	// 如果是文件路径，确保目录存在
-	if err := log.EnsureOutputDir(output); err != nil {
-		return nil, err
+	// 如果是文件路径，展开路径模板并按 FileMode/DirMode 创建目录与文件
+	opts.Output = output
+	w, closeOutput, err := log.OpenOutputWithOptions(opts)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	zapConfig.OutputPaths = []string{output}
-	zapConfig.ErrorOutputPaths = []string{output}
+	// 3. 设置级别并构建 Core
+	// syslog/journald 等输出需要条目的级别与消息，由 recordCore 逐条写出；
+	// BufferedWriteSyncer 会把多条记录合并为一次写入，因此这类输出不启用异步
+	level := zap.NewAtomicLevelAt(parseLevel(opts.Level))
+	var core zapcore.Core
+	var cleanup func() error
+	if rw, ok := w.(log.RecordWriter); ok {
+		core = newRecordCore(encoder, rw, level)
+		cleanup = closeOutput
+	} else {
+		core, cleanup = newWriterCore(encoder, w, closeOutput, level, opts)
+	}
 
-	// 4. 创建 zap logger，根据 AddCaller 配置决定是否添加 caller
-	zapOpts := []zap.Option{
-		zap.AddStacktrace(zapcore.ErrorLevel),
+	// 4. 设置 Options.Sampling 时使用其配置，关闭前输出剩余的丢弃汇总；
+	// 未设置时保持 zap 的默认行为：JSON（生产配置）采样，其他格式（开发配置）不采样
+	switch {
+	case opts.Sampling != nil:
+		var stop func()
+		core, stop = newSamplerCore(core, *opts.Sampling)
+		closeCore := cleanup
+		cleanup = func() error {
+			stop()
+			return closeCore()
+		}
+	case opts.Format == log.FormatJSON:
+		core = newDefaultSamplerCore(core)
 	}
-	if opts.AddCaller {
-		zapOpts = append(zapOpts, zap.AddCaller())
+
+	return core, cleanup, nil
+}
+
+// newWriterCore 构建写入 w 的 zapcore.Core，异步模式下使用 BufferedWriteSyncer
+// 返回的 cleanup 刷新缓冲后调用 closeOutput 释放输出
+func newWriterCore(enc zapcore.Encoder, w io.Writer, closeOutput func() error, level zapcore.LevelEnabler, opts log.Options) (zapcore.Core, func() error) {
+	ws := zapcore.Lock(zapcore.AddSync(w))
+	cleanup := func() error {
+		return errors.Join(syncOutput(ws, opts.Output), closeOutput())
 	}
 
-	zapLogger, err := zapConfig.Build(zapOpts...)
-	if err != nil {
-		return nil, err
+	// 仅使用 BufferBytes/FlushInterval；BufferSize、Overflow、CloseTimeout 不生效，
+	// BufferedWriteSyncer 缓冲满时同步写出，不会丢弃记录
+	if opts.Async != nil {
+		bws := &zapcore.BufferedWriteSyncer{
+			WS:            ws,
+			Size:          opts.Async.BufferBytes,
+			FlushInterval: opts.Async.FlushInterval,
+		}
+		ws = bws
+		cleanup = func() error {
+			return errors.Join(bws.Stop(), syncOutput(bws.WS, opts.Output), closeOutput())
+		}
 	}
 
-	// 5. 使用官方 zapslog 适配器，根据 AddCaller 配置启用 caller 信息
-	return zapslog.NewHandler(zapLogger.Core(), zapslog.WithCaller(opts.AddCaller)), nil
+	return zapcore.NewCore(enc, ws, level), cleanup
 }
 
-// New 便捷函数：创建使用 zap 的 Logger
-func New(opts log.Options) (*slog.Logger, error) {
-	handler, err := NewHandler(opts)
-	if err != nil {
-		return nil, err
+// jsonEncoderConfig 生产环境 JSON 编码配置
+// Duration 输出为 "1.5s"，与 log.New 的各格式一致（官方默认为浮点秒数）
+func jsonEncoderConfig() zapcore.EncoderConfig {
+	cfg := zap.NewProductionEncoderConfig()
+	cfg.EncodeDuration = zapcore.StringDurationEncoder
+	return cfg
+}
+
+// syncOutput 同步输出
+// stdout/stderr 在管道或终端上 Sync 会返回 EINVAL 等错误，忽略之
+func syncOutput(ws zapcore.WriteSyncer, output string) error {
+	if output == log.OutputStdout || output == log.OutputStderr {
+		return nil
 	}
-	return slog.New(handler), nil
+	return ws.Sync()
 }
 
 // zapLevelMap 日志级别映射表
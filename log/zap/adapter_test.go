@@ -1,10 +1,16 @@
 package zap
 
 import (
+	"errors"
+	"log/slog"
+	"net"
 	"os"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/chinayin/gox/log"
+	"go.uber.org/zap/exp/zapslog"
 	"go.uber.org/zap/zapcore"
 )
 
@@ -46,7 +52,7 @@ func TestNewHandler(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			handler, err := NewHandler(tt.opts)
+			handler, err := NewHandler(tt.opts) //nolint:staticcheck // SA1019: 已弃用的 API 仍需覆盖
 			if (err != nil) != tt.wantErr {
 				t.Errorf("NewHandler() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -145,7 +151,7 @@ func TestNewHandler_WithFileOutput(t *testing.T) {
 		Output: logFile,
 	}
 
-	handler, err := NewHandler(opts)
+	handler, err := NewHandler(opts) //nolint:staticcheck // SA1019: 已弃用的 API 仍需覆盖
 	if err != nil {
 		t.Fatalf("failed to create handler: %v", err)
 	}
@@ -162,3 +168,362 @@ func TestNewHandler_WithFileOutput(t *testing.T) {
 		t.Errorf("%s should be a directory", dirPath)
 	}
 }
+
+func TestNewHandler_WithRedact(t *testing.T) {
+	logFile := t.TempDir() + "/app.log"
+
+	handler, cleanup, err := NewHandlerWithCleanup(log.Options{
+		Level:  log.LevelInfo,
+		Format: log.FormatJSON,
+		Output: logFile,
+		Redact: &log.RedactOptions{},
+	})
+	if err != nil {
+		t.Fatalf("NewHandlerWithCleanup() error = %v", err)
+	}
+	t.Cleanup(func() { _ = cleanup() })
+
+	slog.New(handler).Info("login", "user", "alice", "password", "hunter2")
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if strings.Contains(string(data), "hunter2") {
+		t.Errorf("password should be masked, got: %s", data)
+	}
+	if !strings.Contains(string(data), `"user":"alice"`) {
+		t.Errorf("non-sensitive values should be kept, got: %s", data)
+	}
+}
+
+func TestNewHandler_WithSampling(t *testing.T) {
+	logFile := t.TempDir() + "/app.log"
+
+	handler, cleanup, err := NewHandlerWithCleanup(log.Options{
+		Level:    log.LevelInfo,
+		Format:   log.FormatJSON,
+		Output:   logFile,
+		Sampling: &log.SamplingOptions{Tick: time.Hour, Initial: 2, ReportInterval: 50 * time.Millisecond},
+	})
+	if err != nil {
+		t.Fatalf("NewHandlerWithCleanup() error = %v", err)
+	}
+	t.Cleanup(func() { _ = cleanup() })
+
+	logger := slog.New(handler)
+	for range 10 {
+		logger.Info("hot path")
+	}
+
+	// 之后不再有新记录，汇总由后台 goroutine 按间隔输出
+	var data []byte
+	deadline := time.Now().Add(time.Second)
+	for !strings.Contains(string(data), log.MsgSamplingDropped) && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+		data, _ = os.ReadFile(logFile)
+	}
+
+	if got := strings.Count(string(data), `"msg":"hot path"`); got != 2 {
+		t.Errorf("written records = %d, want 2", got)
+	}
+	if !strings.Contains(string(data), log.MsgSamplingDropped) || !strings.Contains(string(data), `"dropped":8`) {
+		t.Errorf("expected drop summary with dropped=8, got: %s", data)
+	}
+}
+
+func TestNew_DefaultSampling(t *testing.T) {
+	tests := []struct {
+		format string
+		want   int
+	}{
+		// JSON 沿用 zap 生产配置的采样：每秒前 100 条
+		{format: log.FormatJSON, want: 100},
+		// 开发配置不采样
+		{format: log.FormatConsole, want: 150},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			logFile := t.TempDir() + "/app.log"
+			logger, err := New(log.Options{Level: log.LevelInfo, Format: tt.format, Output: logFile})
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			for range 150 {
+				logger.Info("hot path")
+			}
+			if err := logger.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			data, err := os.ReadFile(logFile)
+			if err != nil {
+				t.Fatalf("failed to read log file: %v", err)
+			}
+			if got := strings.Count(string(data), "hot path"); got != tt.want {
+				t.Errorf("written records = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewHandlerWithCleanup(t *testing.T) {
+	logFile := t.TempDir() + "/app.log"
+
+	handler, cleanup, err := NewHandlerWithCleanup(log.Options{
+		Level:  log.LevelInfo,
+		Format: log.FormatJSON,
+		Output: logFile,
+		Async:  &log.AsyncOptions{FlushInterval: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("NewHandlerWithCleanup() error = %v", err)
+	}
+
+	slog.New(handler).Info("buffered message")
+	if err := cleanup(); err != nil {
+		t.Fatalf("cleanup() error = %v", err)
+	}
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(data), "buffered message") {
+		t.Errorf("cleanup() should flush buffered records, got: %s", data)
+	}
+}
+
+func TestNew_StacktraceOnError(t *testing.T) {
+	logFile := t.TempDir() + "/app.log"
+
+	logger, err := New(log.Options{Level: log.LevelInfo, Format: log.FormatJSON, Output: logFile})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	logger.Warn("warn message")
+	logger.Error("error message")
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("written records = %d, want 2", len(lines))
+	}
+	if strings.Contains(lines[0], `"stacktrace"`) {
+		t.Errorf("warn record should not carry a stacktrace, got: %s", lines[0])
+	}
+	if !strings.Contains(lines[1], `"stacktrace"`) {
+		t.Errorf("error record should carry a stacktrace, got: %s", lines[1])
+	}
+}
+
+func TestNewCore_WithAsync(t *testing.T) {
+	logFile := t.TempDir() + "/app.log"
+
+	core, cleanup, err := newCore(log.Options{
+		Level:  log.LevelInfo,
+		Format: log.FormatJSON,
+		Output: logFile,
+		Async:  &log.AsyncOptions{FlushInterval: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("newCore() error = %v", err)
+	}
+
+	logger := slog.New(zapslog.NewHandler(core))
+	logger.Info("buffered message")
+
+	data, _ := os.ReadFile(logFile)
+	if strings.Contains(string(data), "buffered message") {
+		t.Fatal("record should stay buffered before cleanup")
+	}
+
+	if err := cleanup(); err != nil {
+		t.Fatalf("cleanup() error = %v", err)
+	}
+	data, _ = os.ReadFile(logFile)
+	if !strings.Contains(string(data), "buffered message") {
+		t.Errorf("cleanup() should flush buffered records, got: %s", data)
+	}
+}
+
+func TestNew_Close(t *testing.T) {
+	logFile := t.TempDir() + "/app.log"
+
+	logger, err := New(log.Options{
+		Level:    log.LevelInfo,
+		Format:   log.FormatJSON,
+		Output:   logFile,
+		Async:    &log.AsyncOptions{FlushInterval: time.Hour},
+		Sampling: &log.SamplingOptions{Tick: time.Hour, Initial: 1, ReportInterval: time.Hour},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	logger.Info("buffered message")
+	logger.Info("buffered message")
+
+	if err := logger.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	if !strings.Contains(string(data), "buffered message") {
+		t.Errorf("Close() should flush buffered records, got: %s", data)
+	}
+	if !strings.Contains(string(data), `"dropped":1`) {
+		t.Errorf("Close() should flush sampling summary, got: %s", data)
+	}
+}
+
+func TestNewHandler_ExpandErrors(t *testing.T) {
+	logFile := t.TempDir() + "/app.log"
+
+	logger, err := New(log.Options{
+		Level:        log.LevelInfo,
+		Format:       log.FormatJSON,
+		Output:       logFile,
+		ExpandErrors: true,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	logger.Error("failed", "err", log.Wrap(log.ErrOpenFile, "rotate"))
+	_ = logger.Close()
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	for _, want := range []string{`"sentinels":["log.ErrOpenFile"]`, `"stack":[`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("output should contain %s, got: %s", want, data)
+		}
+	}
+}
+
+func TestNew_NetworkOutput(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket() error = %v", err)
+	}
+	defer conn.Close()
+
+	logger, err := New(log.Options{
+		Level:  log.LevelInfo,
+		Format: log.FormatJSON,
+		Output: "udp://" + conn.LocalAddr().String(),
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer logger.Close()
+
+	logger.Info("over udp")
+
+	buf := make([]byte, 4096)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	n, _, err := conn.ReadFrom(buf)
+	if err != nil {
+		t.Fatalf("ReadFrom() error = %v", err)
+	}
+	if !strings.Contains(string(buf[:n]), `"msg":"over udp"`) {
+		t.Errorf("unexpected record: %q", buf[:n])
+	}
+}
+
+func TestNew_SyslogSeverityFromEntry(t *testing.T) {
+	path := t.TempDir() + "/log.sock"
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: path, Net: "unixgram"})
+	if err != nil {
+		t.Skipf("unixgram not supported: %v", err)
+	}
+	defer conn.Close()
+
+	logger, err := New(log.Options{
+		Level:  log.LevelInfo,
+		Format: log.FormatConsole,
+		Output: "unix://" + path + "?facility=local0&tag=myapp",
+		Async:  &log.AsyncOptions{},
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer logger.Close()
+
+	// 消息中的级别文本不影响 severity，每条记录单独成报文
+	logger.Info("upstream said ERROR")
+	logger.Error("request failed")
+
+	buf := make([]byte, 4096)
+	for _, want := range []string{"<134>1 ", "<131>1 "} {
+		_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+		n, _, err := conn.ReadFrom(buf)
+		if err != nil {
+			t.Fatalf("ReadFrom() error = %v", err)
+		}
+		if !strings.HasPrefix(string(buf[:n]), want) {
+			t.Errorf("syslog message should start with %q, got: %q", want, buf[:n])
+		}
+	}
+}
+
+func TestNew_OTelFormatUnsupported(t *testing.T) {
+	logFile := t.TempDir() + "/app.log"
+
+	_, err := New(log.Options{Level: log.LevelInfo, Format: log.FormatOTel, Output: logFile})
+	if !errors.Is(err, log.ErrUnsupportedFormat) {
+		t.Fatalf("New() error = %v, want ErrUnsupportedFormat", err)
+	}
+	if _, statErr := os.Stat(logFile); !os.IsNotExist(statErr) {
+		t.Errorf("output should not be opened for an unsupported format, stat error = %v", statErr)
+	}
+}
+
+func TestNew_RejectsInvalidOptions(t *testing.T) {
+	if _, err := New(log.Options{Level: "verbose"}); !errors.Is(err, log.ErrInvalidLevel) {
+		t.Errorf("New() error = %v, want %v", err, log.ErrInvalidLevel)
+	}
+	if _, err := New(log.Options{Output: t.TempDir()}); !errors.Is(err, log.ErrOpenFile) {
+		t.Errorf("New() error = %v, want %v", err, log.ErrOpenFile)
+	}
+}
+
+func TestNew_TimedAndDurationFormat(t *testing.T) {
+	logFile := t.TempDir() + "/app.log"
+
+	logger, err := New(log.Options{
+		Level:         log.LevelInfo,
+		Format:        log.FormatJSON,
+		Output:        logFile,
+		SlowThreshold: time.Nanosecond,
+	})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	logger.Info("request", slog.Duration("latency", 1500*time.Millisecond))
+	logger.Timed(t.Context(), "db.query", "table", "users")(nil)
+	_ = logger.Close()
+
+	data, err := os.ReadFile(logFile)
+	if err != nil {
+		t.Fatalf("failed to read log file: %v", err)
+	}
+	// 与 log.New 一致：Duration 输出为字符串，慢操作使用 warn 级别
+	for _, want := range []string{`"latency":"1.5s"`, `"level":"warn"`, `"msg":"db.query"`, `"threshold":"1ns"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("output should contain %s, got: %s", want, data)
+		}
+	}
+}
@@ -16,20 +16,40 @@
 //		panic(err)
 //	}
 //
+//	defer logger.Close() // syncs zap and closes the output
+//
 //	logger.Info("application started", "port", 8080)
 //
-// # Using with log.NewWithHandler
+// # Using with log.NewWithCleanup
 //
 //	import (
 //		"github.com/chinayin/gox/log"
 //		zaplog "github.com/chinayin/gox/log/zap"
 //	)
 //
-//	handler, err := zaplog.NewHandler(log.DefaultOptions())
+//	handler, cleanup, err := zaplog.NewHandlerWithCleanup(log.DefaultOptions())
 //	if err != nil {
 //		panic(err)
 //	}
-//	logger := log.NewWithHandler(handler)
+//	logger := log.NewWithCleanup(handler, cleanup)
+//	defer logger.Close()
+//
+// NewHandler is deprecated: it cannot release the output.
+//
+// # Async Output
+//
+// Options.Async maps to zapcore.BufferedWriteSyncer: only BufferBytes and
+// FlushInterval apply. BufferSize, Overflow and CloseTimeout are ignored; the
+// syncer writes through when its buffer is full and never drops records.
+// Syslog and journald outputs are written one entry at a time, with the
+// severity and message taken from the entry, so Async does not apply to them.
+//
+// # Formats
+//
+// FormatJSON uses zap's production encoder; FormatConsole and FormatText use
+// the development console encoder. FormatOTel returns
+// log.ErrUnsupportedFormat: zap encoders cannot emit the OpenTelemetry log data
+// model, so use log.New for it.
 //
 // # Kubernetes Deployment
 //
@@ -0,0 +1,76 @@
+package zap
+
+import (
+	"log/slog"
+
+	"github.com/chinayin/gox/log"
+	"go.uber.org/zap/zapcore"
+)
+
+// recordCore 将编码结果连同级别与消息交给 log.RecordWriter 的 zapcore.Core
+// 用于 syslog/journald 等输出，使 severity 与 MESSAGE 取自日志条目而非格式化后的文本
+type recordCore struct {
+	zapcore.LevelEnabler
+	enc zapcore.Encoder
+	out log.RecordWriter
+}
+
+var _ zapcore.Core = (*recordCore)(nil)
+
+// newRecordCore 创建 recordCore
+func newRecordCore(enc zapcore.Encoder, out log.RecordWriter, enab zapcore.LevelEnabler) *recordCore {
+	return &recordCore{LevelEnabler: enab, enc: enc, out: out}
+}
+
+// Level 返回最低启用级别
+func (c *recordCore) Level() zapcore.Level {
+	return zapcore.LevelOf(c.LevelEnabler)
+}
+
+// With 派生附带字段的 Core
+func (c *recordCore) With(fields []zapcore.Field) zapcore.Core {
+	enc := c.enc.Clone()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	return &recordCore{LevelEnabler: c.LevelEnabler, enc: enc, out: c.out}
+}
+
+// Check 级别启用时登记该 Core
+func (c *recordCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(ent.Level) {
+		return ce.AddCore(ent, c)
+	}
+	return ce
+}
+
+// Write 编码条目并按条目的级别与消息写出
+func (c *recordCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	buf, err := c.enc.EncodeEntry(ent, fields)
+	if err != nil {
+		return err
+	}
+	defer buf.Free()
+
+	_, err = c.out.WriteRecord(slogLevel(ent.Level), ent.Message, buf.Bytes())
+	return err
+}
+
+// Sync 每条记录已直接写出，无需同步
+func (c *recordCore) Sync() error {
+	return nil
+}
+
+// slogLevel 将 zap 级别映射为 slog 级别，DPanic/Panic/Fatal 视为 error
+func slogLevel(l zapcore.Level) slog.Level {
+	switch {
+	case l <= zapcore.DebugLevel:
+		return slog.LevelDebug
+	case l == zapcore.InfoLevel:
+		return slog.LevelInfo
+	case l == zapcore.WarnLevel:
+		return slog.LevelWarn
+	default:
+		return slog.LevelError
+	}
+}
@@ -0,0 +1,110 @@
+package zap
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/chinayin/gox/log"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// newSamplerCore 使用 zapcore.NewSamplerWithOptions 包装 core，
+// 丢弃计数通过 SamplerHook 汇总，由后台 goroutine 按 ReportInterval 以 warn 记录写入原 core。
+// 返回的 stop 用于关闭时停止该 goroutine 并输出剩余的汇总
+func newSamplerCore(core zapcore.Core, opts log.SamplingOptions) (zapcore.Core, func()) {
+	tick := opts.Tick
+	if tick <= 0 {
+		tick = log.DefaultSamplingTick
+	}
+	interval := opts.ReportInterval
+	if interval <= 0 {
+		interval = log.DefaultSamplingReportInterval
+	}
+
+	r := &dropReporter{
+		core:     core,
+		interval: interval,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	r.lastReport.Store(time.Now().UnixNano())
+	go r.run()
+
+	sampler := zapcore.NewSamplerWithOptions(core, tick, opts.Initial, opts.Thereafter,
+		zapcore.SamplerHook(r.hook),
+	)
+	return sampler, r.close
+}
+
+// newDefaultSamplerCore 使用 zap 生产配置的默认采样（每秒同一消息前 100 条，之后每 100 条 1 条）
+// 与 zap.NewProductionConfig().Build 的行为一致，未设置 Options.Sampling 的 JSON 输出使用
+func newDefaultSamplerCore(core zapcore.Core) zapcore.Core {
+	cfg := zap.NewProductionConfig().Sampling
+	return zapcore.NewSamplerWithOptions(core, time.Second, cfg.Initial, cfg.Thereafter)
+}
+
+// dropReporter 统计采样丢弃数并定期输出汇总
+type dropReporter struct {
+	core     zapcore.Core
+	interval time.Duration
+
+	pending    atomic.Uint64
+	lastReport atomic.Int64
+
+	stop     chan struct{}
+	done     chan struct{}
+	stopOnce sync.Once
+}
+
+// hook 实现 zapcore.SamplerHook，在每次采样决策时调用
+func (r *dropReporter) hook(_ zapcore.Entry, dec zapcore.SamplingDecision) {
+	if dec&zapcore.LogDropped != 0 {
+		r.pending.Add(1)
+	}
+}
+
+// run 每隔汇总间隔输出一次丢弃汇总，直到 close
+func (r *dropReporter) run() {
+	defer close(r.done)
+
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.stop:
+			return
+		case now := <-ticker.C:
+			r.report(now)
+		}
+	}
+}
+
+// close 停止汇总 goroutine 并输出剩余的汇总，可重复调用
+func (r *dropReporter) close() {
+	r.stopOnce.Do(func() {
+		close(r.stop)
+		<-r.done
+		r.report(time.Now())
+	})
+}
+
+// report 输出上次汇总以来的丢弃汇总，没有丢弃时不输出
+// 仅由 run 与 close 调用，二者不会并发执行
+func (r *dropReporter) report(now time.Time) {
+	last := r.lastReport.Swap(now.UnixNano())
+	dropped := r.pending.Swap(0)
+	if dropped == 0 || !r.core.Enabled(zapcore.WarnLevel) {
+		return
+	}
+
+	_ = r.core.Write(zapcore.Entry{
+		Level:   zapcore.WarnLevel,
+		Time:    now,
+		Message: log.MsgSamplingDropped,
+	}, []zapcore.Field{
+		zap.Uint64("dropped", dropped),
+		zap.Duration("interval", time.Duration(now.UnixNano()-last)),
+	})
+}